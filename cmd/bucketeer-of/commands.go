@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// Flag types accepted by the --type flag.
+const (
+	typeBool   = "bool"
+	typeString = "string"
+	typeInt    = "int"
+	typeFloat  = "float"
+	typeObject = "object"
+)
+
+func evalCommand(ctx context.Context, args []string, stdout, stderr io.Writer, factory providerFactory) error {
+	var (
+		conn         connectionConfig
+		userConf     userConfig
+		flagType     string
+		defaultValue string
+		output       string
+	)
+	fs := newFlagSet("eval", "<flag>", stderr)
+	conn.register(fs)
+	userConf.register(fs)
+	fs.StringVar(&flagType, "type", typeString, "Flag type: bool, string, int, float or object")
+	fs.StringVar(&defaultValue, "default", "", "Default value returned on error")
+	fs.StringVar(&output, "output", outputTable, "Output format: table or json")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("exactly one flag is required")
+	}
+	evalCtx, err := userConf.evaluationContext()
+	if err != nil {
+		return err
+	}
+	spec := flagSpec{key: positional[0], flagType: flagType}
+	def, err := parseDefault(spec.flagType, defaultValue)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, conn.timeout)
+	defer cancel()
+	p, err := factory(ctx, conn)
+	if err != nil {
+		return fmt.Errorf("failed to create provider: %w", err)
+	}
+	defer func() { _ = p.ShutdownWithContext(ctx) }()
+
+	res, err := evaluate(ctx, p, spec, def, evalCtx)
+	if err != nil {
+		return err
+	}
+	return writeResults(stdout, output, []result{res})
+}
+
+func evalAllCommand(ctx context.Context, args []string, stdout, stderr io.Writer, factory providerFactory) error {
+	var (
+		conn     connectionConfig
+		userConf userConfig
+		flagType string
+		output   string
+	)
+	fs := newFlagSet("eval-all", "<flag>[:type]...", stderr)
+	conn.register(fs)
+	userConf.register(fs)
+	fs.StringVar(&flagType, "type", typeString, "Type of the flags without an explicit type")
+	fs.StringVar(&output, "output", outputTable, "Output format: table or json")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return errors.New("at least one flag is required")
+	}
+	evalCtx, err := userConf.evaluationContext()
+	if err != nil {
+		return err
+	}
+	specs := make([]flagSpec, 0, len(positional))
+	for _, arg := range positional {
+		specs = append(specs, parseFlagSpec(arg, flagType))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, conn.timeout)
+	defer cancel()
+	p, err := factory(ctx, conn)
+	if err != nil {
+		return fmt.Errorf("failed to create provider: %w", err)
+	}
+	defer func() { _ = p.ShutdownWithContext(ctx) }()
+
+	results := make([]result, 0, len(specs))
+	for _, spec := range specs {
+		def, err := parseDefault(spec.flagType, "")
+		if err != nil {
+			return err
+		}
+		res, err := evaluate(ctx, p, spec, def, evalCtx)
+		if err != nil {
+			return err
+		}
+		results = append(results, res)
+	}
+	return writeResults(stdout, output, results)
+}
+
+func trackCommand(ctx context.Context, args []string, stdout, stderr io.Writer, factory providerFactory) error {
+	var (
+		conn     connectionConfig
+		userConf userConfig
+		value    float64
+	)
+	fs := newFlagSet("track", "<goal>", stderr)
+	conn.register(fs)
+	userConf.register(fs)
+	fs.Float64Var(&value, "value", 0, "Value associated with the goal event")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("exactly one goal is required")
+	}
+	flatCtx, err := userConf.evaluationContext()
+	if err != nil {
+		return err
+	}
+	attrs := make(map[string]any, len(flatCtx))
+	for k, v := range flatCtx {
+		if k != openfeature.TargetingKey {
+			attrs[k] = v
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, conn.timeout)
+	defer cancel()
+	p, err := factory(ctx, conn)
+	if err != nil {
+		return fmt.Errorf("failed to create provider: %w", err)
+	}
+	p.Track(
+		ctx,
+		positional[0],
+		openfeature.NewEvaluationContext(userConf.id, attrs),
+		openfeature.NewTrackingEventDetails(value),
+	)
+	// Shutdown flushes the goal event
+	if err := p.ShutdownWithContext(ctx); err != nil {
+		return fmt.Errorf("failed to send goal event: %w", err)
+	}
+	fmt.Fprintf(stdout, "tracked goal %q for user %q\n", positional[0], userConf.id)
+	return nil
+}
+
+// flagSpec is a flag to evaluate with its type.
+type flagSpec struct {
+	key      string
+	flagType string
+}
+
+// parseFlagSpec parses a flag written as key or key:type.
+func parseFlagSpec(s, defaultType string) flagSpec {
+	if i := strings.LastIndex(s, ":"); i > 0 {
+		switch t := s[i+1:]; t {
+		case typeBool, typeString, typeInt, typeFloat, typeObject:
+			return flagSpec{key: s[:i], flagType: t}
+		}
+	}
+	return flagSpec{key: s, flagType: defaultType}
+}
+
+func parseDefault(flagType, s string) (any, error) {
+	switch flagType {
+	case typeBool:
+		if s == "" {
+			return false, nil
+		}
+		return strconv.ParseBool(s)
+	case typeString:
+		return s, nil
+	case typeInt:
+		if s == "" {
+			return int64(0), nil
+		}
+		return strconv.ParseInt(s, 10, 64)
+	case typeFloat:
+		if s == "" {
+			return float64(0), nil
+		}
+		return strconv.ParseFloat(s, 64)
+	case typeObject:
+		if s == "" {
+			return map[string]any{}, nil
+		}
+		var v any
+		if err := json.Unmarshal([]byte(s), &v); err != nil {
+			return nil, fmt.Errorf("invalid object default value: %w", err)
+		}
+		return v, nil
+	default:
+		return nil, fmt.Errorf("unknown flag type %q", flagType)
+	}
+}
+
+func evaluate(
+	ctx context.Context,
+	p openfeature.FeatureProvider,
+	spec flagSpec,
+	defaultValue any,
+	evalCtx openfeature.FlattenedContext,
+) (result, error) {
+	var (
+		value  any
+		detail openfeature.ProviderResolutionDetail
+	)
+	switch spec.flagType {
+	case typeBool:
+		res := p.BooleanEvaluation(ctx, spec.key, defaultValue.(bool), evalCtx)
+		value, detail = res.Value, res.ProviderResolutionDetail
+	case typeString:
+		res := p.StringEvaluation(ctx, spec.key, defaultValue.(string), evalCtx)
+		value, detail = res.Value, res.ProviderResolutionDetail
+	case typeInt:
+		res := p.IntEvaluation(ctx, spec.key, defaultValue.(int64), evalCtx)
+		value, detail = res.Value, res.ProviderResolutionDetail
+	case typeFloat:
+		res := p.FloatEvaluation(ctx, spec.key, defaultValue.(float64), evalCtx)
+		value, detail = res.Value, res.ProviderResolutionDetail
+	case typeObject:
+		res := p.ObjectEvaluation(ctx, spec.key, defaultValue, evalCtx)
+		value, detail = res.Value, res.ProviderResolutionDetail
+	default:
+		return result{}, fmt.Errorf("unknown flag type %q", spec.flagType)
+	}
+	return newResult(spec, value, detail), nil
+}
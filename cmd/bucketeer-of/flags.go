@@ -0,0 +1,128 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+const defaultTimeout = 10 * time.Second
+
+// connectionConfig holds the Bucketeer connection flags shared by all commands.
+type connectionConfig struct {
+	apiKey                string
+	apiEndpoint           string
+	tag                   string
+	scheme                string
+	enableLocalEvaluation bool
+	timeout               time.Duration
+	// dryRun evaluates the flags without sending events, set by the commands that must not affect the analytics
+	dryRun bool
+}
+
+func (c *connectionConfig) register(fs *flag.FlagSet) {
+	fs.StringVar(&c.apiKey, "api-key", os.Getenv("BUCKETEER_API_KEY"), "Bucketeer API key")
+	fs.StringVar(&c.apiEndpoint, "api-endpoint", os.Getenv("BUCKETEER_API_ENDPOINT"),
+		"Bucketeer API endpoint, e.g. api.example.com")
+	fs.StringVar(&c.tag, "tag", os.Getenv("BUCKETEER_TAG"), "Bucketeer tag")
+	fs.StringVar(&c.scheme, "scheme", envOrDefault("BUCKETEER_SCHEME", "https"), "Scheme of the Bucketeer service")
+	fs.BoolVar(&c.enableLocalEvaluation, "local-evaluation", false, "Evaluate flags locally using cached flags")
+	fs.DurationVar(&c.timeout, "timeout", defaultTimeout, "Timeout of the command")
+}
+
+func (c *connectionConfig) validate() error {
+	if c.apiKey == "" {
+		return errors.New("API key is required, set --api-key or BUCKETEER_API_KEY")
+	}
+	if c.apiEndpoint == "" {
+		return errors.New("API endpoint is required, set --api-endpoint or BUCKETEER_API_ENDPOINT")
+	}
+	return nil
+}
+
+// userConfig holds the flags describing the evaluated user.
+type userConfig struct {
+	id    string
+	attrs attributes
+}
+
+func (c *userConfig) register(fs *flag.FlagSet) {
+	fs.StringVar(&c.id, "user", "", "User ID used as the targeting key")
+	fs.Var(&c.attrs, "attr", "User attribute as key=value, can be repeated")
+}
+
+func (c *userConfig) evaluationContext() (openfeature.FlattenedContext, error) {
+	if c.id == "" {
+		return nil, errors.New("--user is required")
+	}
+	evalCtx := openfeature.FlattenedContext{}
+	for k, v := range c.attrs {
+		evalCtx[k] = v
+	}
+	evalCtx[openfeature.TargetingKey] = c.id
+	return evalCtx, nil
+}
+
+// attributes is a repeatable key=value flag.
+type attributes map[string]string
+
+func (a *attributes) String() string {
+	pairs := make([]string, 0, len(*a))
+	for k, v := range *a {
+		pairs = append(pairs, k+"="+v)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (a *attributes) Set(s string) error {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("invalid attribute %q, expected key=value", s)
+	}
+	if *a == nil {
+		*a = attributes{}
+	}
+	(*a)[key] = value
+	return nil
+}
+
+// newFlagSet creates a flag set for a command, writing its usage to w.
+func newFlagSet(name, args string, w io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(w)
+	fs.Usage = func() {
+		fmt.Fprintf(w, "Usage: bucketeer-of %s %s [flags]\n\nFlags:\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseArgs parses flags placed before, between or after the positional arguments,
+// and returns the positional arguments.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func envOrDefault(key, defaultValue string) string {
+	if v, ok := os.LookupEnv(key); ok {
+		return v
+	}
+	return defaultValue
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/bucketeer-io/openfeature-go-server-sdk/pkg/inspect"
+)
+
+// outputDOT is the Graphviz output format of the graph command
+const outputDOT = "dot"
+
+// flagInspector is implemented by the providers able to inspect their flags, like the dry-run provider
+type flagInspector interface {
+	Inspector() (*inspect.Inspector, bool)
+}
+
+func graphCommand(ctx context.Context, args []string, stdout, stderr io.Writer, factory providerFactory) error {
+	var (
+		conn   connectionConfig
+		output string
+	)
+	fs := newFlagSet("graph", "[flag]...", stderr)
+	conn.register(fs)
+	fs.StringVar(&output, "output", outputDOT, "Output format: dot or json")
+	flags, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if output != outputDOT && output != outputJSON {
+		return fmt.Errorf("unknown output format %q", output)
+	}
+
+	return withInspector(ctx, conn, factory, func(inspector *inspect.Inspector) error {
+		graph, err := inspector.Graph(flags...)
+		if err != nil {
+			return fmt.Errorf("failed to inspect the flags: %w", err)
+		}
+		if output == outputJSON {
+			encoder := json.NewEncoder(stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(graph)
+		}
+		return graph.WriteDOT(stdout)
+	})
+}
+
+// withInspector calls fn with the inspector of a dry-run provider
+func withInspector(
+	ctx context.Context,
+	conn connectionConfig,
+	factory providerFactory,
+	fn func(inspector *inspect.Inspector) error,
+) error {
+	// The flags are read from the cache of the dry-run SDK, which evaluates them locally
+	conn.dryRun = true
+	ctx, cancel := context.WithTimeout(ctx, conn.timeout)
+	defer cancel()
+	p, err := factory(ctx, conn)
+	if err != nil {
+		return fmt.Errorf("failed to create provider: %w", err)
+	}
+	defer func() { _ = p.ShutdownWithContext(ctx) }()
+
+	var inspector *inspect.Inspector
+	if fi, ok := p.(flagInspector); ok {
+		inspector, _ = fi.Inspector()
+	}
+	if inspector == nil {
+		return errors.New("the provider can't inspect the flags")
+	}
+	return fn(inspector)
+}
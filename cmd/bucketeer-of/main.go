@@ -0,0 +1,156 @@
+// Command bucketeer-of evaluates Bucketeer feature flags through the OpenFeature provider,
+// to quickly check what a user gets for a flag.
+//
+// Usage:
+//
+//	bucketeer-of eval <flag> --type bool --user <id> [--attr key=value]...
+//	bucketeer-of eval-all <flag>[:type]... --user <id> [--attr key=value]...
+//	bucketeer-of track <goal> --user <id> [--value 1.5] [--attr key=value]...
+//	bucketeer-of simulate <flag> --input users.csv [--format csv|jsonl] [--bucket attr[:width]]...
+//	bucketeer-of graph [flag]... [--output dot|json]
+//	bucketeer-of manifest [flag]... [--output yaml|json]
+//
+// simulate evaluates a flag for each evaluation context of the input file and reports the distribution
+// of the variants. The flags are polled and evaluated locally, and no event is sent to Bucketeer.
+//
+// graph exports the dependencies between the flags, through prerequisites and rules, as a Graphviz DOT graph
+// or as JSON. With flags, it exports the flags they depend on and the flags depending on them.
+//
+// manifest exports the flags as a flaggen manifest, to generate typed accessors with cmd/flaggen.
+//
+// The connection is configured with the --api-key, --api-endpoint, --tag and --scheme flags,
+// or the BUCKETEER_API_KEY, BUCKETEER_API_ENDPOINT, BUCKETEER_TAG and BUCKETEER_SCHEME environment variables.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer"
+	"github.com/open-feature/go-sdk/openfeature"
+
+	provider "github.com/bucketeer-io/openfeature-go-server-sdk/pkg"
+	"github.com/bucketeer-io/openfeature-go-server-sdk/pkg/dryrun"
+	"github.com/bucketeer-io/openfeature-go-server-sdk/pkg/inspect"
+)
+
+const usage = `Usage: bucketeer-of <command> [arguments]
+
+Commands:
+  eval <flag>              Evaluate a flag for a user
+  eval-all <flag>[:type]...  Evaluate several flags for a user
+  track <goal>             Report a goal event for a user
+  simulate <flag>          Report the variants of a flag for the users of a file
+  graph [flag]...          Export the dependencies between the flags
+  manifest [flag]...       Export the flags as a flaggen manifest
+
+Run "bucketeer-of <command> -h" for the command flags.
+`
+
+// flagProvider is the subset of provider.Provider used by the commands.
+type flagProvider interface {
+	openfeature.FeatureProvider
+	openfeature.Tracker
+	ShutdownWithContext(ctx context.Context) error
+}
+
+// providerFactory creates the provider used by a command.
+type providerFactory func(ctx context.Context, conf connectionConfig) (flagProvider, error)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:], os.Stdout, os.Stderr, newProvider)
+	stop()
+	os.Exit(code)
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer, factory providerFactory) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	var cmd func(context.Context, []string, io.Writer, io.Writer, providerFactory) error
+	switch args[0] {
+	case "eval":
+		cmd = evalCommand
+	case "eval-all":
+		cmd = evalAllCommand
+	case "track":
+		cmd = trackCommand
+	case "simulate":
+		cmd = simulateCommand
+	case "graph":
+		cmd = graphCommand
+	case "manifest":
+		cmd = manifestCommand
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+	if err := cmd(ctx, args[1:], stdout, stderr, factory); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		fmt.Fprintf(stderr, "bucketeer-of %s: %v\n", args[0], err)
+		return 1
+	}
+	return 0
+}
+
+func newProvider(ctx context.Context, conf connectionConfig) (flagProvider, error) {
+	if err := conf.validate(); err != nil {
+		return nil, err
+	}
+	if conf.dryRun {
+		return newDryRunProvider(ctx, conf)
+	}
+	return provider.NewProviderWithContext(ctx, provider.ProviderOptions{
+		bucketeer.WithAPIKey(conf.apiKey),
+		bucketeer.WithAPIEndpoint(conf.apiEndpoint),
+		bucketeer.WithTag(conf.tag),
+		bucketeer.WithScheme(conf.scheme),
+		bucketeer.WithEnableLocalEvaluation(conf.enableLocalEvaluation),
+		bucketeer.WithEventFlushSize(1),
+	})
+}
+
+// newDryRunProvider creates a provider evaluating the flags locally without sending events
+func newDryRunProvider(ctx context.Context, conf connectionConfig) (flagProvider, error) {
+	sdk, err := dryrun.NewSDK(dryrun.Config{
+		APIKey:      conf.apiKey,
+		APIEndpoint: conf.apiEndpoint,
+		Scheme:      conf.scheme,
+		Tag:         conf.tag,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := sdk.WaitReady(ctx); err != nil {
+		_ = sdk.Close(ctx)
+		return nil, err
+	}
+	p, err := provider.NewProviderFromSDK(sdk)
+	if err != nil {
+		return nil, err
+	}
+	return &dryRunProvider{Provider: p, sdk: sdk}, nil
+}
+
+// dryRunProvider is a provider evaluating the flags with a dry-run SDK, which can inspect the flags of its cache
+type dryRunProvider struct {
+	*provider.Provider
+	sdk *dryrun.SDK
+}
+
+// Inspector returns an inspector of the flags of the dry-run SDK's cache
+func (p *dryRunProvider) Inspector() (*inspect.Inspector, bool) {
+	return inspect.FromSDK(p.sdk)
+}
@@ -0,0 +1,346 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ftproto "github.com/bucketeer-io/bucketeer/v2/proto/feature"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	provider "github.com/bucketeer-io/openfeature-go-server-sdk/pkg"
+	"github.com/bucketeer-io/openfeature-go-server-sdk/pkg/inspect"
+)
+
+type trackedEvent struct {
+	name    string
+	evalCtx openfeature.EvaluationContext
+	value   float64
+}
+
+// fakeProvider serves a fixed variation for each flag
+type fakeProvider struct {
+	openfeature.NoopProvider
+	conf     connectionConfig
+	evalCtx  openfeature.FlattenedContext
+	tracked  []trackedEvent
+	shutdown bool
+}
+
+func (p *fakeProvider) BooleanEvaluation(
+	ctx context.Context,
+	flag string,
+	defaultValue bool,
+	evalCtx openfeature.FlattenedContext,
+) openfeature.BoolResolutionDetail {
+	p.evalCtx = evalCtx
+	if flag != "bool-flag" {
+		return openfeature.BoolResolutionDetail{
+			Value: defaultValue,
+			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+				ResolutionError: openfeature.NewFlagNotFoundResolutionError("ERROR_FLAG_NOT_FOUND"),
+				Reason:          openfeature.ErrorReason,
+			},
+		}
+	}
+	return openfeature.BoolResolutionDetail{
+		Value: true,
+		ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+			Reason:  openfeature.TargetingMatchReason,
+			Variant: "on",
+			FlagMetadata: openfeature.FlagMetadata{
+				provider.FlagMetadataFeatureVersion: int64(4),
+			},
+		},
+	}
+}
+
+func (p *fakeProvider) StringEvaluation(
+	ctx context.Context,
+	flag string,
+	defaultValue string,
+	evalCtx openfeature.FlattenedContext,
+) openfeature.StringResolutionDetail {
+	p.evalCtx = evalCtx
+	return openfeature.StringResolutionDetail{
+		Value: "value-1",
+		ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+			Reason:  openfeature.DefaultReason,
+			Variant: "variation-1",
+		},
+	}
+}
+
+func (p *fakeProvider) Track(
+	ctx context.Context,
+	trackingEventName string,
+	evalCtx openfeature.EvaluationContext,
+	details openfeature.TrackingEventDetails,
+) {
+	p.tracked = append(p.tracked, trackedEvent{name: trackingEventName, evalCtx: evalCtx, value: details.Value()})
+}
+
+func (p *fakeProvider) ShutdownWithContext(ctx context.Context) error {
+	p.shutdown = true
+	return nil
+}
+
+// fakeFlags are the flags of the fake provider's cache: checkout depends on payments
+type fakeFlags struct{}
+
+func (fakeFlags) Features() ([]*ftproto.Feature, error) {
+	return []*ftproto.Feature{
+		{
+			Id:            "checkout",
+			Enabled:       true,
+			Prerequisites: []*ftproto.Prerequisite{{FeatureId: "payments", VariationId: "variation-on"}},
+		},
+		{
+			Id:            "payments",
+			Description:   "Enables the payments",
+			Enabled:       true,
+			VariationType: ftproto.Feature_BOOLEAN,
+			Variations: []*ftproto.Variation{
+				{Id: "variation-on", Name: "on", Value: "true"},
+				{Id: "variation-off", Name: "off", Value: "false"},
+			},
+			OffVariation: "variation-off",
+		},
+		{Id: "search", Enabled: true},
+	}, nil
+}
+
+func (p *fakeProvider) Inspector() (*inspect.Inspector, bool) {
+	return inspect.New(fakeFlags{}), true
+}
+
+func runWithFake(t *testing.T, args ...string) (*fakeProvider, int, string, string) {
+	t.Helper()
+	fake := &fakeProvider{}
+	factory := func(ctx context.Context, conf connectionConfig) (flagProvider, error) {
+		fake.conf = conf
+		return fake, nil
+	}
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), args, &stdout, &stderr, factory)
+	return fake, code, stdout.String(), stderr.String()
+}
+
+func TestEval(t *testing.T) {
+	t.Parallel()
+	fake, code, stdout, stderr := runWithFake(t,
+		"eval", "bool-flag", "--type", "bool", "--user", "user-1", "--attr", "plan=pro", "--output", "json",
+	)
+
+	require.Equal(t, 0, code, stderr)
+	assert.JSONEq(t, `{
+		"flag": "bool-flag",
+		"type": "bool",
+		"value": true,
+		"variant": "on",
+		"reason": "TARGETING_MATCH",
+		"featureVersion": 4
+	}`, stdout)
+	assert.Equal(t, openfeature.FlattenedContext{
+		openfeature.TargetingKey: "user-1",
+		"plan":                   "pro",
+	}, fake.evalCtx)
+	assert.True(t, fake.shutdown)
+}
+
+func TestEvalTable(t *testing.T) {
+	t.Parallel()
+	_, code, stdout, stderr := runWithFake(t, "eval", "--user", "user-1", "--type", "bool", "missing-flag")
+
+	require.Equal(t, 0, code, stderr)
+	assert.Equal(t,
+		"FLAG          TYPE  VALUE  VARIANT  REASON  VERSION  ERROR\n"+
+			"missing-flag  bool  false  -        ERROR   -        FLAG_NOT_FOUND: ERROR_FLAG_NOT_FOUND\n",
+		stdout,
+	)
+}
+
+func TestEvalAll(t *testing.T) {
+	t.Parallel()
+	_, code, stdout, stderr := runWithFake(t,
+		"eval-all", "bool-flag:bool", "string-flag", "--user", "user-1", "--output", "json",
+	)
+
+	require.Equal(t, 0, code, stderr)
+	var results []result
+	require.NoError(t, json.Unmarshal([]byte(stdout), &results))
+	require.Len(t, results, 2)
+	assert.Equal(t, "bool-flag", results[0].Flag)
+	assert.Equal(t, true, results[0].Value)
+	assert.Equal(t, "string-flag", results[1].Flag)
+	assert.Equal(t, typeString, results[1].Type)
+	assert.Equal(t, "value-1", results[1].Value)
+}
+
+func TestTrack(t *testing.T) {
+	t.Parallel()
+	fake, code, stdout, stderr := runWithFake(t, "track", "purchase", "--user", "user-1", "--value", "9.5")
+
+	require.Equal(t, 0, code, stderr)
+	assert.Equal(t, "tracked goal \"purchase\" for user \"user-1\"\n", stdout)
+	require.Len(t, fake.tracked, 1)
+	assert.Equal(t, "purchase", fake.tracked[0].name)
+	assert.Equal(t, "user-1", fake.tracked[0].evalCtx.TargetingKey())
+	assert.Equal(t, 9.5, fake.tracked[0].value)
+	assert.True(t, fake.shutdown)
+}
+
+func TestSimulate(t *testing.T) {
+	t.Parallel()
+	input := filepath.Join(t.TempDir(), "users.csv")
+	require.NoError(t, os.WriteFile(input, []byte("targetingKey,plan\nuser-1,pro\nuser-2,free\nuser-3,pro\n"), 0o600))
+
+	fake, code, stdout, stderr := runWithFake(t, "simulate", "string-flag", "--input", input, "--bucket", "plan")
+
+	require.Equal(t, 0, code, stderr)
+	assert.True(t, fake.conf.dryRun)
+	assert.True(t, fake.shutdown)
+	assert.Equal(t,
+		"FLAG string-flag, 3 contexts\n"+
+			"\n"+
+			"VARIANT      COUNT  SHARE\n"+
+			"variation-1  3      100.0%\n"+
+			"\n"+
+			"REASON   VARIANT      COUNT  SHARE\n"+
+			"DEFAULT  variation-1  3      100.0%\n"+
+			"\n"+
+			"PLAN  VARIANT      COUNT  SHARE\n"+
+			"free  variation-1  1      100.0%\n"+
+			"pro   variation-1  2      100.0%\n",
+		stdout,
+	)
+}
+
+func TestSimulateJSON(t *testing.T) {
+	t.Parallel()
+	input := filepath.Join(t.TempDir(), "users.jsonl")
+	require.NoError(t, os.WriteFile(input, []byte(`{"targetingKey":"user-1","age":34}`+"\n"), 0o600))
+
+	_, code, stdout, stderr := runWithFake(t,
+		"simulate", "string-flag", "--input", input, "--bucket", "age:10", "--output", "json",
+	)
+
+	require.Equal(t, 0, code, stderr)
+	assert.JSONEq(t, `{
+		"flag": "string-flag",
+		"total": 1,
+		"variants": {"variation-1": 1},
+		"reasons": {"DEFAULT": {"variation-1": 1}},
+		"buckets": {"age": {"[30,40)": {"variation-1": 1}}}
+	}`, stdout)
+}
+
+func TestGraph(t *testing.T) {
+	t.Parallel()
+	fake, code, stdout, stderr := runWithFake(t, "graph")
+
+	require.Equal(t, 0, code, stderr)
+	assert.True(t, fake.conf.dryRun)
+	assert.True(t, fake.shutdown)
+	assert.Equal(t, `digraph flags {
+  node [shape=box];
+  "checkout" [label="checkout"];
+  "payments" [label="payments"];
+  "search" [label="search"];
+  "checkout" -> "payments" [label="prerequisite: on"];
+}
+`, stdout)
+}
+
+func TestGraphJSON(t *testing.T) {
+	t.Parallel()
+	_, code, stdout, stderr := runWithFake(t, "graph", "payments", "--output", "json")
+
+	require.Equal(t, 0, code, stderr)
+	assert.JSONEq(t, `{
+		"nodes": [
+			{"id": "checkout", "enabled": true},
+			{"id": "payments", "enabled": true}
+		],
+		"edges": [
+			{"from": "checkout", "to": "payments", "kind": "prerequisite", "variations": ["on"]}
+		]
+	}`, stdout)
+}
+
+func TestManifest(t *testing.T) {
+	t.Parallel()
+	fake, code, stdout, stderr := runWithFake(t, "manifest")
+
+	require.Equal(t, 0, code, stderr)
+	assert.True(t, fake.conf.dryRun)
+	assert.True(t, fake.shutdown)
+	assert.Equal(t, `flags:
+  - key: checkout
+    type: string
+    default: ""
+  - key: payments
+    type: bool
+    default: false
+    description: Enables the payments
+  - key: search
+    type: string
+    default: ""
+`, stdout)
+}
+
+func TestManifestJSON(t *testing.T) {
+	t.Parallel()
+	_, code, stdout, stderr := runWithFake(t, "manifest", "payments", "--output", "json")
+
+	require.Equal(t, 0, code, stderr)
+	assert.JSONEq(t, `{
+		"flags": [
+			{"key": "payments", "type": "bool", "default": false, "description": "Enables the payments"}
+		]
+	}`, stdout)
+}
+
+func TestRunErrors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		desc         string
+		args         []string
+		expectedCode int
+	}{
+		{desc: "no command", args: nil, expectedCode: 2},
+		{desc: "unknown command", args: []string{"unknown"}, expectedCode: 2},
+		{desc: "missing user", args: []string{"eval", "bool-flag"}, expectedCode: 1},
+		{desc: "missing flag", args: []string{"eval", "--user", "user-1"}, expectedCode: 1},
+		{desc: "invalid attribute", args: []string{"eval", "bool-flag", "--user", "user-1", "--attr", "plan"}, expectedCode: 1},
+		{desc: "invalid default", args: []string{"eval", "bool-flag", "--user", "u", "--type", "int", "--default", "x"}, expectedCode: 1},
+		{desc: "unknown type", args: []string{"eval", "bool-flag", "--user", "user-1", "--type", "date"}, expectedCode: 1},
+		{desc: "missing input", args: []string{"simulate", "string-flag"}, expectedCode: 1},
+		{desc: "unknown input format", args: []string{"simulate", "string-flag", "--input", "users.xml"}, expectedCode: 1},
+		{desc: "invalid bucket", args: []string{"simulate", "string-flag", "--input", "u.csv", "--bucket", "age:x"}, expectedCode: 1},
+		{desc: "unknown graph output", args: []string{"graph", "--output", "svg"}, expectedCode: 1},
+		{desc: "unknown manifest output", args: []string{"manifest", "--output", "toml"}, expectedCode: 1},
+		{desc: "unknown manifest flag", args: []string{"manifest", "missing"}, expectedCode: 1},
+		{desc: "help", args: []string{"eval", "-h"}, expectedCode: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			_, code, _, _ := runWithFake(t, test.args...)
+			assert.Equal(t, test.expectedCode, code)
+		})
+	}
+}
+
+func TestParseFlagSpec(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, flagSpec{key: "flag", flagType: typeInt}, parseFlagSpec("flag:int", typeString))
+	assert.Equal(t, flagSpec{key: "flag", flagType: typeString}, parseFlagSpec("flag", typeString))
+	assert.Equal(t, flagSpec{key: "ns:flag", flagType: typeString}, parseFlagSpec("ns:flag", typeString))
+}
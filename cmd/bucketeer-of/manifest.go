@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/bucketeer-io/openfeature-go-server-sdk/pkg/flaggen"
+	"github.com/bucketeer-io/openfeature-go-server-sdk/pkg/inspect"
+)
+
+// outputYAML is the YAML output format of the manifest command
+const outputYAML = "yaml"
+
+func manifestCommand(ctx context.Context, args []string, stdout, stderr io.Writer, factory providerFactory) error {
+	var (
+		conn   connectionConfig
+		output string
+	)
+	fs := newFlagSet("manifest", "[flag]...", stderr)
+	conn.register(fs)
+	fs.StringVar(&output, "output", outputYAML, "Output format: yaml or json")
+	flags, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if output != outputYAML && output != outputJSON {
+		return fmt.Errorf("unknown output format %q", output)
+	}
+
+	return withInspector(ctx, conn, factory, func(inspector *inspect.Inspector) error {
+		summaries, err := inspectFlags(inspector, flags)
+		if err != nil {
+			return fmt.Errorf("failed to inspect the flags: %w", err)
+		}
+		m, err := flaggen.ManifestFromFlags(summaries)
+		if err != nil {
+			return err
+		}
+		if output == outputJSON {
+			encoder := json.NewEncoder(stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(m)
+		}
+		encoder := yaml.NewEncoder(stdout)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(m); err != nil {
+			return err
+		}
+		return encoder.Close()
+	})
+}
+
+// inspectFlags returns the summaries of the flags, or of all the flags if flags is empty
+func inspectFlags(inspector *inspect.Inspector, flags []string) ([]inspect.Flag, error) {
+	if len(flags) == 0 {
+		return inspector.Flags()
+	}
+	summaries := make([]inspect.Flag, 0, len(flags))
+	for _, id := range flags {
+		f, err := inspector.Flag(id)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, f)
+	}
+	return summaries, nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/open-feature/go-sdk/openfeature"
+
+	provider "github.com/bucketeer-io/openfeature-go-server-sdk/pkg"
+)
+
+// Output formats accepted by the --output flag.
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// result is the outcome of a flag evaluation.
+type result struct {
+	Flag           string `json:"flag"`
+	Type           string `json:"type"`
+	Value          any    `json:"value"`
+	Variant        string `json:"variant,omitempty"`
+	Reason         string `json:"reason"`
+	FeatureVersion *int64 `json:"featureVersion,omitempty"`
+	ErrorCode      string `json:"errorCode,omitempty"`
+	ErrorMessage   string `json:"errorMessage,omitempty"`
+}
+
+func newResult(spec flagSpec, value any, detail openfeature.ProviderResolutionDetail) result {
+	resolution := detail.ResolutionDetail()
+	res := result{
+		Flag:         spec.key,
+		Type:         spec.flagType,
+		Value:        value,
+		Variant:      resolution.Variant,
+		Reason:       string(resolution.Reason),
+		ErrorCode:    string(resolution.ErrorCode),
+		ErrorMessage: resolution.ErrorMessage,
+	}
+	if version, err := resolution.FlagMetadata.GetInt(provider.FlagMetadataFeatureVersion); err == nil {
+		res.FeatureVersion = &version
+	}
+	return res
+}
+
+func writeResults(w io.Writer, format string, results []result) error {
+	switch format {
+	case outputJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if len(results) == 1 {
+			return encoder.Encode(results[0])
+		}
+		return encoder.Encode(results)
+	case outputTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "FLAG\tTYPE\tVALUE\tVARIANT\tREASON\tVERSION\tERROR")
+		for _, res := range results {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				res.Flag,
+				res.Type,
+				formatValue(res.Value),
+				orDash(res.Variant),
+				res.Reason,
+				formatVersion(res.FeatureVersion),
+				formatError(res),
+			)
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return strconv.Quote(v)
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(b)
+	}
+}
+
+func formatVersion(version *int64) string {
+	if version == nil {
+		return "-"
+	}
+	return strconv.FormatInt(*version, 10)
+}
+
+func formatError(res result) string {
+	if res.ErrorCode == "" {
+		return "-"
+	}
+	if res.ErrorMessage == "" {
+		return res.ErrorCode
+	}
+	return res.ErrorCode + ": " + res.ErrorMessage
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
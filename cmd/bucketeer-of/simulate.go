@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/open-feature/go-sdk/openfeature"
+
+	"github.com/bucketeer-io/openfeature-go-server-sdk/pkg/simulation"
+)
+
+func simulateCommand(ctx context.Context, args []string, stdout, stderr io.Writer, factory providerFactory) error {
+	var (
+		conn    connectionConfig
+		input   string
+		format  string
+		buckets bucketList
+		output  string
+	)
+	fs := newFlagSet("simulate", "<flag>", stderr)
+	conn.register(fs)
+	fs.StringVar(&input, "input", "", "File of the evaluation contexts, one per row or line")
+	fs.StringVar(&format, "format", "", "Input format: csv or jsonl. Defaults to the file extension")
+	fs.Var(&buckets, "bucket", "Attribute grouping the results as attr or attr:width, can be repeated")
+	fs.StringVar(&output, "output", outputTable, "Output format: table or json")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("exactly one flag is required")
+	}
+	if input == "" {
+		return errors.New("--input is required")
+	}
+	if format == "" {
+		format = formatOf(input)
+	}
+	contexts, err := readContexts(input, format)
+	if err != nil {
+		return err
+	}
+
+	// The flags are evaluated without sending events, so the simulation doesn't affect the analytics
+	conn.dryRun = true
+	ctx, cancel := context.WithTimeout(ctx, conn.timeout)
+	defer cancel()
+	p, err := factory(ctx, conn)
+	if err != nil {
+		return fmt.Errorf("failed to create provider: %w", err)
+	}
+	defer func() { _ = p.ShutdownWithContext(ctx) }()
+
+	report := simulation.Run(ctx, p, positional[0], contexts, buckets...)
+	return writeReport(stdout, output, report)
+}
+
+// formatOf returns the input format matching the file extension
+func formatOf(path string) string {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".jsonl", ".ndjson":
+		return simulation.FormatJSONL
+	default:
+		return strings.TrimPrefix(ext, ".")
+	}
+}
+
+func readContexts(path, format string) ([]openfeature.FlattenedContext, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	contexts, err := simulation.ReadContexts(f, format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return contexts, nil
+}
+
+// bucketList is a repeatable attr[:width] flag.
+type bucketList []simulation.Bucket
+
+func (b *bucketList) String() string {
+	attrs := make([]string, 0, len(*b))
+	for _, bucket := range *b {
+		attrs = append(attrs, bucket.Attribute)
+	}
+	return strings.Join(attrs, ",")
+}
+
+func (b *bucketList) Set(s string) error {
+	bucket, err := simulation.ParseBucket(s)
+	if err != nil {
+		return err
+	}
+	*b = append(*b, bucket)
+	return nil
+}
+
+func writeReport(w io.Writer, format string, report *simulation.Report) error {
+	switch format {
+	case outputJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	case outputTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(tw, "FLAG %s, %d contexts\n\n", report.Flag, report.Total)
+		fmt.Fprintln(tw, "VARIANT\tCOUNT\tSHARE")
+		for _, variant := range sortedKeys(report.Variants) {
+			n := report.Variants[variant]
+			fmt.Fprintf(tw, "%s\t%d\t%s\n", variant, n, share(n, report.Total))
+		}
+		writeGroups(tw, "REASON", report.Reasons)
+		attrs := sortedKeys(report.Buckets)
+		for _, attr := range attrs {
+			writeGroups(tw, strings.ToUpper(attr), report.Buckets[attr])
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// writeGroups writes the variants of each group, with their share of the group
+func writeGroups(w io.Writer, title string, groups map[string]simulation.Counts) {
+	fmt.Fprintf(w, "\n%s\tVARIANT\tCOUNT\tSHARE\n", title)
+	for _, group := range sortedKeys(groups) {
+		counts := groups[group]
+		total := counts.Total()
+		for _, variant := range sortedKeys(counts) {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", group, variant, counts[variant], share(counts[variant], total))
+		}
+	}
+}
+
+func share(n, total int) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", float64(n)*100/float64(total))
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,88 @@
+// Command flaggen generates typed Go accessors for the flags of a manifest, see the flaggen package.
+//
+// Usage:
+//
+//	flaggen [-package flags] [-o flags_gen.go] flags.yaml
+//
+// The manifest is a YAML or JSON file listing the flags with their type, default value and description.
+// The package defaults to $GOPACKAGE, set by go generate, then to the name of the output directory.
+// Without -o, the code is written to stdout. Typically, it runs with go generate:
+//
+//	//go:generate go run github.com/bucketeer-io/openfeature-go-server-sdk/cmd/flaggen -o flags_gen.go flags.yaml
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/bucketeer-io/openfeature-go-server-sdk/pkg/flaggen"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("flaggen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: flaggen [-package flags] [-o flags_gen.go] flags.yaml")
+		fs.PrintDefaults()
+	}
+	pkg := fs.String("package", "", "the package of the generated file (default $GOPACKAGE or the output directory)")
+	output := fs.String("o", "", "write the generated code to the file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	if err := generate(fs.Arg(0), packageName(*pkg, *output), *output, stdout); err != nil {
+		fmt.Fprintf(stderr, "flaggen: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func generate(manifest, pkg, output string, stdout io.Writer) error {
+	m, err := flaggen.LoadManifest(manifest)
+	if err != nil {
+		return err
+	}
+	src, err := flaggen.Generate(m, pkg, filepath.Base(manifest))
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		_, err = stdout.Write(src)
+		return err
+	}
+	if err := os.WriteFile(output, src, 0o600); err != nil {
+		return fmt.Errorf("failed to write the generated code: %w", err)
+	}
+	return nil
+}
+
+// packageName returns the package of the generated file: pkg, $GOPACKAGE, the output directory, or flags
+func packageName(pkg, output string) string {
+	if pkg != "" {
+		return pkg
+	}
+	if pkg := os.Getenv("GOPACKAGE"); pkg != "" {
+		return pkg
+	}
+	if output != "" {
+		if dir, err := filepath.Abs(filepath.Dir(output)); err == nil {
+			return filepath.Base(dir)
+		}
+	}
+	return "flags"
+}
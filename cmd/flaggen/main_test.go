@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const manifest = `
+flags:
+  - key: new-checkout
+    type: bool
+    description: Enables the new checkout flow.
+`
+
+func writeManifest(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "flags.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(manifest), 0o600))
+	return path
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-package", "features", writeManifest(t)}, &stdout, &stderr)
+
+	require.Equal(t, 0, code, stderr.String())
+	assert.Contains(t, stdout.String(), "// Code generated by flaggen from flags.yaml. DO NOT EDIT.\n\npackage features\n")
+	assert.Contains(t, stdout.String(), "func NewCheckout(client openfeature.IClient) NewCheckoutFlag {")
+}
+
+func TestRunOutput(t *testing.T) {
+	t.Parallel()
+	dir := filepath.Join(t.TempDir(), "featureflags")
+	require.NoError(t, os.Mkdir(dir, 0o700))
+	output := filepath.Join(dir, "flags_gen.go")
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-o", output, writeManifest(t)}, &stdout, &stderr)
+
+	require.Equal(t, 0, code, stderr.String())
+	assert.Empty(t, stdout.String())
+	src, err := os.ReadFile(output)
+	require.NoError(t, err)
+	// Without $GOPACKAGE, the package is named after the output directory
+	assert.Contains(t, string(src), "\npackage featureflags\n")
+}
+
+func TestRunErrors(t *testing.T) {
+	t.Parallel()
+	invalid := filepath.Join(t.TempDir(), "invalid.yaml")
+	require.NoError(t, os.WriteFile(invalid, []byte("flags:\n  - {key: theme, type: date}\n"), 0o600))
+	tests := []struct {
+		desc     string
+		args     []string
+		expected int
+		stderr   string
+	}{
+		{desc: "help", args: []string{"-h"}, expected: 0, stderr: "Usage: flaggen"},
+		{desc: "missing manifest", args: nil, expected: 2, stderr: "Usage: flaggen"},
+		{desc: "unknown flag", args: []string{"-unknown"}, expected: 2, stderr: "flag provided but not defined"},
+		{desc: "manifest not found", args: []string{"missing.yaml"}, expected: 1, stderr: "failed to read the manifest"},
+		{desc: "invalid manifest", args: []string{invalid}, expected: 1, stderr: `unknown type "date"`},
+		{desc: "invalid package", args: []string{"-package", "a-b", invalid}, expected: 1, stderr: "invalid package"},
+	}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			var stdout, stderr bytes.Buffer
+			assert.Equal(t, test.expected, run(test.args, &stdout, &stderr))
+			assert.Empty(t, stdout.String())
+			assert.Contains(t, stderr.String(), test.stderr)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+// Command flaglint lists the flags evaluated by a Go codebase through OpenFeature,
+// to diff the flag keys used by the code against the flags of Bucketeer.
+//
+// Usage:
+//
+//	flaglint [-o inventory.json] [-tests] [packages...]
+//
+// It finds the calls to the evaluation methods of the OpenFeature clients and of the provider
+// in the packages, "./..." by default, and writes a JSON inventory of the flag keys, their types,
+// their default values and their call sites. It reports on stderr the flag keys that are not constants,
+// the flags evaluated with different types, and the flags evaluated with different default values.
+//
+// It exits with 1 if there are problems, so it can run in CI, and with 2 if the packages can't be analyzed.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/bucketeer-io/openfeature-go-server-sdk/pkg/flaglint"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("flaglint", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: flaglint [-o inventory.json] [-tests] [packages...]")
+		fs.PrintDefaults()
+	}
+	output := fs.String("o", "", "write the inventory to the file instead of stdout")
+	tests := fs.Bool("tests", false, "analyze the test files too")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	patterns := fs.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	inventory, err := analyze(patterns, *tests)
+	if err != nil {
+		fmt.Fprintf(stderr, "flaglint: %v\n", err)
+		return 2
+	}
+	if err := writeInventory(inventory, *output, stdout); err != nil {
+		fmt.Fprintf(stderr, "flaglint: %v\n", err)
+		return 2
+	}
+	for _, problem := range inventory.Problems {
+		fmt.Fprintln(stderr, problem)
+	}
+	if len(inventory.Problems) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// analyze loads the packages and aggregates their evaluations
+func analyze(patterns []string, tests bool) (flaglint.Inventory, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return flaglint.Inventory{}, err
+	}
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.LoadAllSyntax, Tests: tests}, patterns...)
+	if err != nil {
+		return flaglint.Inventory{}, fmt.Errorf("failed to load the packages: %w", err)
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		return flaglint.Inventory{}, errors.New("failed to load the packages")
+	}
+	graph, err := checker.Analyze([]*analysis.Analyzer{flaglint.Analyzer}, pkgs, nil)
+	if err != nil {
+		return flaglint.Inventory{}, err
+	}
+	var evaluations []flaglint.Evaluation
+	seen := make(map[string]bool)
+	for _, action := range graph.Roots {
+		if action.Err != nil {
+			return flaglint.Inventory{}, fmt.Errorf("failed to analyze %s: %w", action.Package.PkgPath, action.Err)
+		}
+		for _, e := range action.Result.([]flaglint.Evaluation) {
+			// With tests, the files of a package are also in its test variant
+			if pos := e.Position.String(); !seen[pos] {
+				seen[pos] = true
+				evaluations = append(evaluations, e)
+			}
+		}
+	}
+	return flaglint.NewInventory(evaluations, dir), nil
+}
+
+// writeInventory writes the inventory as indented JSON to the file, or to stdout if file is empty
+func writeInventory(inventory flaglint.Inventory, file string, stdout io.Writer) error {
+	data, err := json.MarshalIndent(inventory, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if file == "" {
+		_, err = stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(file, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write the inventory: %w", err)
+	}
+	return nil
+}
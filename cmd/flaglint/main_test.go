@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bucketeer-io/openfeature-go-server-sdk/pkg/flaglint"
+)
+
+// analyzedPackage evaluates a flag with a key that is not a constant
+const analyzedPackage = "github.com/bucketeer-io/openfeature-go-server-sdk/pkg/simulation"
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+	var stdout, stderr bytes.Buffer
+	code := run([]string{analyzedPackage}, &stdout, &stderr)
+
+	assert.Equal(t, 1, code)
+	var inventory flaglint.Inventory
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &inventory))
+	assert.Empty(t, inventory.Flags)
+	require.Len(t, inventory.NonConstantKeys, 1)
+	assert.Equal(t, "FeatureProvider.StringEvaluation", inventory.NonConstantKeys[0].Method)
+	assert.Equal(t, "flag", inventory.NonConstantKeys[0].KeyExpr)
+	require.Len(t, inventory.Problems, 1)
+	assert.Equal(t, flaglint.ProblemNonConstantKey, inventory.Problems[0].Kind)
+	assert.Contains(t, stderr.String(), "simulation.go:")
+	assert.Contains(t, stderr.String(), "flag key of FeatureProvider.StringEvaluation is not a constant: flag\n")
+}
+
+func TestRunOutput(t *testing.T) {
+	t.Parallel()
+	output := filepath.Join(t.TempDir(), "inventory.json")
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-o", output, analyzedPackage}, &stdout, &stderr)
+
+	assert.Equal(t, 1, code)
+	assert.Empty(t, stdout.String())
+	data, err := os.ReadFile(output)
+	require.NoError(t, err)
+	var inventory flaglint.Inventory
+	require.NoError(t, json.Unmarshal(data, &inventory))
+	assert.Len(t, inventory.NonConstantKeys, 1)
+}
+
+func TestRunNoProblem(t *testing.T) {
+	t.Parallel()
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"github.com/bucketeer-io/openfeature-go-server-sdk/pkg/version"}, &stdout, &stderr)
+
+	assert.Equal(t, 0, code)
+	assert.Empty(t, stderr.String())
+	assert.JSONEq(t, `{"flags": [], "nonConstantKeys": [], "problems": []}`, stdout.String())
+}
+
+func TestRunErrors(t *testing.T) {
+	t.Parallel()
+	patterns := []struct {
+		desc     string
+		args     []string
+		expected int
+		stderr   string
+	}{
+		{desc: "help", args: []string{"-h"}, expected: 0, stderr: "Usage: flaglint"},
+		{desc: "unknown flag", args: []string{"-unknown"}, expected: 2, stderr: "flag provided but not defined"},
+		{desc: "unknown package", args: []string{"./does-not-exist"}, expected: 2, stderr: "flaglint: "},
+	}
+	for _, p := range patterns {
+		t.Run(p.desc, func(t *testing.T) {
+			t.Parallel()
+			var stdout, stderr bytes.Buffer
+			assert.Equal(t, p.expected, run(p.args, &stdout, &stderr))
+			assert.Empty(t, stdout.String())
+			assert.Contains(t, stderr.String(), p.stderr)
+		})
+	}
+}
@@ -0,0 +1,197 @@
+// Command ofrep-server serves the OpenFeature Remote Evaluation Protocol (OFREP)
+// backed by the Bucketeer provider, so it can run as a sidecar next to any service.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer"
+	"github.com/open-feature/go-sdk/openfeature"
+
+	provider "github.com/bucketeer-io/openfeature-go-server-sdk/pkg"
+	"github.com/bucketeer-io/openfeature-go-server-sdk/pkg/debug"
+	"github.com/bucketeer-io/openfeature-go-server-sdk/pkg/dryrun"
+	"github.com/bucketeer-io/openfeature-go-server-sdk/pkg/ofrep"
+)
+
+const (
+	timeout = 10 * time.Second
+	// domain is the OpenFeature domain of the provider, whose client evaluates the OFREP requests
+	domain = "ofrep-server"
+)
+
+var (
+	addr                  = flag.String("addr", ":8016", "Address to listen on")
+	bucketeerTag          = flag.String("bucketeer-tag", "", "Bucketeer tag")
+	bucketeerAPIKey       = flag.String("bucketeer-api-key", "", "Bucketeer api key")
+	bucketeerAPIEndpoint  = flag.String("bucketeer-api-endpoint", "", "Bucketeer api endpoint, e.g. api.example.com")
+	scheme                = flag.String("scheme", "https", "Scheme of the Bucketeer service, e.g. https")
+	enableLocalEvaluation = flag.Bool("enable-local-evaluation", false, "Evaluate flags locally using cached flags")
+	bulkFlags             = flag.String("flags", "", "Comma-separated flag IDs evaluated by the bulk evaluation endpoint")
+	flagTypes             = flag.String("flag-types", "",
+		"Comma-separated flag types as id=type, type is boolean, string, number or json. Untyped flags are strings")
+	debugAddr = flag.String("debug-addr", "",
+		"Address serving the debug page at /debug/bucketeer/, disabled if empty")
+)
+
+func main() {
+	flag.Parse()
+
+	options := provider.ProviderOptions{
+		bucketeer.WithTag(*bucketeerTag),
+		bucketeer.WithAPIKey(*bucketeerAPIKey),
+		bucketeer.WithAPIEndpoint(*bucketeerAPIEndpoint),
+		bucketeer.WithScheme(*scheme),
+		bucketeer.WithEnableLocalEvaluation(*enableLocalEvaluation),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	var providerOptions []provider.Option
+	if *debugAddr != "" {
+		// The test evaluations of the debug page must not be reported to Bucketeer
+		dryRunSDK, err := newDryRunSDK(ctx)
+		if err != nil {
+			log.Fatalf("Failed to create dry-run SDK: %v", err)
+		}
+		providerOptions = append(providerOptions, provider.WithDryRunSDK(dryRunSDK))
+	}
+	p, err := provider.NewProviderWithContext(ctx, options, providerOptions...)
+	if err != nil {
+		log.Fatalf("Failed to create provider: %v", err)
+	}
+	// The OFREP requests are evaluated with an OpenFeature client, so they go through its hooks
+	if err := openfeature.SetNamedProviderWithContextAndWait(ctx, domain, p); err != nil {
+		log.Fatalf("Failed to set provider: %v", err)
+	}
+	cancel()
+	client := openfeature.NewClient(domain)
+
+	handlerOptions, err := typeOptions(*flagTypes)
+	if err != nil {
+		log.Fatalf("Invalid flag types: %v", err)
+	}
+	handlerOptions = append(handlerOptions, ofrep.WithFlags(splitFlags(*bulkFlags)...))
+
+	mux := http.NewServeMux()
+	mux.Handle("/ofrep/", ofrep.NewHandler(ofrep.NewClientEvaluator(client), handlerOptions...))
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	if *debugAddr != "" {
+		recorder := debug.NewRecorder(0)
+		client.AddHooks(recorder)
+		go serveDebug(p, recorder)
+	}
+
+	srv := &http.Server{
+		Addr:         *addr,
+		Handler:      mux,
+		ReadTimeout:  timeout,
+		WriteTimeout: timeout,
+	}
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sig
+		log.Println("Shutdown signal received")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Server shutdown error: %v", err)
+		}
+	}()
+
+	log.Printf("OFREP server starting on %s", *addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Printf("Server error: %v", err)
+	}
+
+	// Flush the pending evaluation events before exiting
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := p.ShutdownWithContext(shutdownCtx); err != nil {
+		log.Printf("Provider shutdown error: %v", err)
+	}
+}
+
+// serveDebug serves the debug page on its own address, so it's not exposed with the OFREP endpoints
+func serveDebug(p *provider.Provider, recorder *debug.Recorder) {
+	mux := http.NewServeMux()
+	mux.Handle("/debug/bucketeer/", debug.NewHandler(p,
+		debug.WithConfig(debug.Config{
+			Tag:                   *bucketeerTag,
+			APIEndpoint:           *bucketeerAPIEndpoint,
+			Scheme:                *scheme,
+			APIKey:                *bucketeerAPIKey,
+			EnableLocalEvaluation: *enableLocalEvaluation,
+		}),
+		debug.WithRecorder(recorder),
+	))
+	srv := &http.Server{
+		Addr:         *debugAddr,
+		Handler:      mux,
+		ReadTimeout:  timeout,
+		WriteTimeout: timeout,
+	}
+	log.Printf("Debug server starting on %s", *debugAddr)
+	if err := srv.ListenAndServe(); err != nil {
+		log.Printf("Debug server error: %v", err)
+	}
+}
+
+// newDryRunSDK creates an SDK evaluating the flags of the tag without reporting events
+func newDryRunSDK(ctx context.Context) (*dryrun.SDK, error) {
+	sdk, err := dryrun.NewSDK(dryrun.Config{
+		APIKey:      *bucketeerAPIKey,
+		APIEndpoint: *bucketeerAPIEndpoint,
+		Scheme:      *scheme,
+		Tag:         *bucketeerTag,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := sdk.WaitReady(ctx); err != nil {
+		_ = sdk.Close(ctx)
+		return nil, err
+	}
+	return sdk, nil
+}
+
+func splitFlags(s string) []string {
+	var flags []string
+	for _, f := range strings.Split(s, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			flags = append(flags, f)
+		}
+	}
+	return flags
+}
+
+// typeOptions parses the flag types, e.g. "new-checkout=boolean,max-items=number"
+func typeOptions(s string) ([]ofrep.Option, error) {
+	var options []ofrep.Option
+	for _, f := range splitFlags(s) {
+		id, t, ok := strings.Cut(f, "=")
+		if !ok {
+			return nil, fmt.Errorf("missing type of flag %q, expected id=type", f)
+		}
+		flagType, err := ofrep.ParseFlagType(strings.TrimSpace(t))
+		if err != nil {
+			return nil, fmt.Errorf("flag %s: %w", id, err)
+		}
+		options = append(options, ofrep.WithFlagType(strings.TrimSpace(id), flagType))
+	}
+	return options, nil
+}
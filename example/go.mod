@@ -1,27 +1,31 @@
 module github.com/bucketeer-io/openfeature-go-server-sdk/example
 
-go 1.24.2
+go 1.25.1
 
 require (
-	github.com/bucketeer-io/go-server-sdk v1.5.6-0.20250515121938-9e1d5ff17a1d
+	github.com/bucketeer-io/go-server-sdk v1.6.1
 	github.com/bucketeer-io/openfeature-go-server-sdk v0.0.0-20250516033446-aaf587c291ff
-	github.com/open-feature/go-sdk v1.14.1
+	github.com/open-feature/go-sdk v1.17.1
 )
 
 require (
 	github.com/blang/semver v3.5.1+incompatible // indirect
-	github.com/bucketeer-io/bucketeer v1.3.0 // indirect
-	github.com/go-logr/logr v1.4.2 // indirect
-	github.com/grpc-ecosystem/grpc-gateway/v2 v2.22.0 // indirect
+	github.com/bucketeer-io/bucketeer/v2 v2.2.0 // indirect
+	github.com/go-logr/logr v1.4.3 // indirect
+	github.com/grpc-ecosystem/grpc-gateway/v2 v2.27.4 // indirect
 	github.com/jinzhu/copier v0.4.0 // indirect
 	github.com/spaolacci/murmur3 v1.1.0 // indirect
 	go.opencensus.io v0.24.0 // indirect
-	golang.org/x/exp v0.0.0-20240529005216-23cca8864a10 // indirect
-	golang.org/x/net v0.33.0 // indirect
-	golang.org/x/sys v0.28.0 // indirect
-	golang.org/x/text v0.21.0 // indirect
-	google.golang.org/genproto/googleapis/api v0.0.0-20240903143218-8af14fe29dc1 // indirect
-	google.golang.org/genproto/googleapis/rpc v0.0.0-20240903143218-8af14fe29dc1 // indirect
-	google.golang.org/grpc v1.66.0 // indirect
-	google.golang.org/protobuf v1.36.5 // indirect
+	go.uber.org/mock v0.6.0 // indirect
+	golang.org/x/exp v0.0.0-20240719175910-8a7402abbf56 // indirect
+	golang.org/x/net v0.47.0 // indirect
+	golang.org/x/sys v0.38.0 // indirect
+	golang.org/x/text v0.32.0 // indirect
+	google.golang.org/genproto/googleapis/api v0.0.0-20251222181119-0a764e51fe1b // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20251222181119-0a764e51fe1b // indirect
+	google.golang.org/grpc v1.78.0 // indirect
+	google.golang.org/protobuf v1.36.11 // indirect
+	gopkg.in/yaml.v2 v2.4.0 // indirect
 )
+
+replace github.com/bucketeer-io/openfeature-go-server-sdk => ../
@@ -15,6 +15,8 @@ import (
 	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer"
 	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/uuid"
 	provider "github.com/bucketeer-io/openfeature-go-server-sdk/pkg"
+	"github.com/bucketeer-io/openfeature-go-server-sdk/pkg/debug"
+	"github.com/bucketeer-io/openfeature-go-server-sdk/pkg/dryrun"
 	"github.com/open-feature/go-sdk/openfeature"
 )
 
@@ -33,6 +35,8 @@ var (
 	intFeatureID         = flag.String("int-feature-id", "example-int-flag", "Integer feature ID")
 	floatFeatureID       = flag.String("float-feature-id", "example-float-flag", "Float feature ID")
 	objectFeatureID      = flag.String("object-feature-id", "example-object-flag", "Object feature ID")
+	debugAddr            = flag.String("debug-addr", "",
+		"Address serving the debug page at /debug/bucketeer/, disabled if empty")
 )
 
 func main() {
@@ -51,7 +55,18 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	p, err := provider.NewProviderWithContext(ctx, options)
+	var providerOptions []provider.Option
+	if *debugAddr != "" {
+		// The test evaluations of the debug page must not be reported to Bucketeer.
+		// The dry-run SDK polls the flags, so the API key must allow it, otherwise the test evaluations are disabled.
+		dryRunSDK, err := newDryRunSDK(ctx)
+		if err != nil {
+			log.Printf("Test evaluations disabled, failed to create dry-run SDK: %v", err)
+		} else {
+			providerOptions = append(providerOptions, provider.WithDryRunSDK(dryRunSDK))
+		}
+	}
+	p, err := provider.NewProviderWithContext(ctx, options, providerOptions...)
 	if err != nil {
 		log.Fatalf("Failed to create provider: %v", err)
 	}
@@ -60,6 +75,13 @@ func main() {
 	openfeature.SetProvider(p)
 	client := openfeature.NewClient("example-app")
 
+	if *debugAddr != "" {
+		// Record the last evaluations shown by the debug page
+		recorder := debug.NewRecorder(0)
+		openfeature.AddHooks(recorder)
+		go serveDebug(p, recorder)
+	}
+
 	// Setup and start HTTP server
 	app := &exampleApp{
 		client:           client,
@@ -74,6 +96,56 @@ func main() {
 	if err := app.run(":8080"); err != nil {
 		log.Fatalf("Server error: %v", err)
 	}
+
+	// Flush the pending events before exiting
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := p.ShutdownWithContext(shutdownCtx); err != nil {
+		log.Printf("Provider shutdown error: %v", err)
+	}
+}
+
+// serveDebug serves the debug page on its own address, so it's not exposed with the example endpoints.
+// It shows user attributes, so serve it on an internal address in production.
+func serveDebug(p *provider.Provider, recorder *debug.Recorder) {
+	mux := http.NewServeMux()
+	mux.Handle("/debug/bucketeer/", debug.NewHandler(p,
+		debug.WithConfig(debug.Config{
+			Tag:         *bucketeerTag,
+			APIEndpoint: *bucketeerAPIEndpoint,
+			Scheme:      *scheme,
+			APIKey:      *bucketeerAPIKey,
+		}),
+		debug.WithRecorder(recorder),
+	))
+	srv := &http.Server{
+		Addr:         *debugAddr,
+		Handler:      mux,
+		ReadTimeout:  timeout,
+		WriteTimeout: timeout,
+	}
+	log.Printf("Debug server starting on %s", *debugAddr)
+	if err := srv.ListenAndServe(); err != nil {
+		log.Printf("Debug server error: %v", err)
+	}
+}
+
+// newDryRunSDK creates an SDK evaluating the flags of the tag without reporting events
+func newDryRunSDK(ctx context.Context) (*dryrun.SDK, error) {
+	sdk, err := dryrun.NewSDK(dryrun.Config{
+		APIKey:      *bucketeerAPIKey,
+		APIEndpoint: *bucketeerAPIEndpoint,
+		Scheme:      *scheme,
+		Tag:         *bucketeerTag,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := sdk.WaitReady(ctx); err != nil {
+		_ = sdk.Close(ctx)
+		return nil, err
+	}
+	return sdk, nil
 }
 
 type exampleApp struct {
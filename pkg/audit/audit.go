@@ -0,0 +1,281 @@
+// Package audit provides an OpenFeature hook recording flag evaluations to an audit sink,
+// to show which users got which variant of sensitive flags.
+//
+// Records are written asynchronously, so the hook never waits for the sink
+// unless the Block backpressure policy is used.
+package audit
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+
+	provider "github.com/bucketeer-io/openfeature-go-server-sdk/pkg"
+)
+
+const defaultBufferSize = 1024
+
+// Record is an audited flag evaluation.
+type Record struct {
+	Timestamp       time.Time `json:"timestamp"`
+	Flag            string    `json:"flag"`
+	Variant         string    `json:"variant,omitempty"`
+	VariationID     string    `json:"variationId,omitempty"`
+	Reason          string    `json:"reason"`
+	BucketeerReason string    `json:"bucketeerReason,omitempty"`
+	FeatureVersion  int64     `json:"featureVersion,omitempty"`
+	ErrorCode       string    `json:"errorCode,omitempty"`
+	// UserHash is the SHA-256 hash of the targeting key, or its HMAC-SHA256 if a salt is set.
+	UserHash string `json:"userHash,omitempty"`
+}
+
+// Sink stores audit records.
+// Write and Close are called from a single goroutine.
+type Sink interface {
+	Write(record Record) error
+	Close() error
+}
+
+// Backpressure is the policy applied when the record buffer is full.
+type Backpressure int
+
+const (
+	// DropNewest drops the record being added. This is the default.
+	DropNewest Backpressure = iota
+	// DropOldest drops the oldest buffered record to make room for the new one.
+	DropOldest
+	// Block waits until the sink consumes a record.
+	// It blocks the evaluations while the sink is slower than the evaluations,
+	// until the hook is closed: the records of the evaluations waiting then are dropped.
+	Block
+)
+
+// Option configures a Hook.
+type Option func(*Hook)
+
+// WithFlags restricts the audit to the given flags.
+// All flags are audited if no flag is set.
+func WithFlags(flags ...string) Option {
+	return func(h *Hook) {
+		if h.flags == nil {
+			h.flags = make(map[string]struct{}, len(flags))
+		}
+		for _, f := range flags {
+			h.flags[f] = struct{}{}
+		}
+	}
+}
+
+// WithBufferSize sets the number of records buffered before the backpressure policy applies.
+// A negative size keeps the default size.
+func WithBufferSize(size int) Option {
+	return func(h *Hook) {
+		if size < 0 {
+			size = defaultBufferSize
+		}
+		h.bufferSize = size
+	}
+}
+
+// WithBackpressure sets the policy applied when the buffer is full.
+func WithBackpressure(policy Backpressure) Option {
+	return func(h *Hook) {
+		h.backpressure = policy
+	}
+}
+
+// WithUserHashSalt hashes the targeting keys with HMAC-SHA256 using the salt,
+// so the hashes can't be reversed by hashing known user IDs.
+func WithUserHashSalt(salt []byte) Option {
+	return func(h *Hook) {
+		h.salt = salt
+	}
+}
+
+// WithErrorHandler sets a function called when the sink fails to write a record.
+func WithErrorHandler(handler func(error)) Option {
+	return func(h *Hook) {
+		h.errorHandler = handler
+	}
+}
+
+// Hook is an OpenFeature hook sending evaluation records to a Sink.
+type Hook struct {
+	openfeature.UnimplementedHook
+
+	sink         Sink
+	flags        map[string]struct{}
+	bufferSize   int
+	backpressure Backpressure
+	salt         []byte
+	errorHandler func(error)
+	now          func() time.Time
+
+	// mu guards closed, so that no record is added to records after it's closed
+	mu      sync.RWMutex
+	closed  bool
+	records chan Record
+	// closing is closed when Close is called, to unblock the records waiting with the Block policy,
+	// which hold mu
+	closing   chan struct{}
+	closeOnce sync.Once
+	done      chan struct{}
+	dropped   atomic.Uint64
+}
+
+// NewHook creates a Hook writing records to the sink, and starts writing in the background.
+// Close must be called to flush the buffered records.
+func NewHook(sink Sink, opts ...Option) *Hook {
+	h := &Hook{
+		sink:       sink,
+		bufferSize: defaultBufferSize,
+		now:        time.Now,
+		closing:    make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.records = make(chan Record, h.bufferSize)
+	go h.run()
+	return h
+}
+
+// Finally records the evaluation.
+func (h *Hook) Finally(
+	ctx context.Context,
+	hookCtx openfeature.HookContext,
+	details openfeature.InterfaceEvaluationDetails,
+	hints openfeature.HookHints,
+) {
+	if !h.audited(details.FlagKey) {
+		return
+	}
+	h.add(h.newRecord(hookCtx, details))
+}
+
+// Dropped returns the number of records dropped because the buffer was full or the hook was closed.
+func (h *Hook) Dropped() uint64 {
+	return h.dropped.Load()
+}
+
+// Close stops recording evaluations, writes the buffered records until ctx is done, and closes the sink.
+// The evaluations waiting for room in the buffer with the Block policy drop their records.
+func (h *Hook) Close(ctx context.Context) error {
+	h.closeOnce.Do(func() {
+		close(h.closing)
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.closed = true
+		close(h.records)
+	})
+
+	select {
+	case <-h.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (h *Hook) audited(flag string) bool {
+	if len(h.flags) == 0 {
+		return true
+	}
+	_, ok := h.flags[flag]
+	return ok
+}
+
+func (h *Hook) newRecord(hookCtx openfeature.HookContext, details openfeature.InterfaceEvaluationDetails) Record {
+	record := Record{
+		Timestamp: h.now(),
+		Flag:      details.FlagKey,
+		Variant:   details.Variant,
+		Reason:    string(details.Reason),
+		ErrorCode: string(details.ErrorCode),
+		UserHash:  h.hashUser(hookCtx.EvaluationContext().TargetingKey()),
+	}
+	if v, err := details.FlagMetadata.GetString(provider.FlagMetadataVariationID); err == nil {
+		record.VariationID = v
+	}
+	if v, err := details.FlagMetadata.GetString(provider.FlagMetadataBucketeerReason); err == nil {
+		record.BucketeerReason = v
+	}
+	if v, err := details.FlagMetadata.GetInt(provider.FlagMetadataFeatureVersion); err == nil {
+		record.FeatureVersion = v
+	}
+	return record
+}
+
+func (h *Hook) hashUser(targetingKey string) string {
+	if targetingKey == "" {
+		return ""
+	}
+	if len(h.salt) == 0 {
+		sum := sha256.Sum256([]byte(targetingKey))
+		return hex.EncodeToString(sum[:])
+	}
+	mac := hmac.New(sha256.New, h.salt)
+	mac.Write([]byte(targetingKey))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (h *Hook) add(record Record) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if h.closed {
+		h.dropped.Add(1)
+		return
+	}
+	switch h.backpressure {
+	case Block:
+		select {
+		case h.records <- record:
+		case <-h.closing:
+			h.dropped.Add(1)
+		}
+	case DropOldest:
+		for {
+			select {
+			case h.records <- record:
+				return
+			default:
+			}
+			select {
+			case <-h.records:
+				h.dropped.Add(1)
+			default:
+			}
+		}
+	default:
+		select {
+		case h.records <- record:
+		default:
+			h.dropped.Add(1)
+		}
+	}
+}
+
+func (h *Hook) run() {
+	defer close(h.done)
+	for record := range h.records {
+		if err := h.sink.Write(record); err != nil {
+			h.handleError(err)
+		}
+	}
+	if err := h.sink.Close(); err != nil {
+		h.handleError(err)
+	}
+}
+
+func (h *Hook) handleError(err error) {
+	if h.errorHandler != nil {
+		h.errorHandler(err)
+	}
+}
@@ -0,0 +1,342 @@
+package audit
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	provider "github.com/bucketeer-io/openfeature-go-server-sdk/pkg"
+)
+
+var testTime = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// memorySink stores records in memory. Write blocks while the sink is locked.
+type memorySink struct {
+	mu      sync.Mutex
+	records []Record
+	closed  bool
+	err     error
+}
+
+func (s *memorySink) Write(record Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, record)
+	return s.err
+}
+
+func (s *memorySink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func (s *memorySink) flags() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	flags := make([]string, 0, len(s.records))
+	for _, r := range s.records {
+		flags = append(flags, r.Flag)
+	}
+	return flags
+}
+
+func newTestHook(sink Sink, opts ...Option) *Hook {
+	h := NewHook(sink, opts...)
+	h.now = func() time.Time { return testTime }
+	return h
+}
+
+func evaluate(h *Hook, flag, targetingKey string, resolution openfeature.ResolutionDetail) {
+	hookCtx := openfeature.NewHookContext(
+		flag,
+		openfeature.String,
+		"default",
+		openfeature.NewClientMetadata(""),
+		openfeature.Metadata{Name: "Bucketeer"},
+		openfeature.NewEvaluationContext(targetingKey, nil),
+	)
+	details := openfeature.InterfaceEvaluationDetails{
+		Value: "value",
+		EvaluationDetails: openfeature.EvaluationDetails{
+			FlagKey:          flag,
+			FlagType:         openfeature.String,
+			ResolutionDetail: resolution,
+		},
+	}
+	h.Finally(context.Background(), hookCtx, details, openfeature.HookHints{})
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacHex(salt, s string) string {
+	mac := hmac.New(sha256.New, []byte(salt))
+	mac.Write([]byte(s))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestHookFinally(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		desc         string
+		opts         []Option
+		flag         string
+		targetingKey string
+		resolution   openfeature.ResolutionDetail
+		expected     []Record
+	}{
+		{
+			desc:         "variation served",
+			flag:         "flag",
+			targetingKey: "user-1",
+			resolution: openfeature.ResolutionDetail{
+				Variant: "variation-name",
+				Reason:  openfeature.TargetingMatchReason,
+				FlagMetadata: openfeature.FlagMetadata{
+					provider.FlagMetadataFeatureVersion:  int64(3),
+					provider.FlagMetadataVariationID:     "variation-1",
+					provider.FlagMetadataBucketeerReason: "RULE",
+				},
+			},
+			expected: []Record{{
+				Timestamp:       testTime,
+				Flag:            "flag",
+				Variant:         "variation-name",
+				VariationID:     "variation-1",
+				Reason:          "TARGETING_MATCH",
+				BucketeerReason: "RULE",
+				FeatureVersion:  3,
+				UserHash:        sha256Hex("user-1"),
+			}},
+		},
+		{
+			desc:         "evaluation error",
+			flag:         "flag",
+			targetingKey: "user-1",
+			resolution: openfeature.ResolutionDetail{
+				Reason:    openfeature.ErrorReason,
+				ErrorCode: openfeature.FlagNotFoundCode,
+				FlagMetadata: openfeature.FlagMetadata{
+					provider.FlagMetadataBucketeerReason: "ERROR_FLAG_NOT_FOUND",
+				},
+			},
+			expected: []Record{{
+				Timestamp:       testTime,
+				Flag:            "flag",
+				Reason:          "ERROR",
+				BucketeerReason: "ERROR_FLAG_NOT_FOUND",
+				ErrorCode:       "FLAG_NOT_FOUND",
+				UserHash:        sha256Hex("user-1"),
+			}},
+		},
+		{
+			desc:         "salted user hash",
+			opts:         []Option{WithUserHashSalt([]byte("salt"))},
+			flag:         "flag",
+			targetingKey: "user-1",
+			resolution:   openfeature.ResolutionDetail{Reason: openfeature.DefaultReason},
+			expected: []Record{{
+				Timestamp: testTime,
+				Flag:      "flag",
+				Reason:    "DEFAULT",
+				UserHash:  hmacHex("salt", "user-1"),
+			}},
+		},
+		{
+			desc:       "no targeting key",
+			flag:       "flag",
+			resolution: openfeature.ResolutionDetail{Reason: openfeature.DefaultReason},
+			expected: []Record{{
+				Timestamp: testTime,
+				Flag:      "flag",
+				Reason:    "DEFAULT",
+			}},
+		},
+		{
+			desc:         "audited flag",
+			opts:         []Option{WithFlags("other", "flag")},
+			flag:         "flag",
+			targetingKey: "user-1",
+			resolution:   openfeature.ResolutionDetail{Reason: openfeature.DefaultReason},
+			expected: []Record{{
+				Timestamp: testTime,
+				Flag:      "flag",
+				Reason:    "DEFAULT",
+				UserHash:  sha256Hex("user-1"),
+			}},
+		},
+		{
+			desc:         "not audited flag",
+			opts:         []Option{WithFlags("other")},
+			flag:         "flag",
+			targetingKey: "user-1",
+			resolution:   openfeature.ResolutionDetail{Reason: openfeature.DefaultReason},
+			expected:     nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			sink := &memorySink{}
+			h := newTestHook(sink, test.opts...)
+
+			evaluate(h, test.flag, test.targetingKey, test.resolution)
+			require.NoError(t, h.Close(context.Background()))
+
+			assert.Equal(t, test.expected, sink.records)
+			assert.True(t, sink.closed)
+		})
+	}
+}
+
+func TestHookBackpressure(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		desc            string
+		policy          Backpressure
+		expectedFlags   []string
+		expectedDropped uint64
+	}{
+		{
+			desc:            "drop newest",
+			policy:          DropNewest,
+			expectedFlags:   []string{"flag-0", "flag-1", "flag-2"},
+			expectedDropped: 2,
+		},
+		{
+			desc:            "drop oldest",
+			policy:          DropOldest,
+			expectedFlags:   []string{"flag-0", "flag-3", "flag-4"},
+			expectedDropped: 2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			sink := &memorySink{}
+			h := newTestHook(sink, WithBufferSize(2), WithBackpressure(test.policy))
+
+			// flag-0 is consumed by the worker, which then blocks on the locked sink
+			sink.mu.Lock()
+			evaluate(h, "flag-0", "user-1", openfeature.ResolutionDetail{})
+			require.Eventually(t, func() bool { return len(h.records) == 0 }, time.Second, time.Millisecond)
+			for _, flag := range []string{"flag-1", "flag-2", "flag-3", "flag-4"} {
+				evaluate(h, flag, "user-1", openfeature.ResolutionDetail{})
+			}
+			sink.mu.Unlock()
+			require.NoError(t, h.Close(context.Background()))
+
+			assert.Equal(t, test.expectedFlags, sink.flags())
+			assert.Equal(t, test.expectedDropped, h.Dropped())
+		})
+	}
+}
+
+func TestHookBlock(t *testing.T) {
+	t.Parallel()
+	sink := &memorySink{}
+	h := newTestHook(sink, WithBufferSize(1), WithBackpressure(Block))
+
+	sink.mu.Lock()
+	evaluated := make(chan struct{})
+	go func() {
+		defer close(evaluated)
+		for _, flag := range []string{"flag-0", "flag-1", "flag-2"} {
+			evaluate(h, flag, "user-1", openfeature.ResolutionDetail{})
+		}
+	}()
+	select {
+	case <-evaluated:
+		t.Fatal("evaluations should wait for the sink")
+	case <-time.After(50 * time.Millisecond):
+	}
+	sink.mu.Unlock()
+	<-evaluated
+	require.NoError(t, h.Close(context.Background()))
+
+	assert.Equal(t, []string{"flag-0", "flag-1", "flag-2"}, sink.flags())
+	assert.Zero(t, h.Dropped())
+}
+
+func TestHookClose(t *testing.T) {
+	t.Parallel()
+	sink := &memorySink{}
+	h := newTestHook(sink)
+
+	sink.mu.Lock()
+	evaluate(h, "flag-0", "user-1", openfeature.ResolutionDetail{})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, h.Close(ctx), context.DeadlineExceeded)
+	sink.mu.Unlock()
+
+	// evaluations after Close are dropped, and the buffered ones are still written
+	evaluate(h, "flag-1", "user-1", openfeature.ResolutionDetail{})
+	require.NoError(t, h.Close(context.Background()))
+	assert.Equal(t, []string{"flag-0"}, sink.flags())
+	assert.Equal(t, uint64(1), h.Dropped())
+	assert.True(t, sink.closed)
+}
+
+func TestHookCloseBlocked(t *testing.T) {
+	t.Parallel()
+	// Nobody reads the records, so the sink stalls on the first one and the next evaluations wait
+	h := newTestHook(NewChannelSink(make(chan Record)), WithBufferSize(0), WithBackpressure(Block))
+
+	evaluated := make(chan struct{})
+	go func() {
+		defer close(evaluated)
+		for _, flag := range []string{"flag-0", "flag-1", "flag-2"} {
+			evaluate(h, flag, "user-1", openfeature.ResolutionDetail{})
+		}
+	}()
+	select {
+	case <-evaluated:
+		t.Fatal("evaluations should wait for the sink")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Close honors its ctx, and unblocks the waiting evaluation
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, h.Close(ctx), context.DeadlineExceeded)
+	<-evaluated
+	assert.Equal(t, uint64(2), h.Dropped())
+}
+
+func TestWithBufferSize(t *testing.T) {
+	t.Parallel()
+	h := newTestHook(&memorySink{}, WithBufferSize(-1))
+	defer func() { _ = h.Close(context.Background()) }()
+	assert.Equal(t, defaultBufferSize, cap(h.records))
+}
+
+func TestHookErrorHandler(t *testing.T) {
+	t.Parallel()
+	writeErr := errors.New("write error")
+	sink := &memorySink{err: writeErr}
+	var errs []error
+	h := newTestHook(sink, WithErrorHandler(func(err error) {
+		errs = append(errs, err)
+	}))
+
+	evaluate(h, "flag", "user-1", openfeature.ResolutionDetail{})
+	require.NoError(t, h.Close(context.Background()))
+
+	assert.Equal(t, []error{writeErr}, errs)
+}
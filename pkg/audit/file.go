@@ -0,0 +1,123 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const defaultMaxFileSize = 100 << 20
+
+// FileSinkConfig configures a FileSink.
+type FileSinkConfig struct {
+	// Path is the file the records are appended to.
+	Path string
+	// MaxSize is the size in bytes after which the file is rotated. Defaults to 100MB.
+	MaxSize int64
+	// MaxBackups is the number of rotated files kept, named Path.1 (the newest) to Path.<MaxBackups>.
+	// Rotated files are removed if it's zero.
+	MaxBackups int
+}
+
+// FileSink appends records to a file as JSON lines, and rotates the file when it's too large.
+type FileSink struct {
+	config FileSinkConfig
+	file   *os.File
+	size   int64
+}
+
+// NewFileSink opens the file and creates a FileSink appending to it.
+func NewFileSink(config FileSinkConfig) (*FileSink, error) {
+	if config.Path == "" {
+		return nil, errors.New("audit: file path is required")
+	}
+	config.Path = filepath.Clean(config.Path)
+	if config.MaxSize <= 0 {
+		config.MaxSize = defaultMaxFileSize
+	}
+	s := &FileSink{config: config}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Write appends the record to the file, after rotating it if the record doesn't fit.
+// If the rotation fails, the record is still appended to the file, and the rotation is retried with the next record.
+func (s *FileSink) Write(record Record) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("audit: failed to encode record: %w", err)
+	}
+	line = append(line, '\n')
+	var rotateErr error
+	if s.size > 0 && s.size+int64(len(line)) > s.config.MaxSize {
+		rotateErr = s.rotate()
+	}
+	n, err := s.file.Write(line)
+	s.size += int64(n)
+	if err != nil {
+		return errors.Join(rotateErr, fmt.Errorf("audit: failed to write record: %w", err))
+	}
+	return rotateErr
+}
+
+// Close closes the file.
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
+
+func (s *FileSink) open() error {
+	f, err := os.OpenFile(s.config.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("audit: failed to open file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("audit: failed to stat file: %w", err)
+	}
+	s.file = f
+	s.size = info.Size()
+	return nil
+}
+
+// rotate moves the file to the backups, or removes it, and opens a new file.
+// If the file can't be moved, it's reopened, so the next records are still appended to it.
+func (s *FileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return s.reopen(fmt.Errorf("audit: failed to close file: %w", err))
+	}
+	if err := s.moveToBackups(); err != nil {
+		return s.reopen(fmt.Errorf("audit: failed to rotate file: %w", err))
+	}
+	return s.open()
+}
+
+// moveToBackups shifts the backups and moves the file to the first one, or removes it without backups
+func (s *FileSink) moveToBackups() error {
+	if s.config.MaxBackups == 0 {
+		return os.Remove(s.config.Path)
+	}
+	for i := s.config.MaxBackups - 1; i > 0; i-- {
+		err := os.Rename(s.backupPath(i), s.backupPath(i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.Rename(s.config.Path, s.backupPath(1))
+}
+
+// reopen opens the file again after a failed rotation, and returns the rotation error
+func (s *FileSink) reopen(err error) error {
+	if openErr := s.open(); openErr != nil {
+		return errors.Join(err, openErr)
+	}
+	return err
+}
+
+func (s *FileSink) backupPath(i int) string {
+	return fmt.Sprintf("%s.%d", s.config.Path, i)
+}
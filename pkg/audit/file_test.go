@@ -0,0 +1,125 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readLines(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+func TestNewFileSink(t *testing.T) {
+	t.Parallel()
+	_, err := NewFileSink(FileSinkConfig{})
+	assert.EqualError(t, err, "audit: file path is required")
+
+	_, err = NewFileSink(FileSinkConfig{Path: filepath.Join(t.TempDir(), "missing", "audit.log")})
+	assert.ErrorContains(t, err, "audit: failed to open file")
+}
+
+func TestFileSinkRotation(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		desc       string
+		maxBackups int
+		expected   map[string][]string
+	}{
+		{
+			desc:       "without backups",
+			maxBackups: 0,
+			expected: map[string][]string{
+				"audit.log": {`"flag-4"`},
+			},
+		},
+		{
+			desc:       "with backups",
+			maxBackups: 2,
+			expected: map[string][]string{
+				"audit.log":   {`"flag-4"`},
+				"audit.log.1": {`"flag-2"`, `"flag-3"`},
+				"audit.log.2": {`"flag-0"`, `"flag-1"`},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			path := filepath.Join(dir, "audit.log")
+			line := `{"timestamp":"2024-01-02T03:04:05Z","flag":"flag-0","reason":"DEFAULT"}` + "\n"
+			sink, err := NewFileSink(FileSinkConfig{
+				Path:       path,
+				MaxSize:    int64(2 * len(line)),
+				MaxBackups: test.maxBackups,
+			})
+			require.NoError(t, err)
+
+			for _, flag := range []string{"flag-0", "flag-1", "flag-2", "flag-3", "flag-4"} {
+				require.NoError(t, sink.Write(Record{Timestamp: testTime, Flag: flag, Reason: "DEFAULT"}))
+			}
+			require.NoError(t, sink.Close())
+
+			entries, err := os.ReadDir(dir)
+			require.NoError(t, err)
+			assert.Len(t, entries, len(test.expected))
+			for name, flags := range test.expected {
+				lines := readLines(t, filepath.Join(dir, name))
+				require.Len(t, lines, len(flags), name)
+				for i, flag := range flags {
+					assert.Contains(t, lines[i], `"flag":`+flag, name)
+				}
+			}
+		})
+	}
+}
+
+func TestFileSinkAppend(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "audit.log")
+	for _, flag := range []string{"flag-0", "flag-1"} {
+		sink, err := NewFileSink(FileSinkConfig{Path: path})
+		require.NoError(t, err)
+		require.NoError(t, sink.Write(Record{Timestamp: testTime, Flag: flag, Reason: "DEFAULT"}))
+		require.NoError(t, sink.Close())
+	}
+
+	lines := readLines(t, path)
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"flag":"flag-0"`)
+	assert.Contains(t, lines[1], `"flag":"flag-1"`)
+}
+
+func TestFileSinkRotationFailure(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.log")
+	// The file can't be moved to the backup, which is a non-empty directory
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "audit.log.1", "taken"), 0o700))
+	sink, err := NewFileSink(FileSinkConfig{Path: path, MaxSize: 1, MaxBackups: 1})
+	require.NoError(t, err)
+
+	for _, flag := range []string{"flag-0", "flag-1", "flag-2"} {
+		err := sink.Write(Record{Timestamp: testTime, Flag: flag, Reason: "DEFAULT"})
+		if flag == "flag-0" {
+			require.NoError(t, err)
+		} else {
+			assert.ErrorContains(t, err, "audit: failed to rotate file")
+		}
+	}
+	require.NoError(t, sink.Close())
+
+	// The records are still appended to the file
+	lines := readLines(t, path)
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[2], `"flag":"flag-2"`)
+}
@@ -0,0 +1,59 @@
+package audit
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// WriterSink writes records to an io.Writer as JSON lines.
+type WriterSink struct {
+	encoder *json.Encoder
+	closer  io.Closer
+}
+
+// NewWriterSink creates a WriterSink writing to w, e.g. os.Stdout.
+// w is not closed with the sink, see NewWriteCloserSink.
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{encoder: json.NewEncoder(w)}
+}
+
+// NewWriteCloserSink creates a WriterSink writing to w, which is owned by the sink and closed with it.
+func NewWriteCloserSink(w io.WriteCloser) *WriterSink {
+	return &WriterSink{encoder: json.NewEncoder(w), closer: w}
+}
+
+// Write writes the record as a JSON line.
+func (s *WriterSink) Write(record Record) error {
+	return s.encoder.Encode(record)
+}
+
+// Close closes the writer if the sink was created with NewWriteCloserSink.
+func (s *WriterSink) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
+
+// ChannelSink sends records to a channel, e.g. to forward them to a custom pipeline.
+type ChannelSink struct {
+	records chan<- Record
+}
+
+// NewChannelSink creates a ChannelSink sending to records.
+// The channel is closed with the sink.
+func NewChannelSink(records chan<- Record) *ChannelSink {
+	return &ChannelSink{records: records}
+}
+
+// Write sends the record to the channel.
+func (s *ChannelSink) Write(record Record) error {
+	s.records <- record
+	return nil
+}
+
+// Close closes the channel.
+func (s *ChannelSink) Close() error {
+	close(s.records)
+	return nil
+}
@@ -0,0 +1,72 @@
+package audit
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriterSink(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	sink := NewWriterSink(&buf)
+
+	require.NoError(t, sink.Write(Record{Timestamp: testTime, Flag: "flag-1", Reason: "DEFAULT"}))
+	require.NoError(t, sink.Write(Record{
+		Timestamp:      testTime,
+		Flag:           "flag-2",
+		Variant:        "on",
+		Reason:         "TARGETING_MATCH",
+		FeatureVersion: 2,
+		UserHash:       "hash",
+	}))
+	require.NoError(t, sink.Close())
+
+	assert.Equal(t,
+		`{"timestamp":"2024-01-02T03:04:05Z","flag":"flag-1","reason":"DEFAULT"}`+"\n"+
+			`{"timestamp":"2024-01-02T03:04:05Z","flag":"flag-2","variant":"on","reason":"TARGETING_MATCH","featureVersion":2,"userHash":"hash"}`+"\n",
+		buf.String(),
+	)
+}
+
+// closeRecorder is a writer recording whether it was closed
+type closeRecorder struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (w *closeRecorder) Close() error {
+	w.closed = true
+	return nil
+}
+
+func TestWriterSinkClose(t *testing.T) {
+	t.Parallel()
+	// The writer is not owned by the sink, e.g. os.Stdout
+	w := &closeRecorder{}
+	require.NoError(t, NewWriterSink(w).Close())
+	assert.False(t, w.closed)
+
+	w = &closeRecorder{}
+	sink := NewWriteCloserSink(w)
+	require.NoError(t, sink.Write(Record{Timestamp: testTime, Flag: "flag", Reason: "DEFAULT"}))
+	require.NoError(t, sink.Close())
+	assert.True(t, w.closed)
+	assert.Equal(t, `{"timestamp":"2024-01-02T03:04:05Z","flag":"flag","reason":"DEFAULT"}`+"\n", w.String())
+}
+
+func TestChannelSink(t *testing.T) {
+	t.Parallel()
+	records := make(chan Record, 1)
+	sink := NewChannelSink(records)
+
+	record := Record{Timestamp: testTime, Flag: "flag", Reason: "DEFAULT"}
+	require.NoError(t, sink.Write(record))
+	require.NoError(t, sink.Close())
+
+	assert.Equal(t, record, <-records)
+	_, ok := <-records
+	assert.False(t, ok)
+}
@@ -0,0 +1,191 @@
+package provider
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/model"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+var errCircuitOpen = errors.New("circuit breaker is open, the SDK is not called")
+
+// Defaults of CircuitBreakerConfig
+const (
+	defaultBreakerErrorRate      = 0.5
+	defaultBreakerMinEvaluations = 20
+	defaultBreakerWindow         = 10 * time.Second
+	defaultBreakerOpenDuration   = 30 * time.Second
+)
+
+// CircuitBreakerConfig configures the circuit breaker enabled with WithCircuitBreaker.
+// Zero values are replaced by the defaults.
+type CircuitBreakerConfig struct {
+	// ErrorRate is the ratio of failed evaluations, between 0 and 1, opening the circuit. Defaults to 0.5.
+	ErrorRate float64
+	// MinEvaluations is the number of evaluations in the window needed to open the circuit,
+	// so that a few failures don't open it when the traffic is low. Defaults to 20.
+	MinEvaluations int
+	// Window is the period over which the error rate is computed. Defaults to 10 seconds.
+	Window time.Duration
+	// OpenDuration is how long the circuit stays open before an evaluation is tried again.
+	// Defaults to 30 seconds.
+	OpenDuration time.Duration
+}
+
+// WithCircuitBreaker stops calling the SDK while it fails, so that evaluations don't wait for the network timeout
+// during a Bucketeer outage when flags are evaluated remotely.
+//
+// Evaluations failing with the ERROR_EXCEPTION or ERROR_NO_EVALUATIONS reason count as failures.
+// When their rate reaches config.ErrorRate, the circuit opens: evaluations return the default value
+// with a GENERAL error without calling the SDK, and the provider emits PROVIDER_ERROR.
+// After config.OpenDuration, the circuit half-opens and the next evaluation calls the SDK:
+// if it succeeds, the circuit closes and the provider emits PROVIDER_READY, otherwise it opens again.
+func WithCircuitBreaker(config CircuitBreakerConfig) Option {
+	return func(p *Provider) {
+		if config.ErrorRate <= 0 {
+			config.ErrorRate = defaultBreakerErrorRate
+		}
+		if config.MinEvaluations <= 0 {
+			config.MinEvaluations = defaultBreakerMinEvaluations
+		}
+		if config.Window <= 0 {
+			config.Window = defaultBreakerWindow
+		}
+		if config.OpenDuration <= 0 {
+			config.OpenDuration = defaultBreakerOpenDuration
+		}
+		p.breaker = &circuitBreaker{
+			config: config,
+			emit:   p.emit,
+			now:    time.Now,
+		}
+	}
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// circuitBreaker counts the failed evaluations, and lets one trial evaluation through when half-open
+type circuitBreaker struct {
+	config CircuitBreakerConfig
+	emit   func(eventType openfeature.EventType, message string)
+	now    func() time.Time
+
+	mu          sync.Mutex
+	state       breakerState
+	windowStart time.Time
+	evaluations int
+	failures    int
+	trying      bool
+	timer       *time.Timer
+	stopped     bool
+}
+
+// isBreakerFailure returns true if the evaluation reason means that the SDK failed to reach Bucketeer
+func isBreakerFailure(reason model.EvaluationReason) bool {
+	return reason == model.EvaluationReasonErrorException || reason == model.EvaluationReasonErrorNoEvaluations
+}
+
+// allow returns false if the SDK must not be called.
+// trial is true for the evaluation trying the SDK again when half-open, which must be passed to record.
+func (b *circuitBreaker) allow() (trial, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case breakerOpen:
+		return false, false
+	case breakerHalfOpen:
+		if b.trying {
+			return false, false
+		}
+		b.trying = true
+		return true, true
+	default:
+		return false, true
+	}
+}
+
+// record records the result of an evaluation allowed by allow
+func (b *circuitBreaker) record(trial, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case breakerHalfOpen:
+		if !trial {
+			return
+		}
+		b.trying = false
+		if failed {
+			b.open()
+			return
+		}
+		b.state = breakerClosed
+		b.resetWindow()
+		b.emit(openfeature.ProviderReady, "circuit breaker closed")
+	case breakerClosed:
+		// The evaluations started before the circuit opened are ignored
+		if trial {
+			return
+		}
+		if b.now().Sub(b.windowStart) >= b.config.Window {
+			b.resetWindow()
+		}
+		b.evaluations++
+		if failed {
+			b.failures++
+		}
+		if b.evaluations >= b.config.MinEvaluations &&
+			float64(b.failures)/float64(b.evaluations) >= b.config.ErrorRate {
+			b.open()
+			b.emit(openfeature.ProviderError, errCircuitOpen.Error())
+		}
+	}
+}
+
+// open opens the circuit until the timer half-opens it. b.mu must be held.
+func (b *circuitBreaker) open() {
+	b.state = breakerOpen
+	if b.stopped {
+		return
+	}
+	b.timer = time.AfterFunc(b.config.OpenDuration, b.halfOpen)
+}
+
+func (b *circuitBreaker) halfOpen() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == breakerOpen && !b.stopped {
+		b.state = breakerHalfOpen
+	}
+}
+
+// resetWindow starts a new window. b.mu must be held.
+func (b *circuitBreaker) resetWindow() {
+	b.windowStart = b.now()
+	b.evaluations = 0
+	b.failures = 0
+}
+
+// isClosed returns false if the circuit is open or half-open
+func (b *circuitBreaker) isClosed() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state == breakerClosed
+}
+
+// stop stops the timer when the provider is shut down
+func (b *circuitBreaker) stop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.stopped = true
+	if b.timer != nil {
+		b.timer.Stop()
+	}
+}
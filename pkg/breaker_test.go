@@ -0,0 +1,208 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/model"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	mockProvider "github.com/bucketeer-io/openfeature-go-server-sdk/test/mock/provider"
+)
+
+func TestCircuitBreakerRecord(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	type result struct {
+		failed bool
+		after  time.Duration
+	}
+	tests := []struct {
+		desc           string
+		results        []result
+		expectedState  breakerState
+		expectedEvents []openfeature.EventType
+	}{
+		{
+			desc: "error rate reached",
+			results: []result{
+				{failed: false}, {failed: true}, {failed: false}, {failed: true},
+			},
+			expectedState:  breakerOpen,
+			expectedEvents: []openfeature.EventType{openfeature.ProviderError},
+		},
+		{
+			desc: "error rate not reached",
+			results: []result{
+				{failed: false}, {failed: true}, {failed: false}, {failed: false},
+			},
+			expectedState: breakerClosed,
+		},
+		{
+			desc: "not enough evaluations",
+			results: []result{
+				{failed: true}, {failed: true}, {failed: true},
+			},
+			expectedState: breakerClosed,
+		},
+		{
+			desc: "failures of the previous window ignored",
+			results: []result{
+				{failed: true}, {failed: true}, {failed: true},
+				{failed: false, after: time.Minute}, {failed: false}, {failed: false}, {failed: true},
+			},
+			expectedState: breakerClosed,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			now := start
+			var events []openfeature.EventType
+			b := &circuitBreaker{
+				config: CircuitBreakerConfig{
+					ErrorRate:      0.5,
+					MinEvaluations: 4,
+					Window:         10 * time.Second,
+					OpenDuration:   time.Hour,
+				},
+				emit: func(eventType openfeature.EventType, _ string) {
+					events = append(events, eventType)
+				},
+				now: func() time.Time { return now },
+			}
+			defer b.stop()
+
+			for _, r := range test.results {
+				now = now.Add(r.after)
+				trial, ok := b.allow()
+				require.True(t, ok)
+				b.record(trial, r.failed)
+			}
+			assert.Equal(t, test.expectedState, b.state)
+			assert.Equal(t, test.expectedEvents, events)
+		})
+	}
+}
+
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		desc          string
+		trialFailed   bool
+		expectedState breakerState
+		expectedEvent openfeature.EventType
+	}{
+		{
+			desc:          "trial succeeded",
+			trialFailed:   false,
+			expectedState: breakerClosed,
+			expectedEvent: openfeature.ProviderReady,
+		},
+		{
+			desc:          "trial failed",
+			trialFailed:   true,
+			expectedState: breakerOpen,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			var events []openfeature.EventType
+			b := &circuitBreaker{
+				config: CircuitBreakerConfig{OpenDuration: time.Hour},
+				emit: func(eventType openfeature.EventType, _ string) {
+					events = append(events, eventType)
+				},
+				now:   time.Now,
+				state: breakerOpen,
+			}
+			defer b.stop()
+			b.halfOpen()
+
+			trial, ok := b.allow()
+			require.True(t, ok)
+			assert.True(t, trial)
+			// Only one evaluation tries the SDK
+			_, ok = b.allow()
+			assert.False(t, ok)
+			// An evaluation started before the circuit opened doesn't close it
+			b.record(false, false)
+			assert.Equal(t, breakerHalfOpen, b.state)
+
+			b.record(trial, test.trialFailed)
+			assert.Equal(t, test.expectedState, b.state)
+			if test.expectedEvent != "" {
+				assert.Equal(t, []openfeature.EventType{test.expectedEvent}, events)
+			} else {
+				assert.Empty(t, events)
+			}
+		})
+	}
+}
+
+func TestEvaluationWithCircuitBreaker(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	evalCtx := openfeature.FlattenedContext{openfeature.TargetingKey: "test-user"}
+	mockSDK := mockProvider.NewMockBucketeerSDK(ctrl)
+	gomock.InOrder(
+		mockSDK.EXPECT().
+			BoolVariationDetails(gomock.Any(), gomock.Any(), "bool-flag", false).
+			Return(model.BKTEvaluationDetails[bool]{
+				FeatureID: "bool-flag",
+				UserID:    "test-user",
+				Reason:    model.EvaluationReasonErrorException,
+			}).
+			Times(2),
+		mockSDK.EXPECT().
+			BoolVariationDetails(gomock.Any(), gomock.Any(), "bool-flag", false).
+			Return(model.BKTEvaluationDetails[bool]{
+				FeatureID:      "bool-flag",
+				UserID:         "test-user",
+				VariationID:    "variation-1",
+				VariationValue: true,
+				Reason:         model.EvaluationReasonRule,
+			}).
+			Times(1),
+	)
+	mockSDK.EXPECT().Close(gomock.Any()).Return(nil).Times(1)
+	provider := newTestProvider(mockSDK, WithCircuitBreaker(CircuitBreakerConfig{
+		ErrorRate:      1,
+		MinEvaluations: 2,
+		OpenDuration:   10 * time.Millisecond,
+	}))
+	defer func() {
+		assert.NoError(t, provider.ShutdownWithContext(context.Background()))
+	}()
+
+	for range 2 {
+		result := provider.BooleanEvaluation(context.Background(), "bool-flag", false, evalCtx)
+		assert.Equal(t, openfeature.NewGeneralResolutionError(string(model.EvaluationReasonErrorException)),
+			result.ResolutionError)
+	}
+	event := <-provider.EventChannel()
+	assert.Equal(t, openfeature.ProviderError, event.EventType)
+	assert.Equal(t, openfeature.ErrorState, provider.Status())
+
+	// The SDK is not called while the circuit is open
+	result := provider.BooleanEvaluation(context.Background(), "bool-flag", false, evalCtx)
+	assert.False(t, result.Value)
+	assert.Equal(t, openfeature.ErrorReason, result.Reason)
+	assert.Equal(t, openfeature.NewGeneralResolutionError(errCircuitOpen.Error()), result.ResolutionError)
+
+	require.Eventually(t, func() bool {
+		return provider.BooleanEvaluation(context.Background(), "bool-flag", false, evalCtx).Value
+	}, time.Second, 5*time.Millisecond)
+	event = <-provider.EventChannel()
+	assert.Equal(t, openfeature.ProviderReady, event.EventType)
+	assert.Equal(t, openfeature.ReadyState, provider.Status())
+}
@@ -0,0 +1,304 @@
+package provider
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/model"
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/user"
+)
+
+// Defaults of CacheConfig
+const (
+	defaultCacheTTL        = 30 * time.Second
+	defaultCacheStaleTTL   = 5 * time.Minute
+	defaultCacheMaxEntries = 10000
+)
+
+// CacheConfig configures the evaluation cache enabled with WithEvaluationCache.
+// Zero values are replaced by the defaults.
+type CacheConfig struct {
+	// TTL is how long an evaluation is served from the cache without calling the SDK. Defaults to 30 seconds.
+	TTL time.Duration
+	// StaleTTL is how long an evaluation is still served after the TTL, while it's refreshed in the background.
+	// Defaults to 5 minutes.
+	StaleTTL time.Duration
+	// MaxEntries is the number of evaluations cached. The least recently used are evicted. Defaults to 10000.
+	MaxEntries int
+}
+
+// WithEvaluationCache caches the evaluations, for services evaluating flags remotely,
+// where each evaluation calls Bucketeer, that can't use the local evaluation.
+//
+// The evaluations are cached by flag type, feature ID, user ID and attributes.
+// A cached evaluation is served without calling the SDK for config.TTL. It's then served for config.StaleTTL
+// while it's refreshed in the background, and evaluated again once both have passed.
+// Concurrent evaluations of the same key call the SDK once. Failed evaluations are not cached,
+// the evaluations failing with the error mapping of the provider, see WithErrorMapping.
+// The object values are copied in and out of the cache, so the callers can modify them.
+//
+// The SDK reports an evaluation event only when it's called, so the experiment analytics
+// count an evaluation per user and flag per TTL at most. Evaluations without events are not cached.
+func WithEvaluationCache(config CacheConfig) Option {
+	return func(p *Provider) {
+		if config.TTL <= 0 {
+			config.TTL = defaultCacheTTL
+		}
+		if config.StaleTTL < 0 {
+			config.StaleTTL = 0
+		} else if config.StaleTTL == 0 {
+			config.StaleTTL = defaultCacheStaleTTL
+		}
+		if config.MaxEntries <= 0 {
+			config.MaxEntries = defaultCacheMaxEntries
+		}
+		p.cache = &evaluationCache{
+			config:  config,
+			now:     time.Now,
+			lru:     list.New(),
+			entries: make(map[string]*list.Element),
+			calls:   make(map[string]*cacheCall),
+		}
+	}
+}
+
+// evaluationCache is an LRU cache of evaluations, with stale-while-revalidate and de-duplicated calls
+type evaluationCache struct {
+	config CacheConfig
+	now    func() time.Time
+
+	mu      sync.Mutex
+	lru     *list.List
+	entries map[string]*list.Element
+	calls   map[string]*cacheCall
+}
+
+type cacheEntry struct {
+	key        string
+	value      any
+	freshUntil time.Time
+	staleUntil time.Time
+	refreshing bool
+}
+
+// cacheCall is a call in progress, waited for by the concurrent calls of the same key
+type cacheCall struct {
+	done  chan struct{}
+	value any
+	err   error
+}
+
+// get returns the cached value, and true if it's stale and must be refreshed by the caller.
+// Only one caller is asked to refresh a stale value, until refreshed is called.
+func (c *evaluationCache) get(key string) (value any, refresh bool, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false, false
+	}
+	entry := elem.Value.(*cacheEntry)
+	now := c.now()
+	if !now.Before(entry.staleUntil) {
+		c.lru.Remove(elem)
+		delete(c.entries, key)
+		return nil, false, false
+	}
+	c.lru.MoveToFront(elem)
+	if now.Before(entry.freshUntil) || entry.refreshing {
+		return entry.value, false, true
+	}
+	entry.refreshing = true
+	return entry.value, true, true
+}
+
+// set caches the value, evicting the least recently used value if the cache is full
+func (c *evaluationCache) set(key string, value any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	entry := &cacheEntry{
+		key:        key,
+		value:      value,
+		freshUntil: now.Add(c.config.TTL),
+		staleUntil: now.Add(c.config.TTL + c.config.StaleTTL),
+	}
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.lru.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(entry)
+	if c.lru.Len() > c.config.MaxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// refreshed lets the next get of a stale value refresh it again, e.g. after a failed refresh
+func (c *evaluationCache) refreshed(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*cacheEntry).refreshing = false
+	}
+}
+
+// do calls fn, unless a call of the same key is in progress, in which case it waits for its result
+func (c *evaluationCache) do(key string, fn func() (any, error)) (any, error) {
+	c.mu.Lock()
+	if call, ok := c.calls[key]; ok {
+		c.mu.Unlock()
+		<-call.done
+		return call.value, call.err
+	}
+	call := &cacheCall{done: make(chan struct{})}
+	c.calls[key] = call
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.calls, key)
+		c.mu.Unlock()
+		close(call.done)
+	}()
+	call.value, call.err = fn()
+	return call.value, call.err
+}
+
+// cachedEvaluation is an evaluation stored in the cache
+type cachedEvaluation[T model.EvaluationValue] struct {
+	evaluation model.BKTEvaluationDetails[T]
+	coercion   string
+}
+
+// evaluationCall evaluates a flag with the SDK, returning the evaluation and the type coercion
+type evaluationCall[T model.EvaluationValue] func(ctx context.Context) (model.BKTEvaluationDetails[T], string, error)
+
+// cachedCall returns the cached evaluation, refreshing it in the background if it's stale,
+// or calls the SDK once for the concurrent evaluations of the same key and caches the evaluation.
+// Failed evaluations are shared by the concurrent evaluations with their own default value.
+// The returned object values are copies, so modifying them doesn't modify the cache.
+func cachedCall[T model.EvaluationValue](
+	ctx context.Context,
+	p *Provider,
+	featureID string,
+	u *user.User,
+	defaultValue T,
+	call evaluationCall[T],
+) (model.BKTEvaluationDetails[T], string, error) {
+	key := cacheKey[T](featureID, u)
+	if value, stale, ok := p.cache.get(key); ok {
+		if stale {
+			refresh(ctx, p, key, call)
+		}
+		cached := value.(cachedEvaluation[T])
+		return shared(p, cached.evaluation, defaultValue), cached.coercion, nil
+	}
+	value, err := p.cache.do(key, func() (any, error) {
+		return callAndCache(ctx, p, key, call)
+	})
+	if err != nil {
+		return model.BKTEvaluationDetails[T]{}, "", err
+	}
+	cached := value.(cachedEvaluation[T])
+	return shared(p, cached.evaluation, defaultValue), cached.coercion, nil
+}
+
+// shared returns an evaluation shared through the cache for a caller: with a copy of the value,
+// or with the caller's default value if the evaluation fails, or if the SDK served the default value
+// of another caller for an error the provider maps to a success.
+func shared[T model.EvaluationValue](
+	p *Provider,
+	evaluation model.BKTEvaluationDetails[T],
+	defaultValue T,
+) model.BKTEvaluationDetails[T] {
+	if p.getEvaluationErrorCode(evaluation.Reason) != "" || getEvaluationErrorCode(evaluation.Reason) != "" {
+		evaluation.VariationValue = defaultValue
+		return evaluation
+	}
+	evaluation.VariationValue = copyValue(evaluation.VariationValue)
+	return evaluation
+}
+
+// refresh evaluates the flag again in the background, with the evaluation context without its cancellation.
+// It does nothing if the provider is shut down.
+func refresh[T model.EvaluationValue](ctx context.Context, p *Provider, key string, call evaluationCall[T]) {
+	if !p.acquire() {
+		p.cache.refreshed(key)
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		defer p.release()
+		defer p.cache.refreshed(key)
+		_, _ = p.cache.do(key, func() (any, error) {
+			return callAndCache(ctx, p, key, call)
+		})
+	}()
+}
+
+// callAndCache calls the SDK and caches a copy of the evaluation if it didn't fail,
+// with the same error classification as the uncached evaluations
+func callAndCache[T model.EvaluationValue](
+	ctx context.Context,
+	p *Provider,
+	key string,
+	call evaluationCall[T],
+) (any, error) {
+	evaluation, coercion, err := call(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cached := cachedEvaluation[T]{evaluation: evaluation, coercion: coercion}
+	if p.getEvaluationErrorCode(evaluation.Reason) == "" {
+		stored := cached
+		stored.evaluation.VariationValue = copyValue(evaluation.VariationValue)
+		p.cache.set(key, stored)
+	}
+	return cached, nil
+}
+
+// copyValue returns a deep copy of the JSON objects and arrays of an object value,
+// so a caller modifying the value doesn't modify the cached one. Other values are returned as is.
+func copyValue[T model.EvaluationValue](value T) T {
+	if copied, ok := copyJSON(any(value)).(T); ok {
+		return copied
+	}
+	return value
+}
+
+// copyJSON returns a deep copy of a decoded JSON value
+func copyJSON(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		copied := make(map[string]any, len(v))
+		for key, elem := range v {
+			copied[key] = copyJSON(elem)
+		}
+		return copied
+	case []any:
+		copied := make([]any, len(v))
+		for i, elem := range v {
+			copied[i] = copyJSON(elem)
+		}
+		return copied
+	default:
+		return value
+	}
+}
+
+// cacheKey returns the cache key of an evaluation.
+// The attributes are hashed as JSON, which has sorted keys.
+func cacheKey[T model.EvaluationValue](featureID string, u *user.User) string {
+	data, _ := json.Marshal(u.Data)
+	sum := sha256.Sum256(data)
+	return reflect.TypeFor[T]().String() + "\x00" + featureID + "\x00" + u.ID + "\x00" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,253 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/model"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	mockProvider "github.com/bucketeer-io/openfeature-go-server-sdk/test/mock/provider"
+)
+
+// fakeClock is a time source advanced by the tests
+type fakeClock struct {
+	now atomic.Int64
+}
+
+func (c *fakeClock) Now() time.Time {
+	return time.Unix(0, c.now.Load())
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now.Add(int64(d))
+}
+
+func newStringEvaluation(value string, reason model.EvaluationReason) model.BKTEvaluationDetails[string] {
+	evaluation := model.BKTEvaluationDetails[string]{
+		FeatureID:      "string-flag",
+		UserID:         "test-user",
+		VariationValue: value,
+		Reason:         reason,
+	}
+	if reason == model.EvaluationReasonDefault {
+		evaluation.VariationID = "variation-" + value
+		evaluation.VariationName = value
+	}
+	return evaluation
+}
+
+func TestEvaluationCache(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSDK := mockProvider.NewMockBucketeerSDK(ctrl)
+	gomock.InOrder(
+		mockSDK.EXPECT().
+			StringVariationDetails(gomock.Any(), gomock.Any(), "string-flag", "default").
+			Return(newStringEvaluation("pro-value", model.EvaluationReasonDefault)),
+		mockSDK.EXPECT().
+			StringVariationDetails(gomock.Any(), gomock.Any(), "string-flag", "default").
+			Return(newStringEvaluation("free-value", model.EvaluationReasonDefault)),
+	)
+	mockSDK.EXPECT().
+		StringVariationDetails(gomock.Any(), gomock.Any(), "missing-flag", "default").
+		Return(newStringEvaluation("default", model.EvaluationReasonErrorFlagNotFound)).
+		Times(2)
+	provider := newTestProvider(mockSDK, WithEvaluationCache(CacheConfig{}))
+	proCtx := openfeature.FlattenedContext{openfeature.TargetingKey: "test-user", "plan": "pro"}
+	freeCtx := openfeature.FlattenedContext{openfeature.TargetingKey: "test-user", "plan": "free"}
+
+	// The second evaluation of the same user is cached
+	assert.Equal(t, "pro-value", provider.StringEvaluation(context.Background(), "string-flag", "default", proCtx).Value)
+	assert.Equal(t, "pro-value", provider.StringEvaluation(context.Background(), "string-flag", "default", proCtx).Value)
+	// Other attributes are another key
+	assert.Equal(t, "free-value", provider.StringEvaluation(context.Background(), "string-flag", "default", freeCtx).Value)
+	// Failed evaluations are not cached
+	for i := 0; i < 2; i++ {
+		result := provider.StringEvaluation(context.Background(), "missing-flag", "default", proCtx)
+		assert.Equal(t, openfeature.FlagNotFoundCode, result.ResolutionDetail().ErrorCode)
+	}
+}
+
+func TestEvaluationCacheObjectCopy(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSDK := mockProvider.NewMockBucketeerSDK(ctrl)
+	mockSDK.EXPECT().
+		ObjectVariationDetails(gomock.Any(), gomock.Any(), "object-flag", gomock.Any()).
+		Return(model.BKTEvaluationDetails[interface{}]{
+			FeatureID:      "object-flag",
+			UserID:         "test-user",
+			VariationID:    "variation-limits",
+			VariationValue: map[string]interface{}{"limits": []interface{}{float64(1), float64(2)}},
+			Reason:         model.EvaluationReasonDefault,
+		}).
+		Times(1)
+	provider := newTestProvider(mockSDK, WithEvaluationCache(CacheConfig{}))
+	evalCtx := openfeature.FlattenedContext{openfeature.TargetingKey: "test-user"}
+
+	// Modifying an evaluated object doesn't modify the cached one
+	for i := 0; i < 3; i++ {
+		result := provider.ObjectEvaluation(context.Background(), "object-flag", nil, evalCtx)
+		value := result.Value.(map[string]interface{})
+		assert.Equal(t, map[string]interface{}{"limits": []interface{}{float64(1), float64(2)}}, value)
+		value["limits"].([]interface{})[0] = float64(100)
+		value["added"] = true
+	}
+}
+
+func TestEvaluationCacheErrorMapping(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSDK := mockProvider.NewMockBucketeerSDK(ctrl)
+	// The evaluations failing with the error mapping are not cached
+	mockSDK.EXPECT().
+		StringVariationDetails(gomock.Any(), gomock.Any(), "string-flag", "default").
+		Return(newStringEvaluation("value", model.EvaluationReasonDefault)).
+		Times(2)
+	// The errors mapped to a success are cached, and served with the default value of each caller
+	mockSDK.EXPECT().
+		StringVariationDetails(gomock.Any(), gomock.Any(), "missing-flag", "default-1").
+		Return(newStringEvaluation("default-1", model.EvaluationReasonErrorFlagNotFound)).
+		Times(1)
+	provider := newTestProvider(mockSDK,
+		WithEvaluationCache(CacheConfig{}),
+		WithErrorMapping(map[model.EvaluationReason]openfeature.ErrorCode{
+			model.EvaluationReasonDefault:           openfeature.GeneralCode,
+			model.EvaluationReasonErrorFlagNotFound: "",
+		}),
+	)
+	evalCtx := openfeature.FlattenedContext{openfeature.TargetingKey: "test-user"}
+
+	for i := 0; i < 2; i++ {
+		result := provider.StringEvaluation(context.Background(), "string-flag", "default", evalCtx)
+		assert.Equal(t, openfeature.GeneralCode, result.ResolutionDetail().ErrorCode)
+	}
+	for _, defaultValue := range []string{"default-1", "default-2"} {
+		result := provider.StringEvaluation(context.Background(), "missing-flag", defaultValue, evalCtx)
+		assert.Equal(t, defaultValue, result.Value)
+		assert.Empty(t, result.ResolutionDetail().ErrorCode)
+	}
+}
+
+func TestEvaluationCacheStaleWhileRevalidate(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	refreshed := make(chan struct{})
+	mockSDK := mockProvider.NewMockBucketeerSDK(ctrl)
+	gomock.InOrder(
+		mockSDK.EXPECT().
+			StringVariationDetails(gomock.Any(), gomock.Any(), "string-flag", "default").
+			Return(newStringEvaluation("old", model.EvaluationReasonDefault)),
+		mockSDK.EXPECT().
+			StringVariationDetails(gomock.Any(), gomock.Any(), "string-flag", "default").
+			DoAndReturn(func(ctx context.Context, _ any, _ string, _ string) model.BKTEvaluationDetails[string] {
+				// The refresh is not canceled with the evaluation
+				assert.NoError(t, ctx.Err())
+				<-refreshed
+				return newStringEvaluation("new", model.EvaluationReasonDefault)
+			}),
+		mockSDK.EXPECT().
+			StringVariationDetails(gomock.Any(), gomock.Any(), "string-flag", "default").
+			Return(newStringEvaluation("newest", model.EvaluationReasonDefault)),
+	)
+	mockSDK.EXPECT().Close(gomock.Any()).Return(nil)
+	provider := newTestProvider(mockSDK, WithEvaluationCache(CacheConfig{TTL: time.Minute, StaleTTL: time.Minute}))
+	clock := &fakeClock{}
+	provider.cache.now = clock.Now
+	evalCtx := openfeature.FlattenedContext{openfeature.TargetingKey: "test-user"}
+	evaluate := func() string {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		return provider.StringEvaluation(ctx, "string-flag", "default", evalCtx).Value
+	}
+
+	assert.Equal(t, "old", evaluate())
+	clock.Advance(90 * time.Second)
+	// The stale value is served while it's refreshed once in the background
+	assert.Equal(t, "old", evaluate())
+	assert.Equal(t, "old", evaluate())
+	close(refreshed)
+	require.Eventually(t, func() bool { return evaluate() == "new" }, time.Second, time.Millisecond)
+
+	// The value expires after the TTL and the stale TTL
+	clock.Advance(2 * time.Minute)
+	assert.Equal(t, "newest", evaluate())
+	assert.NoError(t, provider.ShutdownWithContext(context.Background()))
+}
+
+func TestEvaluationCacheEviction(t *testing.T) {
+	t.Parallel()
+	cache := newTestProvider(nil, WithEvaluationCache(CacheConfig{MaxEntries: 2})).cache
+	cache.set("a", 1)
+	cache.set("b", 2)
+	// a is the most recently used, so b is evicted
+	_, _, ok := cache.get("a")
+	assert.True(t, ok)
+	cache.set("c", 3)
+
+	_, _, ok = cache.get("b")
+	assert.False(t, ok)
+	for key, expected := range map[string]int{"a": 1, "c": 3} {
+		value, _, ok := cache.get(key)
+		assert.True(t, ok)
+		assert.Equal(t, expected, value)
+	}
+}
+
+func TestEvaluationCacheDo(t *testing.T) {
+	t.Parallel()
+	cache := newTestProvider(nil, WithEvaluationCache(CacheConfig{})).cache
+	var (
+		calls   atomic.Int32
+		started = make(chan struct{})
+		release = make(chan struct{})
+		wg      sync.WaitGroup
+	)
+	fn := func() (any, error) {
+		if calls.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+		return nil, errors.New("failed")
+	}
+
+	results := make([]error, 3)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, results[0] = cache.do("key", fn)
+	}()
+	<-started
+	for i := 1; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, results[i] = cache.do("key", fn)
+		}()
+	}
+	// Let the concurrent calls wait for the first one
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+	for _, err := range results {
+		assert.EqualError(t, err, "failed")
+	}
+}
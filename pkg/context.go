@@ -0,0 +1,187 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+const (
+	defaultContextSeparator = "."
+
+	// UserIDSourceAnonymous is the FlagMetadataUserIDSource value when the user ID
+	// is synthesized from the attributes set with WithAnonymousID.
+	UserIDSourceAnonymous = "anonymous"
+
+	anonymousIDPrefix = "anonymous-"
+)
+
+// contextConfig configures how evaluation contexts are converted to Bucketeer users
+type contextConfig struct {
+	// flattenNested flattens nested objects into namespaced attributes joined with separator
+	flattenNested bool
+	separator     string
+	// userIDAttribute is the attribute used as user ID instead of the targeting key
+	userIDAttribute string
+	// fallbackKeys are the attributes used as user ID, in order, if the user ID is missing
+	fallbackKeys []string
+	// anonymousAttributes are the attributes hashed into an anonymous ID if no fallback key is set
+	anonymousAttributes []string
+}
+
+// WithNestedContextFlattening flattens the nested objects of evaluation contexts
+// into namespaced attributes joined with the separator, "." if it's empty.
+// For example, {"org": {"id": "org-1", "plan": "pro"}} becomes the user attributes
+// "org.id" and "org.plan", so flags can target the organization plan.
+//
+// Only map[string]any values are flattened. Attributes set explicitly,
+// e.g. "org.id" at the top level, take precedence over flattened ones.
+// Without this option, nested objects are JSON-encoded.
+func WithNestedContextFlattening(separator string) Option {
+	return func(p *Provider) {
+		if separator == "" {
+			separator = defaultContextSeparator
+		}
+		p.contextConfig.flattenNested = true
+		p.contextConfig.separator = separator
+	}
+}
+
+// WithUserIDAttribute uses the attribute as the Bucketeer user ID instead of the targeting key,
+// e.g. "org.id" to evaluate the flags of an organization with WithNestedContextFlattening.
+//
+// The attribute must be set, otherwise evaluations fail with TARGETING_KEY_MISSING.
+// The targeting key, if any, is kept as the "targetingKey" user attribute.
+func WithUserIDAttribute(attribute string) Option {
+	return func(p *Provider) {
+		p.contextConfig.userIDAttribute = attribute
+	}
+}
+
+// WithTargetingKeyFallbacks uses the first of the attributes that is set as user ID,
+// if the targeting key, or the attribute set with WithUserIDAttribute, is missing.
+// For example, "sessionId" and "deviceId" let anonymous traffic be evaluated.
+//
+// The attribute used is reported in the FlagMetadataUserIDSource flag metadata,
+// and FlagMetadataAnonymous is set to true.
+func WithTargetingKeyFallbacks(keys ...string) Option {
+	return func(p *Provider) {
+		p.contextConfig.fallbackKeys = append(p.contextConfig.fallbackKeys, keys...)
+	}
+}
+
+// WithAnonymousID synthesizes a deterministic user ID by hashing the given attributes,
+// if neither the user ID nor a fallback key is set.
+// The same attribute values always get the same ID, e.g. ["ip", "userAgent"] for a visitor,
+// so anonymous users are consistently bucketed into the same variation.
+// The ID is only synthesized if at least one of the attributes is set.
+//
+// FlagMetadataUserIDSource is set to UserIDSourceAnonymous in the flag metadata,
+// and FlagMetadataAnonymous to true.
+func WithAnonymousID(attributes ...string) Option {
+	return func(p *Provider) {
+		p.contextConfig.anonymousAttributes = append(p.contextConfig.anonymousAttributes, attributes...)
+	}
+}
+
+// resolveUserID returns the user ID from the user data, falling back to the fallback keys and the anonymous ID.
+// The source is the fallback key or UserIDSourceAnonymous, or empty if the ID is the idKey attribute.
+// The ID is empty if none is set.
+func (c contextConfig) resolveUserID(data map[string]string, idKey string) (id string, source string) {
+	if id := data[idKey]; id != "" {
+		return id, ""
+	}
+	for _, key := range c.fallbackKeys {
+		if id := data[key]; id != "" {
+			return id, key
+		}
+	}
+	if id := c.anonymousID(data); id != "" {
+		return id, UserIDSourceAnonymous
+	}
+	return "", ""
+}
+
+// anonymousID hashes the anonymous attributes that are set, or returns an empty string if none is set
+func (c contextConfig) anonymousID(data map[string]string) string {
+	var b strings.Builder
+	for _, attr := range c.anonymousAttributes {
+		if v, ok := data[attr]; ok {
+			// The lengths prevent different attributes from having the same hash input
+			fmt.Fprintf(&b, "%d:%s=%d:%s;", len(attr), attr, len(v), v)
+		}
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(b.String()))
+	return anonymousIDPrefix + hex.EncodeToString(sum[:16])
+}
+
+// toUserData converts the evaluation context attributes to Bucketeer user data
+func (c contextConfig) toUserData(
+	evalCtx openfeature.FlattenedContext,
+) (map[string]string, *openfeature.ResolutionError) {
+	data := make(map[string]string, len(evalCtx))
+	var nested []string
+	for key, val := range evalCtx {
+		if _, ok := val.(map[string]any); ok && c.flattenNested {
+			nested = append(nested, key)
+			continue
+		}
+		if err := setUserData(data, key, val); err != nil {
+			return nil, err
+		}
+	}
+	// Flattened after the other attributes, so that explicit attributes take precedence
+	for _, key := range nested {
+		if err := c.flatten(data, key, evalCtx[key].(map[string]any)); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// flatten adds the attributes of a nested object prefixed with its key
+func (c contextConfig) flatten(
+	data map[string]string,
+	prefix string,
+	object map[string]any,
+) *openfeature.ResolutionError {
+	for key, val := range object {
+		key = prefix + c.separator + key
+		if nestedObject, ok := val.(map[string]any); ok {
+			if err := c.flatten(data, key, nestedObject); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, exists := data[key]; exists {
+			continue
+		}
+		if err := setUserData(data, key, val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setUserData sets the attribute as is if it's a string, JSON-encoded otherwise
+func setUserData(data map[string]string, key string, val any) *openfeature.ResolutionError {
+	if v, ok := val.(string); ok {
+		data[key] = v
+		return nil
+	}
+	jsonBytes, err := json.Marshal(val)
+	if err != nil {
+		return ToPtr(openfeature.NewParseErrorResolutionError(
+			fmt.Sprintf("key %q, value %v cannot be converted to JSON string: %v", key, val, err),
+		))
+	}
+	data[key] = string(jsonBytes)
+	return nil
+}
@@ -0,0 +1,327 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/model"
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/user"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	mockProvider "github.com/bucketeer-io/openfeature-go-server-sdk/test/mock/provider"
+)
+
+func TestToBucketeerUserWithContextConfig(t *testing.T) {
+	t.Parallel()
+	org := map[string]any{
+		"id":   "org-1",
+		"plan": "pro",
+		"billing": map[string]any{
+			"country": "jp",
+			"seats":   10,
+		},
+	}
+	tests := []struct {
+		desc          string
+		opts          []Option
+		evalCtx       openfeature.FlattenedContext
+		expectedUser  user.User
+		expectedError string
+	}{
+		{
+			desc: "nested objects JSON-encoded by default",
+			evalCtx: openfeature.FlattenedContext{
+				openfeature.TargetingKey: "user-1",
+				"org":                    map[string]any{"id": "org-1"},
+			},
+			expectedUser: user.User{
+				ID:   "user-1",
+				Data: map[string]string{"org": `{"id":"org-1"}`},
+			},
+		},
+		{
+			desc: "nested objects flattened",
+			opts: []Option{WithNestedContextFlattening("")},
+			evalCtx: openfeature.FlattenedContext{
+				openfeature.TargetingKey: "user-1",
+				"org":                    org,
+				"tags":                   []any{"a", "b"},
+			},
+			expectedUser: user.User{
+				ID: "user-1",
+				Data: map[string]string{
+					"org.id":              "org-1",
+					"org.plan":            "pro",
+					"org.billing.country": "jp",
+					"org.billing.seats":   "10",
+					"tags":                `["a","b"]`,
+				},
+			},
+		},
+		{
+			desc: "custom separator",
+			opts: []Option{WithNestedContextFlattening("_")},
+			evalCtx: openfeature.FlattenedContext{
+				openfeature.TargetingKey: "user-1",
+				"org":                    map[string]any{"plan": "pro"},
+			},
+			expectedUser: user.User{
+				ID:   "user-1",
+				Data: map[string]string{"org_plan": "pro"},
+			},
+		},
+		{
+			desc: "explicit attribute takes precedence",
+			opts: []Option{WithNestedContextFlattening(".")},
+			evalCtx: openfeature.FlattenedContext{
+				openfeature.TargetingKey: "user-1",
+				"org":                    map[string]any{"plan": "pro"},
+				"org.plan":               "enterprise",
+			},
+			expectedUser: user.User{
+				ID:   "user-1",
+				Data: map[string]string{"org.plan": "enterprise"},
+			},
+		},
+		{
+			desc: "user ID from entity",
+			opts: []Option{WithNestedContextFlattening("."), WithUserIDAttribute("org.id")},
+			evalCtx: openfeature.FlattenedContext{
+				openfeature.TargetingKey: "user-1",
+				"org":                    map[string]any{"id": "org-1", "plan": "pro"},
+			},
+			expectedUser: user.User{
+				ID: "org-1",
+				Data: map[string]string{
+					openfeature.TargetingKey: "user-1",
+					"org.id":                 "org-1",
+					"org.plan":               "pro",
+				},
+			},
+		},
+		{
+			desc: "user ID from attribute without targeting key",
+			opts: []Option{WithUserIDAttribute("device_id")},
+			evalCtx: openfeature.FlattenedContext{
+				"device_id": "device-1",
+			},
+			expectedUser: user.User{
+				ID:   "device-1",
+				Data: map[string]string{"device_id": "device-1"},
+			},
+		},
+		{
+			desc: "missing user ID attribute",
+			opts: []Option{WithNestedContextFlattening("."), WithUserIDAttribute("org.id")},
+			evalCtx: openfeature.FlattenedContext{
+				openfeature.TargetingKey: "user-1",
+				"org":                    map[string]any{"plan": "pro"},
+			},
+			expectedError: `TARGETING_KEY_MISSING: user ID attribute "org.id" is missing`,
+		},
+		{
+			desc: "nested attribute conversion error",
+			opts: []Option{WithNestedContextFlattening(".")},
+			evalCtx: openfeature.FlattenedContext{
+				openfeature.TargetingKey: "user-1",
+				"org":                    map[string]any{"fn": func() {}},
+			},
+			expectedError: `PARSE_ERROR: key "org.fn"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			p := newProvider(nil, test.opts...)
+			bucketeerUser, _, err := toBucketeerUser(test.evalCtx, p.contextConfig)
+			if test.expectedError != "" {
+				assert.ErrorContains(t, err, test.expectedError)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, test.expectedUser, bucketeerUser)
+		})
+	}
+}
+
+func TestResolveUserID(t *testing.T) {
+	t.Parallel()
+	anonymousID := func(input string) string {
+		sum := sha256.Sum256([]byte(input))
+		return "anonymous-" + hex.EncodeToString(sum[:16])
+	}
+	tests := []struct {
+		desc           string
+		opts           []Option
+		evalCtx        openfeature.FlattenedContext
+		expectedID     string
+		expectedSource string
+		expectedError  string
+	}{
+		{
+			desc:          "missing targeting key without fallback",
+			evalCtx:       openfeature.FlattenedContext{"sessionId": "session-1"},
+			expectedError: "TARGETING_KEY_MISSING: targeting key is missing",
+		},
+		{
+			desc: "targeting key takes precedence over fallbacks",
+			opts: []Option{WithTargetingKeyFallbacks("sessionId")},
+			evalCtx: openfeature.FlattenedContext{
+				openfeature.TargetingKey: "user-1",
+				"sessionId":              "session-1",
+			},
+			expectedID: "user-1",
+		},
+		{
+			desc: "first fallback set",
+			opts: []Option{WithTargetingKeyFallbacks("userId", "sessionId", "deviceId")},
+			evalCtx: openfeature.FlattenedContext{
+				"userId":    "",
+				"sessionId": "session-1",
+				"deviceId":  "device-1",
+			},
+			expectedID:     "session-1",
+			expectedSource: "sessionId",
+		},
+		{
+			desc: "fallback after user ID attribute",
+			opts: []Option{WithUserIDAttribute("accountId"), WithTargetingKeyFallbacks("deviceId")},
+			evalCtx: openfeature.FlattenedContext{
+				openfeature.TargetingKey: "user-1",
+				"deviceId":               "device-1",
+			},
+			expectedID:     "device-1",
+			expectedSource: "deviceId",
+		},
+		{
+			desc: "nested fallback",
+			opts: []Option{WithNestedContextFlattening("."), WithTargetingKeyFallbacks("device.id")},
+			evalCtx: openfeature.FlattenedContext{
+				"device": map[string]any{"id": "device-1"},
+			},
+			expectedID:     "device-1",
+			expectedSource: "device.id",
+		},
+		{
+			desc: "anonymous ID",
+			opts: []Option{WithTargetingKeyFallbacks("sessionId"), WithAnonymousID("ip", "userAgent", "country")},
+			evalCtx: openfeature.FlattenedContext{
+				"userAgent": "Mozilla/5.0",
+				"ip":        "192.0.2.1",
+			},
+			expectedID:     anonymousID("2:ip=9:192.0.2.1;9:userAgent=11:Mozilla/5.0;"),
+			expectedSource: UserIDSourceAnonymous,
+		},
+		{
+			desc:          "no anonymous attribute set",
+			opts:          []Option{WithAnonymousID("ip")},
+			evalCtx:       openfeature.FlattenedContext{"country": "jp"},
+			expectedError: "TARGETING_KEY_MISSING: targeting key is missing",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			p := newProvider(nil, test.opts...)
+			bucketeerUser, source, err := toBucketeerUser(test.evalCtx, p.contextConfig)
+			if test.expectedError != "" {
+				assert.ErrorContains(t, err, test.expectedError)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, test.expectedID, bucketeerUser.ID)
+			assert.Equal(t, test.expectedSource, source)
+		})
+	}
+}
+
+func TestAnonymousIDIsDeterministic(t *testing.T) {
+	t.Parallel()
+	config := newProvider(nil, WithAnonymousID("ip", "userAgent")).contextConfig
+
+	id := config.anonymousID(map[string]string{"ip": "192.0.2.1", "userAgent": "Mozilla/5.0", "page": "/a"})
+	assert.Equal(t, id, config.anonymousID(map[string]string{"userAgent": "Mozilla/5.0", "ip": "192.0.2.1"}))
+	assert.NotEqual(t, id, config.anonymousID(map[string]string{"ip": "192.0.2.2", "userAgent": "Mozilla/5.0"}))
+	// Values moved between attributes don't collide
+	assert.NotEqual(t,
+		config.anonymousID(map[string]string{"ip": "a", "userAgent": "b"}),
+		config.anonymousID(map[string]string{"ip": "a;9:userAgent=1:b", "userAgent": ""}),
+	)
+}
+
+func TestEvaluationWithNestedContext(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	expectedUser := &user.User{
+		ID:   "org-1",
+		Data: map[string]string{"org/id": "org-1", "org/plan": "pro"},
+	}
+	mockSDK := mockProvider.NewMockBucketeerSDK(ctrl)
+	mockSDK.EXPECT().
+		BoolVariationDetails(gomock.Any(), expectedUser, "bool-flag", false).
+		Return(model.BKTEvaluationDetails[bool]{
+			FeatureID:      "bool-flag",
+			UserID:         "org-1",
+			VariationID:    "variation-1",
+			VariationName:  "on",
+			VariationValue: true,
+			Reason:         model.EvaluationReasonRule,
+		}).
+		Times(1)
+	provider := newTestProvider(mockSDK, WithNestedContextFlattening("/"), WithUserIDAttribute("org/id"))
+
+	result := provider.BooleanEvaluation(
+		context.Background(),
+		"bool-flag",
+		false,
+		openfeature.FlattenedContext{"org": map[string]any{"id": "org-1", "plan": "pro"}},
+	)
+
+	assert.True(t, result.Value)
+	assert.Equal(t, openfeature.TargetingMatchReason, result.Reason)
+}
+
+func TestAnonymousEvaluationMetadata(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	expectedUser := &user.User{
+		ID:   "session-1",
+		Data: map[string]string{"sessionId": "session-1"},
+	}
+	mockSDK := mockProvider.NewMockBucketeerSDK(ctrl)
+	mockSDK.EXPECT().
+		StringVariationDetails(gomock.Any(), expectedUser, "string-flag", "default").
+		Return(model.BKTEvaluationDetails[string]{
+			FeatureID:      "string-flag",
+			UserID:         "session-1",
+			VariationValue: "value",
+			Reason:         model.EvaluationReasonDefault,
+		}).
+		Times(1)
+	provider := newTestProvider(mockSDK, WithTargetingKeyFallbacks("sessionId"))
+
+	result := provider.StringEvaluation(
+		context.Background(),
+		"string-flag",
+		"default",
+		openfeature.FlattenedContext{"sessionId": "session-1"},
+	)
+
+	assert.Equal(t, "value", result.Value)
+	anonymous, err := result.FlagMetadata.GetBool(FlagMetadataAnonymous)
+	assert.NoError(t, err)
+	assert.True(t, anonymous)
+	source, err := result.FlagMetadata.GetString(FlagMetadataUserIDSource)
+	assert.NoError(t, err)
+	assert.Equal(t, "sessionId", source)
+}
@@ -0,0 +1,375 @@
+// Package debug provides an http.Handler showing the state of the Bucketeer provider,
+// like net/http/pprof does for the runtime.
+//
+// The page shows the provider status, the SDK configuration with the API key redacted,
+// the last evaluations recorded by a Recorder, the stale flags reported with WithUsage and WithInspector,
+// and a form to run a test evaluation.
+// The same state is served as JSON at state.json under the handler path, and the usage report at usage.json.
+// The Bucketeer SDK doesn't report its cache age nor its event queue depth, so they aren't shown.
+//
+// Test evaluations are made with provider.WithoutEvents, so they aren't reported to Bucketeer.
+// They're disabled unless the provider has a dry-run SDK, see provider.WithDryRunSDK.
+//
+// The evaluations show user attributes, so the handler should only be served on an internal address.
+package debug
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+
+	provider "github.com/bucketeer-io/openfeature-go-server-sdk/pkg"
+	"github.com/bucketeer-io/openfeature-go-server-sdk/pkg/inspect"
+)
+
+const (
+	// maxFormSize limits the size of a test evaluation form.
+	maxFormSize = 64 << 10
+
+	redacted = "REDACTED"
+)
+
+var errNoDryRunSDK = errors.New(
+	"test evaluations are disabled: the provider has no dry-run SDK to evaluate without reporting events, " +
+		"see provider.WithDryRunSDK",
+)
+
+// Provider is the provider inspected by the Handler.
+// *provider.Provider implements this interface.
+type Provider interface {
+	openfeature.FeatureProvider
+	Status() openfeature.State
+	HasDryRunSDK() bool
+}
+
+// Config is the SDK configuration shown by the Handler.
+type Config struct {
+	Tag                   string `json:"tag,omitempty"`
+	APIEndpoint           string `json:"apiEndpoint,omitempty"`
+	Scheme                string `json:"scheme,omitempty"`
+	APIKey                string `json:"apiKey,omitempty"`
+	EnableLocalEvaluation bool   `json:"enableLocalEvaluation"`
+}
+
+// redact returns the configuration without the API key.
+func (c Config) redact() Config {
+	if c.APIKey != "" {
+		c.APIKey = redacted
+	}
+	return c
+}
+
+// Option configures a Handler.
+type Option func(*Handler)
+
+// WithConfig sets the SDK configuration shown by the handler.
+// The provider options can't be read back from the SDK, so they must be passed again.
+func WithConfig(config Config) Option {
+	return func(h *Handler) {
+		h.config = &config
+	}
+}
+
+// WithRecorder shows the evaluations recorded by the recorder.
+// The recorder must be added as a hook to see the evaluations.
+func WithRecorder(recorder *Recorder) Option {
+	return func(h *Handler) {
+		h.recorder = recorder
+	}
+}
+
+// UsageReporter reports the stale flags over a window.
+// *provider.Provider implements this interface.
+type UsageReporter interface {
+	UsageReport(window time.Duration, tagFlags ...string) (provider.UsageReport, bool)
+}
+
+// WithUsage shows the stale flags over the window, reported by a provider tracking the evaluations,
+// see provider.WithUsageTracking. The window of usage.json can be changed with the window query parameter,
+// e.g. usage.json?window=24h.
+func WithUsage(reporter UsageReporter, window time.Duration) Option {
+	return func(h *Handler) {
+		h.usage = reporter
+		h.usageWindow = window
+	}
+}
+
+// WithInspector compares the usage report with the flags of the tag listed by the inspector,
+// so the flags never evaluated are reported unused, see inspect.FromSDK.
+func WithInspector(inspector *inspect.Inspector) Option {
+	return func(h *Handler) {
+		h.inspector = inspector
+	}
+}
+
+// Handler serves the debug page of a provider.
+type Handler struct {
+	provider    Provider
+	config      *Config
+	recorder    *Recorder
+	usage       UsageReporter
+	usageWindow time.Duration
+	inspector   *inspect.Inspector
+}
+
+// NewHandler creates a Handler showing the state of the provider.
+// It can be served under any path, e.g. /debug/bucketeer/.
+func NewHandler(p Provider, opts ...Option) *Handler {
+	h := &Handler{
+		provider: p,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// State is the state shown by the Handler.
+type State struct {
+	Provider    string       `json:"provider"`
+	Status      string       `json:"status"`
+	Config      *Config      `json:"config,omitempty"`
+	Evaluations []Evaluation `json:"evaluations"`
+	// TestEvaluations is true if the provider can run test evaluations without reporting events
+	TestEvaluations bool `json:"testEvaluations"`
+	// Usage is reported with WithUsage
+	Usage *provider.UsageReport `json:"usage,omitempty"`
+}
+
+// TestEvaluation is a test evaluation submitted with the form.
+type TestEvaluation struct {
+	Flag         string
+	Type         string
+	DefaultValue string
+	TargetingKey string
+	Attributes   string
+}
+
+// TestResult is the result of a test evaluation.
+type TestResult struct {
+	Value        any
+	Variant      string
+	Reason       string
+	ErrorCode    string
+	ErrorMessage string
+	Metadata     openfeature.FlagMetadata
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodGet && path.Base(r.URL.Path) == "state.json":
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		_ = json.NewEncoder(w).Encode(h.state())
+	case r.Method == http.MethodGet && path.Base(r.URL.Path) == "usage.json":
+		h.usageReport(w, r)
+	case r.Method == http.MethodGet:
+		h.render(w, http.StatusOK, page{State: h.state()})
+	case r.Method == http.MethodPost:
+		h.testEvaluation(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) state() State {
+	state := State{
+		Provider:    h.provider.Metadata().Name,
+		Status:      string(h.provider.Status()),
+		Evaluations: []Evaluation{},
+		// Test evaluations must not be reported to Bucketeer
+		TestEvaluations: h.provider.HasDryRunSDK(),
+	}
+	if h.config != nil {
+		config := h.config.redact()
+		state.Config = &config
+	}
+	if h.recorder != nil {
+		state.Evaluations = h.recorder.Evaluations()
+	}
+	if h.usage != nil {
+		if report, ok := h.usage.UsageReport(h.usageWindow, h.tagFlags()...); ok {
+			state.Usage = &report
+		}
+	}
+	return state
+}
+
+// usageReport serves the usage report, over the window of the query if any
+func (h *Handler) usageReport(w http.ResponseWriter, r *http.Request) {
+	if h.usage == nil {
+		http.Error(w, "usage not reported, see WithUsage", http.StatusNotFound)
+		return
+	}
+	window := h.usageWindow
+	if s := r.URL.Query().Get("window"); s != "" {
+		var err error
+		if window, err = time.ParseDuration(s); err != nil {
+			http.Error(w, fmt.Sprintf("invalid window: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+	report, ok := h.usage.UsageReport(window, h.tagFlags()...)
+	if !ok {
+		http.Error(w, "usage not tracked, see provider.WithUsageTracking", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(report)
+}
+
+// tagFlags returns the IDs of the flags of the tag listed by the inspector, or nil if they can't be listed
+func (h *Handler) tagFlags() []string {
+	if h.inspector == nil {
+		return nil
+	}
+	// The flags can't be compared until they are polled
+	flags, err := h.inspector.Flags()
+	if err != nil {
+		return nil
+	}
+	ids := make([]string, 0, len(flags))
+	for _, f := range flags {
+		ids = append(ids, f.ID)
+	}
+	return ids
+}
+
+func (h *Handler) testEvaluation(w http.ResponseWriter, r *http.Request) {
+	if !h.provider.HasDryRunSDK() {
+		h.render(w, http.StatusNotImplemented, page{State: h.state(), Error: errNoDryRunSDK.Error()})
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxFormSize)
+	if err := r.ParseForm(); err != nil {
+		h.render(w, http.StatusBadRequest, page{State: h.state(), Error: err.Error()})
+		return
+	}
+	test := TestEvaluation{
+		Flag:         r.PostForm.Get("flag"),
+		Type:         r.PostForm.Get("type"),
+		DefaultValue: r.PostForm.Get("default"),
+		TargetingKey: r.PostForm.Get("targetingKey"),
+		Attributes:   r.PostForm.Get("attributes"),
+	}
+	result, err := evaluate(r.Context(), h.provider, test)
+	if err != nil {
+		h.render(w, http.StatusBadRequest, page{State: h.state(), Test: &test, Error: err.Error()})
+		return
+	}
+	h.render(w, http.StatusOK, page{State: h.state(), Test: &test, Result: result})
+}
+
+// evaluate runs the test evaluation with the provider, without reporting events to Bucketeer
+func evaluate(ctx context.Context, p Provider, test TestEvaluation) (*TestResult, error) {
+	if test.Flag == "" {
+		return nil, errors.New("flag is required")
+	}
+	ctx = provider.WithoutEvents(ctx)
+	evalCtx, err := parseAttributes(test.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	if test.TargetingKey != "" {
+		evalCtx[openfeature.TargetingKey] = test.TargetingKey
+	}
+
+	var (
+		value  any
+		detail openfeature.ProviderResolutionDetail
+	)
+	switch test.Type {
+	case openfeature.Boolean.String():
+		defaultValue, err := parseDefault(test.DefaultValue, strconv.ParseBool)
+		if err != nil {
+			return nil, err
+		}
+		res := p.BooleanEvaluation(ctx, test.Flag, defaultValue, evalCtx)
+		value, detail = res.Value, res.ProviderResolutionDetail
+	case openfeature.String.String():
+		res := p.StringEvaluation(ctx, test.Flag, test.DefaultValue, evalCtx)
+		value, detail = res.Value, res.ProviderResolutionDetail
+	case openfeature.Int.String():
+		defaultValue, err := parseDefault(test.DefaultValue, func(s string) (int64, error) {
+			return strconv.ParseInt(s, 10, 64)
+		})
+		if err != nil {
+			return nil, err
+		}
+		res := p.IntEvaluation(ctx, test.Flag, defaultValue, evalCtx)
+		value, detail = res.Value, res.ProviderResolutionDetail
+	case openfeature.Float.String():
+		defaultValue, err := parseDefault(test.DefaultValue, func(s string) (float64, error) {
+			return strconv.ParseFloat(s, 64)
+		})
+		if err != nil {
+			return nil, err
+		}
+		res := p.FloatEvaluation(ctx, test.Flag, defaultValue, evalCtx)
+		value, detail = res.Value, res.ProviderResolutionDetail
+	case openfeature.Object.String():
+		defaultValue, err := parseDefault(test.DefaultValue, func(s string) (any, error) {
+			var v any
+			err := json.Unmarshal([]byte(s), &v)
+			return v, err
+		})
+		if err != nil {
+			return nil, err
+		}
+		res := p.ObjectEvaluation(ctx, test.Flag, defaultValue, evalCtx)
+		value, detail = res.Value, res.ProviderResolutionDetail
+	default:
+		return nil, fmt.Errorf("unknown flag type %q", test.Type)
+	}
+
+	resolution := detail.ResolutionDetail()
+	return &TestResult{
+		Value:        value,
+		Variant:      resolution.Variant,
+		Reason:       string(resolution.Reason),
+		ErrorCode:    string(resolution.ErrorCode),
+		ErrorMessage: resolution.ErrorMessage,
+		Metadata:     resolution.FlagMetadata,
+	}, nil
+}
+
+// parseDefault parses the default value, returning the zero value if it's empty
+func parseDefault[T any](s string, parse func(string) (T, error)) (T, error) {
+	var zero T
+	if s == "" {
+		return zero, nil
+	}
+	v, err := parse(s)
+	if err != nil {
+		return zero, fmt.Errorf("invalid default value %q: %w", s, err)
+	}
+	return v, nil
+}
+
+// parseAttributes parses key=value attributes, one per line
+func parseAttributes(s string) (openfeature.FlattenedContext, error) {
+	evalCtx := openfeature.FlattenedContext{}
+	for line := range strings.Lines(s) {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid attribute %q, expected key=value", line)
+		}
+		evalCtx[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return evalCtx, nil
+}
@@ -0,0 +1,440 @@
+package debug
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	ftproto "github.com/bucketeer-io/bucketeer/v2/proto/feature"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	provider "github.com/bucketeer-io/openfeature-go-server-sdk/pkg"
+	"github.com/bucketeer-io/openfeature-go-server-sdk/pkg/inmemory"
+	"github.com/bucketeer-io/openfeature-go-server-sdk/pkg/inspect"
+)
+
+// fakeProvider returns the default value with the evaluation context as variant
+type fakeProvider struct {
+	openfeature.NoopProvider
+	noDryRunSDK bool
+}
+
+func (p *fakeProvider) Metadata() openfeature.Metadata {
+	return openfeature.Metadata{Name: "Bucketeer"}
+}
+
+func (p *fakeProvider) Status() openfeature.State {
+	return openfeature.ReadyState
+}
+
+func (p *fakeProvider) HasDryRunSDK() bool {
+	return !p.noDryRunSDK
+}
+
+func (p *fakeProvider) StringEvaluation(
+	ctx context.Context,
+	flag string,
+	defaultValue string,
+	evalCtx openfeature.FlattenedContext,
+) openfeature.StringResolutionDetail {
+	b, _ := json.Marshal(evalCtx)
+	return openfeature.StringResolutionDetail{
+		Value: defaultValue,
+		ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+			Variant:      string(b),
+			Reason:       openfeature.TargetingMatchReason,
+			FlagMetadata: openfeature.FlagMetadata{"bucketeerReason": "RULE"},
+		},
+	}
+}
+
+// fakeUsage reports a flag missing in Bucketeer over any window, and records the last window and flags of the tag
+type fakeUsage struct {
+	tracked  bool
+	window   time.Duration
+	tagFlags []string
+}
+
+func (u *fakeUsage) UsageReport(window time.Duration, tagFlags ...string) (provider.UsageReport, bool) {
+	u.window = window
+	u.tagFlags = tagFlags
+	if !u.tracked {
+		return provider.UsageReport{}, false
+	}
+	return provider.UsageReport{
+		Since:         testTime.Add(-window),
+		TrackingSince: testTime.Add(-window),
+		Flags: []provider.FlagUsage{{
+			Flag:          "renamed-flag",
+			Evaluations:   3,
+			Reasons:       map[string]uint64{"ERROR": 3},
+			ErrorCodes:    map[string]uint64{"FLAG_NOT_FOUND": 3},
+			LastReason:    "ERROR",
+			LastErrorCode: "FLAG_NOT_FOUND",
+			LastEvaluated: testTime,
+		}},
+		NotFound:    []string{"renamed-flag"},
+		DefaultOnly: []string{},
+		Unused:      []string{"old-flag"},
+	}, true
+}
+
+// fakeSource lists the flags of the tag
+type fakeSource struct{}
+
+func (fakeSource) Features() ([]*ftproto.Feature, error) {
+	return []*ftproto.Feature{{Id: "old-flag"}, {Id: "renamed-flag"}}, nil
+}
+
+func TestHandlerState(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		desc     string
+		provider *fakeProvider
+		opts     []Option
+		expected string
+	}{
+		{
+			desc:     "nothing reported",
+			provider: &fakeProvider{},
+			expected: `{"provider":"Bucketeer","status":"READY","evaluations":[],"testEvaluations":true}`,
+		},
+		{
+			desc:     "no dry-run SDK",
+			provider: &fakeProvider{noDryRunSDK: true},
+			expected: `{"provider":"Bucketeer","status":"READY","evaluations":[],"testEvaluations":false}`,
+		},
+		{
+			desc:     "API key redacted",
+			provider: &fakeProvider{},
+			opts: []Option{WithConfig(Config{
+				Tag:         "server",
+				APIEndpoint: "api.example.com",
+				Scheme:      "https",
+				APIKey:      "secret",
+			})},
+			expected: `{"provider":"Bucketeer","status":"READY","config":{"tag":"server",` +
+				`"apiEndpoint":"api.example.com","scheme":"https","apiKey":"REDACTED","enableLocalEvaluation":false},` +
+				`"evaluations":[],"testEvaluations":true}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			h := NewHandler(test.provider, test.opts...)
+
+			req := httptest.NewRequest(http.MethodGet, "/debug/bucketeer/state.json", nil)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+			assert.JSONEq(t, test.expected, rec.Body.String())
+		})
+	}
+}
+
+func TestHandlerUsage(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		desc             string
+		opts             func(usage *fakeUsage) []Option
+		tracked          bool
+		query            string
+		expectedStatus   int
+		expectedWindow   time.Duration
+		expectedTagFlags []string
+	}{
+		{
+			desc:           "report",
+			opts:           func(usage *fakeUsage) []Option { return []Option{WithUsage(usage, time.Hour)} },
+			tracked:        true,
+			expectedStatus: http.StatusOK,
+			expectedWindow: time.Hour,
+		},
+		{
+			desc:           "window of the query",
+			opts:           func(usage *fakeUsage) []Option { return []Option{WithUsage(usage, time.Hour)} },
+			tracked:        true,
+			query:          "?window=24h",
+			expectedStatus: http.StatusOK,
+			expectedWindow: 24 * time.Hour,
+		},
+		{
+			desc: "compared with the flags of the tag",
+			opts: func(usage *fakeUsage) []Option {
+				return []Option{WithUsage(usage, time.Hour), WithInspector(inspect.New(fakeSource{}))}
+			},
+			tracked:          true,
+			expectedStatus:   http.StatusOK,
+			expectedWindow:   time.Hour,
+			expectedTagFlags: []string{"old-flag", "renamed-flag"},
+		},
+		{
+			desc:           "invalid window",
+			opts:           func(usage *fakeUsage) []Option { return []Option{WithUsage(usage, time.Hour)} },
+			tracked:        true,
+			query:          "?window=1d",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			desc:           "usage not tracked",
+			opts:           func(usage *fakeUsage) []Option { return []Option{WithUsage(usage, time.Hour)} },
+			expectedStatus: http.StatusNotFound,
+			expectedWindow: time.Hour,
+		},
+		{
+			desc:           "usage not reported",
+			opts:           func(usage *fakeUsage) []Option { return nil },
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			usage := &fakeUsage{tracked: test.tracked}
+			h := NewHandler(&fakeProvider{}, test.opts(usage)...)
+
+			req := httptest.NewRequest(http.MethodGet, "/debug/bucketeer/usage.json"+test.query, nil)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.expectedStatus, rec.Code)
+			assert.Equal(t, test.expectedWindow, usage.window)
+			assert.Equal(t, test.expectedTagFlags, usage.tagFlags)
+			if test.expectedStatus != http.StatusOK {
+				return
+			}
+			var report provider.UsageReport
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+			assert.Equal(t, []string{"renamed-flag"}, report.NotFound)
+			assert.Equal(t, []string{"old-flag"}, report.Unused)
+		})
+	}
+}
+
+func TestHandlerPageUsage(t *testing.T) {
+	t.Parallel()
+	h := NewHandler(&fakeProvider{}, WithUsage(&fakeUsage{tracked: true}, time.Hour))
+
+	req := httptest.NewRequest(http.MethodGet, "/debug/bucketeer/", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, "<th>Not found in Bucketeer</th><td>renamed-flag</td>")
+	assert.Contains(t, body, "<th>Default only</th><td>none</td>")
+	assert.Contains(t, body, "<th>Unused (not compared with the tag)</th><td>old-flag</td>")
+	assert.Contains(t, body, "<td>renamed-flag</td><td>3</td>")
+
+	req = httptest.NewRequest(http.MethodGet, "/debug/bucketeer/state.json", nil)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	var state State
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &state))
+	require.NotNil(t, state.Usage)
+	assert.Equal(t, []string{"renamed-flag"}, state.Usage.NotFound)
+}
+
+func TestHandlerPage(t *testing.T) {
+	t.Parallel()
+	recorder := NewRecorder(10)
+	record(recorder, "recorded-flag")
+	h := NewHandler(
+		&fakeProvider{},
+		WithRecorder(recorder),
+		WithConfig(Config{Tag: "server", APIKey: "secret"}),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/debug/bucketeer/", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+	body := rec.Body.String()
+	assert.Contains(t, body, "<td>READY</td>")
+	assert.Contains(t, body, "<td>REDACTED</td>")
+	assert.NotContains(t, body, "secret")
+	assert.Contains(t, body, "<td>recorded-flag</td>")
+	assert.Contains(t, body, "<td>RULE</td>")
+}
+
+func TestHandlerTestEvaluation(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		desc           string
+		form           url.Values
+		expectedStatus int
+		expectedBody   []string
+	}{
+		{
+			desc: "evaluated",
+			form: url.Values{
+				"flag":         {"string-flag"},
+				"type":         {"string"},
+				"default":      {"default"},
+				"targetingKey": {"user-1"},
+				"attributes":   {"country = jp\n\nplan=<pro>\n"},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: []string{
+				`<td>&#34;default&#34;</td>`,
+				`<td>{&#34;country&#34;:&#34;jp&#34;,&#34;plan&#34;:&#34;\u003cpro\u003e&#34;,&#34;targetingKey&#34;:&#34;user-1&#34;}</td>`,
+				`<td>TARGETING_MATCH</td>`,
+				`value="string-flag"`,
+				`<option selected>string</option>`,
+				`plan=&lt;pro&gt;`,
+			},
+		},
+		{
+			desc: "default type value",
+			form: url.Values{
+				"flag":    {"int-flag"},
+				"type":    {"int"},
+				"default": {"42"},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   []string{`<td>42</td>`, `<td>DEFAULT</td>`},
+		},
+		{
+			desc:           "missing flag",
+			form:           url.Values{"type": {"bool"}},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   []string{"flag is required"},
+		},
+		{
+			desc:           "unknown type",
+			form:           url.Values{"flag": {"flag"}, "type": {"date"}},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   []string{"unknown flag type &#34;date&#34;"},
+		},
+		{
+			desc:           "invalid default value",
+			form:           url.Values{"flag": {"flag"}, "type": {"float"}, "default": {"abc"}},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   []string{"invalid default value &#34;abc&#34;"},
+		},
+		{
+			desc:           "invalid attribute",
+			form:           url.Values{"flag": {"flag"}, "type": {"bool"}, "attributes": {"country"}},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   []string{"invalid attribute &#34;country&#34;, expected key=value"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			h := NewHandler(&fakeProvider{})
+
+			req := httptest.NewRequest(http.MethodPost, "/debug/bucketeer/", strings.NewReader(test.form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.expectedStatus, rec.Code)
+			for _, s := range test.expectedBody {
+				assert.Contains(t, rec.Body.String(), s)
+			}
+		})
+	}
+}
+
+func TestHandlerTestEvaluationWithoutEvents(t *testing.T) {
+	t.Parallel()
+	flag := func(value string) map[string]inmemory.Flag {
+		return map[string]inmemory.Flag{"string-flag": {
+			Variations:       []inmemory.Variation{{ID: "variation", Name: "variation", Value: value}},
+			DefaultVariation: "variation",
+		}}
+	}
+	p, err := provider.NewProviderFromSDK(
+		inmemory.New(flag("main")),
+		provider.WithDryRunSDK(inmemory.New(flag("dry-run"))),
+	)
+	require.NoError(t, err)
+	h := NewHandler(p)
+
+	form := url.Values{"flag": {"string-flag"}, "type": {"string"}, "targetingKey": {"user-1"}}
+	req := httptest.NewRequest(http.MethodPost, "/debug/bucketeer/", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `<td>&#34;dry-run&#34;</td>`)
+	assert.NotContains(t, rec.Body.String(), `&#34;main&#34;`)
+}
+
+func TestHandlerTestEvaluationWithoutDryRunSDK(t *testing.T) {
+	t.Parallel()
+	h := NewHandler(&fakeProvider{noDryRunSDK: true})
+
+	req := httptest.NewRequest(http.MethodGet, "/debug/bucketeer/", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "<form")
+	assert.Contains(t, rec.Body.String(), "Disabled: the provider has no dry-run SDK")
+
+	form := url.Values{"flag": {"string-flag"}, "type": {"string"}}
+	req = httptest.NewRequest(http.MethodPost, "/debug/bucketeer/", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotImplemented, rec.Code)
+	assert.Contains(t, rec.Body.String(), "test evaluations are disabled")
+}
+
+func TestEvaluateTypes(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		flagType string
+		value    string
+		expected any
+	}{
+		{flagType: "bool", value: "true", expected: true},
+		{flagType: "bool", value: "", expected: false},
+		{flagType: "string", value: "text", expected: "text"},
+		{flagType: "int", value: "42", expected: int64(42)},
+		{flagType: "float", value: "1.5", expected: 1.5},
+		{flagType: "object", value: `{"a":1}`, expected: map[string]any{"a": float64(1)}},
+		{flagType: "object", value: "", expected: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.flagType+"/"+test.value, func(t *testing.T) {
+			t.Parallel()
+			result, err := evaluate(context.Background(), &fakeProvider{}, TestEvaluation{
+				Flag:         "flag",
+				Type:         test.flagType,
+				DefaultValue: test.value,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, result.Value)
+		})
+	}
+}
+
+func TestHandlerMethodNotAllowed(t *testing.T) {
+	t.Parallel()
+	h := NewHandler(&fakeProvider{})
+
+	req := httptest.NewRequest(http.MethodDelete, "/debug/bucketeer/", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "GET, POST", rec.Header().Get("Allow"))
+}
@@ -0,0 +1,45 @@
+package debug
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"html/template"
+	"net/http"
+)
+
+// page is the data rendered by pageTemplate
+type page struct {
+	State  State
+	Test   *TestEvaluation
+	Result *TestResult
+	Error  string
+}
+
+//go:embed page.html
+var templates embed.FS
+
+var pageTemplate = template.Must(template.New("page.html").Funcs(template.FuncMap{
+	"json": func(v any) string {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err.Error()
+		}
+		return string(b)
+	},
+	"types": func() []string {
+		return []string{"bool", "string", "int", "float", "object"}
+	},
+}).ParseFS(templates, "page.html"))
+
+func (h *Handler) render(w http.ResponseWriter, status int, data page) {
+	var buf bytes.Buffer
+	if err := pageTemplate.Execute(&buf, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_, _ = w.Write(buf.Bytes())
+}
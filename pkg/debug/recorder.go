@@ -0,0 +1,100 @@
+package debug
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+
+	provider "github.com/bucketeer-io/openfeature-go-server-sdk/pkg"
+)
+
+const defaultRecorderSize = 100
+
+// Evaluation is an evaluation kept by a Recorder.
+type Evaluation struct {
+	Time            time.Time      `json:"time"`
+	Flag            string         `json:"flag"`
+	Type            string         `json:"type"`
+	TargetingKey    string         `json:"targetingKey,omitempty"`
+	Attributes      map[string]any `json:"attributes,omitempty"`
+	DefaultValue    any            `json:"defaultValue"`
+	Value           any            `json:"value"`
+	Variant         string         `json:"variant,omitempty"`
+	Reason          string         `json:"reason"`
+	BucketeerReason string         `json:"bucketeerReason,omitempty"`
+	ErrorCode       string         `json:"errorCode,omitempty"`
+	ErrorMessage    string         `json:"errorMessage,omitempty"`
+}
+
+// Recorder is an OpenFeature hook keeping the last evaluations, with their inputs and results.
+type Recorder struct {
+	openfeature.UnimplementedHook
+
+	now func() time.Time
+
+	mu          sync.Mutex
+	evaluations []Evaluation
+	// next is the index of the next evaluation in evaluations, which is a ring buffer once full
+	next int
+}
+
+// NewRecorder creates a Recorder keeping the last size evaluations, 100 if size isn't positive.
+func NewRecorder(size int) *Recorder {
+	if size <= 0 {
+		size = defaultRecorderSize
+	}
+	return &Recorder{
+		now:         time.Now,
+		evaluations: make([]Evaluation, 0, size),
+	}
+}
+
+// Finally records the evaluation.
+func (r *Recorder) Finally(
+	ctx context.Context,
+	hookCtx openfeature.HookContext,
+	details openfeature.InterfaceEvaluationDetails,
+	hints openfeature.HookHints,
+) {
+	evalCtx := hookCtx.EvaluationContext()
+	evaluation := Evaluation{
+		Time:         r.now(),
+		Flag:         details.FlagKey,
+		Type:         details.FlagType.String(),
+		TargetingKey: evalCtx.TargetingKey(),
+		Attributes:   maps.Clone(evalCtx.Attributes()),
+		DefaultValue: hookCtx.DefaultValue(),
+		Value:        details.Value,
+		Variant:      details.Variant,
+		Reason:       string(details.Reason),
+		ErrorCode:    string(details.ErrorCode),
+		ErrorMessage: details.ErrorMessage,
+	}
+	if reason, err := details.FlagMetadata.GetString(provider.FlagMetadataBucketeerReason); err == nil {
+		evaluation.BucketeerReason = reason
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.evaluations) < cap(r.evaluations) {
+		r.evaluations = append(r.evaluations, evaluation)
+	} else {
+		r.evaluations[r.next] = evaluation
+	}
+	r.next = (r.next + 1) % cap(r.evaluations)
+}
+
+// Evaluations returns the recorded evaluations, the newest first.
+func (r *Recorder) Evaluations() []Evaluation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	evaluations := make([]Evaluation, 0, len(r.evaluations))
+	for i := range len(r.evaluations) {
+		j := (r.next - 1 - i + len(r.evaluations)) % len(r.evaluations)
+		evaluations = append(evaluations, r.evaluations[j])
+	}
+	return evaluations
+}
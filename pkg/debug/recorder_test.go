@@ -0,0 +1,86 @@
+package debug
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+
+	provider "github.com/bucketeer-io/openfeature-go-server-sdk/pkg"
+)
+
+var testTime = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func record(r *Recorder, flag string) {
+	hookCtx := openfeature.NewHookContext(
+		flag,
+		openfeature.Boolean,
+		false,
+		openfeature.NewClientMetadata(""),
+		openfeature.Metadata{Name: "Bucketeer"},
+		openfeature.NewEvaluationContext("user-1", map[string]any{"country": "jp"}),
+	)
+	details := openfeature.InterfaceEvaluationDetails{
+		Value: true,
+		EvaluationDetails: openfeature.EvaluationDetails{
+			FlagKey:  flag,
+			FlagType: openfeature.Boolean,
+			ResolutionDetail: openfeature.ResolutionDetail{
+				Variant: "on",
+				Reason:  openfeature.TargetingMatchReason,
+				FlagMetadata: openfeature.FlagMetadata{
+					provider.FlagMetadataBucketeerReason: "RULE",
+				},
+			},
+		},
+	}
+	r.Finally(context.Background(), hookCtx, details, openfeature.HookHints{})
+}
+
+func recordedFlags(r *Recorder) []string {
+	var flags []string
+	for _, e := range r.Evaluations() {
+		flags = append(flags, e.Flag)
+	}
+	return flags
+}
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+	r := NewRecorder(2)
+	r.now = func() time.Time { return testTime }
+
+	assert.Empty(t, r.Evaluations())
+
+	record(r, "flag-0")
+	assert.Equal(t, []Evaluation{{
+		Time:            testTime,
+		Flag:            "flag-0",
+		Type:            "bool",
+		TargetingKey:    "user-1",
+		Attributes:      map[string]any{"country": "jp"},
+		DefaultValue:    false,
+		Value:           true,
+		Variant:         "on",
+		Reason:          "TARGETING_MATCH",
+		BucketeerReason: "RULE",
+	}}, r.Evaluations())
+
+	record(r, "flag-1")
+	assert.Equal(t, []string{"flag-1", "flag-0"}, recordedFlags(r))
+	record(r, "flag-2")
+	assert.Equal(t, []string{"flag-2", "flag-1"}, recordedFlags(r))
+	record(r, "flag-3")
+	assert.Equal(t, []string{"flag-3", "flag-2"}, recordedFlags(r))
+}
+
+func TestNewRecorderDefaultSize(t *testing.T) {
+	t.Parallel()
+	r := NewRecorder(0)
+	for range defaultRecorderSize + 1 {
+		record(r, "flag")
+	}
+	assert.Len(t, r.Evaluations(), defaultRecorderSize)
+}
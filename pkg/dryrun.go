@@ -0,0 +1,75 @@
+package provider
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNoDryRunSDK is the error of the evaluations made with WithoutEvents when the provider has no dry-run SDK.
+// NewProvider returns it if WithEventsDisabled is set without WithDryRunSDK.
+var ErrNoDryRunSDK = errors.New("events can't be disabled without a dry-run SDK, see WithDryRunSDK")
+
+// withoutEventsKey is the context key set by WithoutEvents
+type withoutEventsKey struct{}
+
+// WithoutEvents returns a context evaluating the flags without reporting evaluation or goal events to Bucketeer,
+// e.g. for batch jobs, previews or health checks, which would skew the experiment analytics.
+//
+// The flags are evaluated by the SDK set with WithDryRunSDK, which polls the flags on its own
+// and evaluates them locally. Without it, the evaluations fail with a GENERAL error and the ErrNoDryRunSDK message
+// rather than reporting events, so the default value is returned, see HasDryRunSDK. The goal events are dropped.
+func WithoutEvents(ctx context.Context) context.Context {
+	return context.WithValue(ctx, withoutEventsKey{}, true)
+}
+
+// WithDryRunSDK sets the SDK evaluating the flags without reporting events, used with WithoutEvents
+// and WithEventsDisabled. It's typically a dryrun.SDK polling the flags of the same tag, which evaluates them
+// locally like the Bucketeer SDK in local-evaluation mode, so the API key must allow it.
+// The SDK is closed when the provider is shut down.
+func WithDryRunSDK(sdk BucketeerSDK) Option {
+	return func(p *Provider) {
+		p.dryRunSDK = sdk
+	}
+}
+
+// WithEventsDisabled evaluates every flag with the SDK set with WithDryRunSDK and drops the goal events,
+// as if every call was made with WithoutEvents. The provider can't be created without WithDryRunSDK.
+func WithEventsDisabled() Option {
+	return func(p *Provider) {
+		p.eventsDisabled = true
+	}
+}
+
+// reportsEvents returns false if the events of the call must not be reported
+func (p *Provider) reportsEvents(ctx context.Context) bool {
+	if p.eventsDisabled {
+		return false
+	}
+	disabled, _ := ctx.Value(withoutEventsKey{}).(bool)
+	return !disabled
+}
+
+// sdkFor returns the SDK evaluating the flags of the call: the dry-run SDK if the events must not be reported.
+// The second value is true if the dry-run SDK is returned.
+func (p *Provider) sdkFor(ctx context.Context) (BucketeerSDK, bool, error) {
+	if p.reportsEvents(ctx) {
+		return p.sdk, false, nil
+	}
+	if p.dryRunSDK == nil {
+		return nil, false, ErrNoDryRunSDK
+	}
+	return p.dryRunSDK, true, nil
+}
+
+// HasDryRunSDK returns true if the provider can evaluate flags without reporting events, see WithDryRunSDK.
+func (p *Provider) HasDryRunSDK() bool {
+	return p.dryRunSDK != nil
+}
+
+// validate returns an error if the options can't work together
+func (p *Provider) validate() error {
+	if p.eventsDisabled && p.dryRunSDK == nil {
+		return ErrNoDryRunSDK
+	}
+	return nil
+}
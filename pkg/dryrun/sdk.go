@@ -0,0 +1,259 @@
+// Package dryrun provides a Bucketeer SDK evaluating flags locally without sending any event to Bucketeer,
+// so evaluations made by simulations, previews or batch jobs don't affect the experiment analytics.
+//
+// Like the Bucketeer SDK in local-evaluation mode, it polls the flags and segment users
+// and evaluates them in memory.
+package dryrun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	ftproto "github.com/bucketeer-io/bucketeer/v2/proto/feature"
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer"
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/api"
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/cache"
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/cache/processor"
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/evaluator"
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/log"
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/model"
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/user"
+
+	provider "github.com/bucketeer-io/openfeature-go-server-sdk/pkg"
+	"github.com/bucketeer-io/openfeature-go-server-sdk/pkg/internal/variation"
+	"github.com/bucketeer-io/openfeature-go-server-sdk/pkg/version"
+)
+
+var _ provider.BucketeerSDK = (*SDK)(nil)
+
+const (
+	defaultScheme          = "https"
+	defaultPollingInterval = time.Minute
+	readyCheckInterval     = 100 * time.Millisecond
+
+	// featureKeyPrefix is the prefix of the flag keys in the Bucketeer SDK cache.
+	// The SDK doesn't export it, so TestFeatureKeyPrefix checks it against the keys written by the SDK.
+	featureKeyPrefix = "bucketeer_feature_flag:"
+)
+
+// ErrNotReady is returned by Features until the flags have been polled
+var ErrNotReady = errors.New("flags not polled yet")
+
+// Config configures the connection to Bucketeer.
+type Config struct {
+	APIKey      string
+	APIEndpoint string
+	// Scheme is "http" or "https". Defaults to "https".
+	Scheme string
+	Tag    string
+	// PollingInterval is the interval of polling the flags and segment users. Defaults to 1 minute.
+	PollingInterval time.Duration
+	// ErrorLogger logs the polling errors. Defaults to the Bucketeer SDK's default error logger.
+	ErrorLogger log.BaseLogger
+}
+
+// SDK evaluates flags locally without sending evaluation, goal or metrics events.
+type SDK struct {
+	evaluator evaluator.EvaluateLocally
+	keys      cache.Scanner
+	features  cache.FeaturesCache
+	ready     func() bool
+	close     func()
+	closeOnce sync.Once
+}
+
+// NewSDK creates an SDK and starts polling the flags and segment users in the background.
+// Evaluations return the default value with the ERROR_CACHE_NOT_FOUND reason until the first poll succeeds,
+// see WaitReady.
+func NewSDK(conf Config) (*SDK, error) {
+	if conf.Scheme == "" {
+		conf.Scheme = defaultScheme
+	}
+	if conf.PollingInterval <= 0 {
+		conf.PollingInterval = defaultPollingInterval
+	}
+	if conf.ErrorLogger == nil {
+		conf.ErrorLogger = log.DefaultErrorLogger
+	}
+	client, err := api.NewClient(&api.ClientConfig{
+		APIKey:      conf.APIKey,
+		APIEndpoint: conf.APIEndpoint,
+		Scheme:      conf.Scheme,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create api client: %w", err)
+	}
+
+	loggers := log.NewLoggers(&log.LoggersConfig{ErrorLogger: conf.ErrorLogger})
+	c := cache.NewInMemoryCache()
+	flagProcessor := processor.NewFeatureFlagProcessor(&processor.FeatureFlagProcessorConfig{
+		Cache:                   c,
+		PollingInterval:         conf.PollingInterval,
+		APIClient:               client,
+		PushLatencyMetricsEvent: func(time.Duration, model.APIID) {},
+		PushSizeMetricsEvent:    func(int, model.APIID) {},
+		PushErrorEvent:          func(error, model.APIID) {},
+		Loggers:                 loggers,
+		Tag:                     conf.Tag,
+		SDKVersion:              version.SDKVersion,
+		SourceID:                model.SourceIDOpenFeatureGo,
+	})
+	segmentProcessor := processor.NewSegmentUserProcessor(&processor.SegmentUserProcessorConfig{
+		Cache:                   c,
+		PollingInterval:         conf.PollingInterval,
+		APIClient:               client,
+		PushLatencyMetricsEvent: func(time.Duration, model.APIID) {},
+		PushSizeMetricsEvent:    func(int, model.APIID) {},
+		PushErrorEvent:          func(error, model.APIID) {},
+		Loggers:                 loggers,
+		Tag:                     conf.Tag,
+		SDKVersion:              version.SDKVersion,
+		SourceID:                model.SourceIDOpenFeatureGo,
+	})
+	flagProcessor.Run()
+	segmentProcessor.Run()
+
+	features := cache.NewFeaturesCache(c)
+	return &SDK{
+		evaluator: evaluator.NewEvaluator(conf.Tag, features, cache.NewSegmentUsersCache(c)),
+		keys:      c,
+		features:  features,
+		ready: func() bool {
+			return flagProcessor.IsReady() && segmentProcessor.IsReady()
+		},
+		close: func() {
+			flagProcessor.Close()
+			segmentProcessor.Close()
+			c.Destroy()
+		},
+	}, nil
+}
+
+// WaitReady waits until the flags and segment users have been polled, or ctx is done
+func (s *SDK) WaitReady(ctx context.Context) error {
+	ticker := time.NewTicker(readyCheckInterval)
+	defer ticker.Stop()
+	for !s.ready() {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("flags not polled: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// BoolVariationDetails evaluates a boolean flag
+func (s *SDK) BoolVariationDetails(
+	ctx context.Context,
+	user *user.User,
+	featureID string,
+	defaultValue bool,
+) model.BKTEvaluationDetails[bool] {
+	return variation.Details(s.evaluate, user, featureID, defaultValue, variation.ParseBool)
+}
+
+// StringVariationDetails evaluates a string flag
+func (s *SDK) StringVariationDetails(
+	ctx context.Context,
+	user *user.User,
+	featureID string,
+	defaultValue string,
+) model.BKTEvaluationDetails[string] {
+	return variation.Details(s.evaluate, user, featureID, defaultValue, variation.ParseString)
+}
+
+// Int64VariationDetails evaluates an integer flag.
+// Like the Bucketeer SDK, a float value is truncated.
+func (s *SDK) Int64VariationDetails(
+	ctx context.Context,
+	user *user.User,
+	featureID string,
+	defaultValue int64,
+) model.BKTEvaluationDetails[int64] {
+	return variation.Details(s.evaluate, user, featureID, defaultValue, variation.ParseInt64)
+}
+
+// Float64VariationDetails evaluates a float flag
+func (s *SDK) Float64VariationDetails(
+	ctx context.Context,
+	user *user.User,
+	featureID string,
+	defaultValue float64,
+) model.BKTEvaluationDetails[float64] {
+	return variation.Details(s.evaluate, user, featureID, defaultValue, variation.ParseFloat64)
+}
+
+// ObjectVariationDetails evaluates a JSON flag
+func (s *SDK) ObjectVariationDetails(
+	ctx context.Context,
+	user *user.User,
+	featureID string,
+	defaultValue interface{},
+) model.BKTEvaluationDetails[interface{}] {
+	return variation.Details(s.evaluate, user, featureID, defaultValue, variation.ParseObject)
+}
+
+// Features returns the flags of the cache, sorted by ID, e.g. to inspect them with the inspect package.
+// It returns ErrNotReady until the flags have been polled.
+func (s *SDK) Features() ([]*ftproto.Feature, error) {
+	if !s.ready() {
+		return nil, ErrNotReady
+	}
+	keys, err := s.keys.Scan(featureKeyPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the flags: %w", err)
+	}
+	features := make([]*ftproto.Feature, 0, len(keys))
+	for _, key := range keys {
+		feature, err := s.features.Get(strings.TrimPrefix(key, featureKeyPrefix))
+		if errors.Is(err, cache.ErrNotFound) {
+			// Deleted by a concurrent poll
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get flag %s: %w", key, err)
+		}
+		features = append(features, feature)
+	}
+	slices.SortFunc(features, func(a, b *ftproto.Feature) int {
+		return strings.Compare(a.Id, b.Id)
+	})
+	return features, nil
+}
+
+// Close stops polling. There are no events to flush.
+func (s *SDK) Close(ctx context.Context) error {
+	s.closeOnce.Do(s.close)
+	return nil
+}
+
+// evaluate evaluates the flag like the Bucketeer SDK in local-evaluation mode, without pushing events,
+// see variation.EvaluateFunc
+func (s *SDK) evaluate(u *user.User, featureID string) (model.BKTEvaluationDetails[string], bool) {
+	if !s.ready() {
+		return model.NewEvaluationDetails(featureID, u.ID, "", "", 0, model.ReasonErrorCacheNotFound, ""), false
+	}
+	evaluation, err := s.evaluator.Evaluate(u, featureID)
+	if err == nil && evaluation.Reason == nil {
+		err = errors.New("evaluation without reason")
+	}
+	if err != nil {
+		reason := bucketeer.MapErrorToReason(err, true, featureID)
+		return model.NewEvaluationDetails(featureID, u.ID, "", "", 0, reason, ""), false
+	}
+	return model.NewEvaluationDetails(
+		featureID,
+		u.ID,
+		evaluation.VariationID,
+		evaluation.VariationName,
+		evaluation.FeatureVersion,
+		evaluation.Reason.Type,
+		evaluation.VariationValue,
+	), true
+}
@@ -0,0 +1,228 @@
+package dryrun
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ftproto "github.com/bucketeer-io/bucketeer/v2/proto/feature"
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/cache"
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/log"
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/model"
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/user"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeEvaluator serves a fixed evaluation for each flag
+type fakeEvaluator map[string]*model.Evaluation
+
+func (e fakeEvaluator) Evaluate(user *user.User, featureID string) (*model.Evaluation, error) {
+	evaluation, ok := e[featureID]
+	if !ok {
+		return nil, cache.ErrNotFound
+	}
+	return evaluation, nil
+}
+
+func newTestSDK(ready bool) *SDK {
+	return &SDK{
+		evaluator: fakeEvaluator{
+			"feature": {
+				FeatureID:      "feature",
+				FeatureVersion: 3,
+				VariationID:    "variation-on",
+				VariationName:  "on",
+				VariationValue: "true",
+				Reason:         &model.Reason{Type: model.ReasonRule},
+			},
+			"text": {
+				FeatureID:      "text",
+				VariationID:    "variation-text",
+				VariationValue: "hello",
+				Reason:         &model.Reason{Type: model.ReasonDefault},
+			},
+		},
+		ready: func() bool { return ready },
+		close: func() {},
+	}
+}
+
+func TestBoolVariationDetails(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		desc     string
+		ready    bool
+		user     *user.User
+		flag     string
+		expected model.BKTEvaluationDetails[bool]
+	}{
+		{
+			desc:  "evaluated",
+			ready: true,
+			user:  user.NewUser("user", nil),
+			flag:  "feature",
+			expected: model.BKTEvaluationDetails[bool]{
+				FeatureID:      "feature",
+				FeatureVersion: 3,
+				UserID:         "user",
+				VariationID:    "variation-on",
+				VariationName:  "on",
+				VariationValue: true,
+				Reason:         model.EvaluationReasonRule,
+			},
+		},
+		{
+			desc:  "flag not found",
+			ready: true,
+			user:  user.NewUser("user", nil),
+			flag:  "missing",
+			expected: model.BKTEvaluationDetails[bool]{
+				FeatureID:      "missing",
+				UserID:         "user",
+				VariationValue: false,
+				Reason:         model.EvaluationReasonErrorFlagNotFound,
+			},
+		},
+		{
+			desc:  "wrong type",
+			ready: true,
+			user:  user.NewUser("user", nil),
+			flag:  "text",
+			expected: model.BKTEvaluationDetails[bool]{
+				FeatureID:      "text",
+				UserID:         "user",
+				VariationValue: false,
+				Reason:         model.EvaluationReasonErrorWrongType,
+			},
+		},
+		{
+			desc:  "not polled yet",
+			ready: false,
+			user:  user.NewUser("user", nil),
+			flag:  "feature",
+			expected: model.BKTEvaluationDetails[bool]{
+				FeatureID:      "feature",
+				UserID:         "user",
+				VariationValue: false,
+				Reason:         model.EvaluationReasonErrorCacheNotFound,
+			},
+		},
+		{
+			desc:  "user ID not specified",
+			ready: true,
+			user:  nil,
+			flag:  "feature",
+			expected: model.BKTEvaluationDetails[bool]{
+				FeatureID:      "feature",
+				VariationValue: false,
+				Reason:         model.EvaluationReasonErrorUserIDNotSpecified,
+			},
+		},
+		{
+			desc:  "feature ID not specified",
+			ready: true,
+			user:  user.NewUser("user", nil),
+			flag:  "",
+			expected: model.BKTEvaluationDetails[bool]{
+				UserID:         "user",
+				VariationValue: false,
+				Reason:         model.EvaluationReasonErrorFeatureFlagIDNotSpecified,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			sdk := newTestSDK(test.ready)
+			assert.Equal(t, test.expected, sdk.BoolVariationDetails(context.Background(), test.user, test.flag, false))
+		})
+	}
+}
+
+func TestValueParsing(t *testing.T) {
+	t.Parallel()
+	sdk := newTestSDK(true)
+	u := user.NewUser("user", nil)
+
+	assert.Equal(t, "hello", sdk.StringVariationDetails(context.Background(), u, "text", "").VariationValue)
+	assert.Equal(t,
+		model.EvaluationReasonErrorWrongType,
+		sdk.Int64VariationDetails(context.Background(), u, "text", 0).Reason,
+	)
+	assert.Equal(t,
+		model.EvaluationReasonErrorWrongType,
+		sdk.Float64VariationDetails(context.Background(), u, "text", 0).Reason,
+	)
+	assert.Equal(t, true, sdk.ObjectVariationDetails(context.Background(), u, "feature", nil).VariationValue)
+}
+
+func TestFeatures(t *testing.T) {
+	t.Parallel()
+	c := cache.NewInMemoryCache()
+	defer c.Destroy()
+	features := cache.NewFeaturesCache(c)
+	for _, id := range []string{"search", "checkout"} {
+		assert.NoError(t, features.Put(&ftproto.Feature{Id: id, Enabled: true}))
+	}
+	// Other keys of the cache are not flags
+	assert.NoError(t, c.Put("bucketeer_feature_flags_id", "id", 0))
+
+	sdk := newTestSDK(true)
+	sdk.keys = c
+	sdk.features = features
+	listed, err := sdk.Features()
+	assert.NoError(t, err)
+	ids := make([]string, 0, len(listed))
+	for _, f := range listed {
+		ids = append(ids, f.Id)
+	}
+	assert.Equal(t, []string{"checkout", "search"}, ids)
+
+	sdk.ready = func() bool { return false }
+	_, err = sdk.Features()
+	assert.ErrorIs(t, err, ErrNotReady)
+}
+
+func TestFeatureKeyPrefix(t *testing.T) {
+	t.Parallel()
+	c := cache.NewInMemoryCache()
+	defer c.Destroy()
+	assert.NoError(t, cache.NewFeaturesCache(c).Put(&ftproto.Feature{Id: "flag"}))
+
+	// Features lists the flags by the prefix of their keys, so it must match the keys of the SDK
+	keys, err := c.Scan("")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{featureKeyPrefix + "flag"}, keys)
+}
+
+func TestWaitReady(t *testing.T) {
+	t.Parallel()
+	assert.NoError(t, newTestSDK(true).WaitReady(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, newTestSDK(false).WaitReady(ctx), context.DeadlineExceeded)
+}
+
+func TestNewSDK(t *testing.T) {
+	t.Parallel()
+	_, err := NewSDK(Config{APIEndpoint: "api.example.com"})
+	assert.Error(t, err)
+
+	sdk, err := NewSDK(Config{
+		APIKey:      "key",
+		APIEndpoint: "localhost:1",
+		Scheme:      "http",
+		Tag:         "server",
+		ErrorLogger: log.DiscardErrorLogger,
+	})
+	assert.NoError(t, err)
+	evaluation := sdk.StringVariationDetails(context.Background(), user.NewUser("user", nil), "feature", "default")
+	assert.Equal(t, "default", evaluation.VariationValue)
+	assert.Equal(t, model.EvaluationReasonErrorCacheNotFound, evaluation.Reason)
+	_, err = sdk.Features()
+	assert.ErrorIs(t, err, ErrNotReady)
+	assert.NoError(t, sdk.Close(context.Background()))
+	assert.NoError(t, sdk.Close(context.Background()))
+}
@@ -0,0 +1,196 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/model"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	mockProvider "github.com/bucketeer-io/openfeature-go-server-sdk/test/mock/provider"
+)
+
+func TestEvaluationWithoutEvents(t *testing.T) {
+	t.Parallel()
+	evaluation := model.BKTEvaluationDetails[bool]{
+		FeatureID:      "bool-flag",
+		FeatureVersion: 2,
+		UserID:         "test-user",
+		VariationID:    "variation-on",
+		VariationName:  "on",
+		VariationValue: true,
+		Reason:         model.EvaluationReasonRule,
+	}
+	tests := []struct {
+		desc             string
+		ctx              context.Context
+		withDryRunSDK    bool
+		opts             []Option
+		expectedSDK      bool
+		expectedDryRun   bool
+		expectedResponse openfeature.BoolResolutionDetail
+	}{
+		{
+			desc:          "events reported",
+			ctx:           context.Background(),
+			withDryRunSDK: true,
+			expectedSDK:   true,
+			expectedResponse: openfeature.BoolResolutionDetail{
+				Value: true,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:  openfeature.TargetingMatchReason,
+					Variant: "on",
+					FlagMetadata: openfeature.FlagMetadata{
+						FlagMetadataBucketeerReason: "RULE",
+						FlagMetadataFeatureVersion:  int64(2),
+						FlagMetadataVariationID:     "variation-on",
+					},
+				},
+			},
+		},
+		{
+			desc:           "call without events",
+			ctx:            WithoutEvents(context.Background()),
+			withDryRunSDK:  true,
+			expectedDryRun: true,
+			expectedResponse: openfeature.BoolResolutionDetail{
+				Value: true,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:  openfeature.TargetingMatchReason,
+					Variant: "on",
+					FlagMetadata: openfeature.FlagMetadata{
+						FlagMetadataBucketeerReason: "RULE",
+						FlagMetadataFeatureVersion:  int64(2),
+						FlagMetadataVariationID:     "variation-on",
+						FlagMetadataDryRun:          true,
+					},
+				},
+			},
+		},
+		{
+			desc:           "events disabled",
+			ctx:            context.Background(),
+			withDryRunSDK:  true,
+			opts:           []Option{WithEventsDisabled()},
+			expectedDryRun: true,
+			expectedResponse: openfeature.BoolResolutionDetail{
+				Value: true,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:  openfeature.TargetingMatchReason,
+					Variant: "on",
+					FlagMetadata: openfeature.FlagMetadata{
+						FlagMetadataBucketeerReason: "RULE",
+						FlagMetadataFeatureVersion:  int64(2),
+						FlagMetadataVariationID:     "variation-on",
+						FlagMetadataDryRun:          true,
+					},
+				},
+			},
+		},
+		{
+			desc: "call without events and no dry-run SDK",
+			ctx:  WithoutEvents(context.Background()),
+			expectedResponse: openfeature.BoolResolutionDetail{
+				Value: false,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: openfeature.NewGeneralResolutionError(ErrNoDryRunSDK.Error()),
+					Reason:          openfeature.ErrorReason,
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSDK := mockProvider.NewMockBucketeerSDK(ctrl)
+			if test.expectedSDK {
+				mockSDK.EXPECT().
+					BoolVariationDetails(test.ctx, gomock.Any(), "bool-flag", false).
+					Return(evaluation).
+					Times(1)
+			}
+			opts := test.opts
+			if test.withDryRunSDK {
+				dryRunSDK := mockProvider.NewMockBucketeerSDK(ctrl)
+				if test.expectedDryRun {
+					dryRunSDK.EXPECT().
+						BoolVariationDetails(test.ctx, gomock.Any(), "bool-flag", false).
+						Return(evaluation).
+						Times(1)
+				}
+				opts = append(opts, WithDryRunSDK(dryRunSDK))
+			}
+			provider := newTestProvider(mockSDK, opts...)
+
+			response := provider.BooleanEvaluation(
+				test.ctx,
+				"bool-flag",
+				false,
+				openfeature.FlattenedContext{openfeature.TargetingKey: "test-user"},
+			)
+			assert.Equal(t, test.expectedResponse, response)
+		})
+	}
+}
+
+func TestTrackWithoutEvents(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// The goal events are dropped, so TrackValue is not called
+	mockSDK := mockProvider.NewMockExtendedSDK(ctrl)
+	evalCtx := openfeature.NewEvaluationContext("test-user", nil)
+	newTestProvider(mockSDK).Track(
+		WithoutEvents(context.Background()), "purchase", evalCtx, openfeature.NewTrackingEventDetails(1),
+	)
+	newTestProvider(mockSDK, WithEventsDisabled()).Track(
+		context.Background(), "purchase", evalCtx, openfeature.NewTrackingEventDetails(1),
+	)
+}
+
+func TestShutdownWithDryRunSDK(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSDK := mockProvider.NewMockBucketeerSDK(ctrl)
+	mockSDK.EXPECT().Close(gomock.Any()).Return(nil).Times(1)
+	dryRunSDK := mockProvider.NewMockBucketeerSDK(ctrl)
+	dryRunSDK.EXPECT().Close(gomock.Any()).Return(nil).Times(1)
+
+	provider := newTestProvider(mockSDK, WithDryRunSDK(dryRunSDK))
+	assert.NoError(t, provider.ShutdownWithContext(context.Background()))
+}
+
+func TestHasDryRunSDK(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSDK := mockProvider.NewMockBucketeerSDK(ctrl)
+	assert.False(t, newTestProvider(mockSDK).HasDryRunSDK())
+	assert.True(t, newTestProvider(mockSDK, WithDryRunSDK(mockProvider.NewMockBucketeerSDK(ctrl))).HasDryRunSDK())
+}
+
+func TestEventsDisabledWithoutDryRunSDK(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSDK := mockProvider.NewMockBucketeerSDK(ctrl)
+	_, err := NewProviderFromSDK(mockSDK, WithEventsDisabled())
+	assert.ErrorIs(t, err, ErrNoDryRunSDK)
+	// The SDK isn't created when the options are invalid
+	_, err = NewProviderWithContext(context.Background(), nil, WithEventsDisabled())
+	assert.ErrorIs(t, err, ErrNoDryRunSDK)
+
+	_, err = NewProviderFromSDK(mockSDK, WithEventsDisabled(), WithDryRunSDK(mockProvider.NewMockBucketeerSDK(ctrl)))
+	assert.NoError(t, err)
+}
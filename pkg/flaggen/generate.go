@@ -0,0 +1,330 @@
+package flaggen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// accessor is a flag of the generated file
+type accessor struct {
+	Key  string
+	Name string
+	// Type is the flag type, e.g. int
+	Type string
+	// Doc are the lines of the description
+	Doc []string
+	// GoType is the type of the values, e.g. int64
+	GoType string
+	// Method is the OpenFeature client method prefix, e.g. Int for IntValueDetails
+	Method string
+	// Details is the type of the evaluation details, e.g. IntEvaluationDetails
+	Details string
+	// Default is the Go literal of the default value
+	Default string
+	// DefaultExpr is passed to the client: the literal, or a call to Default for objects to get a copy
+	DefaultExpr string
+}
+
+// goTypes are the Go type, the client method prefix and the details type of each flag type
+var goTypes = map[string][3]string{
+	TypeBool:   {"bool", "Boolean", "BooleanEvaluationDetails"},
+	TypeString: {"string", "String", "StringEvaluationDetails"},
+	TypeInt:    {"int64", "Int", "IntEvaluationDetails"},
+	TypeFloat:  {"float64", "Float", "FloatEvaluationDetails"},
+	TypeObject: {"any", "Object", "InterfaceEvaluationDetails"},
+}
+
+var fileTemplate = template.Must(template.New("flags").Parse(
+	`// Code generated by flaggen{{with .Source}} from {{.}}{{end}}. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"context"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// Keys returns the keys of the flags, e.g. to compare them with the flags of Bucketeer
+func Keys() []string {
+	return []string{
+{{- range .Flags}}
+		{{.Name}}Key,
+{{- end}}
+	}
+}
+{{range .Flags}}
+// {{.Name}}Key is the key of the {{.Key}} flag
+const {{.Name}}Key = {{printf "%q" .Key}}
+
+// {{.Name}}Flag is the {{.Key}} {{.Type}} flag.
+{{- if .Doc}}
+//
+{{- range .Doc}}
+//{{with .}} {{.}}{{end}}
+{{- end}}
+{{- end}}
+type {{.Name}}Flag struct {
+	client openfeature.IClient
+}
+
+// {{.Name}} returns the {{.Key}} flag evaluated by the client, e.g. an OpenFeature client of the provider.
+{{- if .Doc}}
+//
+{{- range .Doc}}
+//{{with .}} {{.}}{{end}}
+{{- end}}
+{{- end}}
+func {{.Name}}(client openfeature.IClient) {{.Name}}Flag {
+	return {{.Name}}Flag{client: client}
+}
+
+// Key returns the key of the flag
+func ({{.Name}}Flag) Key() string {
+	return {{.Name}}Key
+}
+
+// Default returns the default value of the flag
+func ({{.Name}}Flag) Default() {{.GoType}} {
+	return {{.Default}}
+}
+
+// Value evaluates the flag, and returns the default value if the evaluation fails
+func (f {{.Name}}Flag) Value(
+	ctx context.Context,
+	evalCtx openfeature.EvaluationContext,
+	options ...openfeature.Option,
+) {{.GoType}} {
+	return f.client.{{.Method}}(ctx, {{.Name}}Key, {{.DefaultExpr}}, evalCtx, options...)
+}
+
+// Details evaluates the flag and returns the evaluation details
+func (f {{.Name}}Flag) Details(
+	ctx context.Context,
+	evalCtx openfeature.EvaluationContext,
+	options ...openfeature.Option,
+) (openfeature.{{.Details}}, error) {
+	return f.client.{{.Method}}ValueDetails(ctx, {{.Name}}Key, {{.DefaultExpr}}, evalCtx, options...)
+}
+{{end}}`,
+))
+
+// Generate generates the Go file of the package pkg with the accessors of the flags of the manifest.
+// source is the manifest file named in the header of the generated file, or empty.
+func Generate(m Manifest, pkg, source string) ([]byte, error) {
+	if !token.IsIdentifier(pkg) {
+		return nil, fmt.Errorf("invalid package name %q", pkg)
+	}
+	accessors := make([]accessor, 0, len(m.Flags))
+	// names are the generated identifiers, to report the collisions
+	names := map[string]string{"Keys": "the Keys function"}
+	for i, d := range m.Flags {
+		a, err := newAccessor(d)
+		if err != nil {
+			return nil, fmt.Errorf("flag %d (%s): %w", i, d.Key, err)
+		}
+		for _, name := range []string{a.Name, a.Name + "Key", a.Name + "Flag"} {
+			if other, ok := names[name]; ok {
+				return nil, fmt.Errorf("flag %s: %s is already defined by %s, set its name", d.Key, name, other)
+			}
+			names[name] = "flag " + d.Key
+		}
+		accessors = append(accessors, a)
+	}
+
+	var buf bytes.Buffer
+	err := fileTemplate.Execute(&buf, struct {
+		Package string
+		Source  string
+		Flags   []accessor
+	}{Package: pkg, Source: source, Flags: accessors})
+	if err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format the generated code: %w", err)
+	}
+	return src, nil
+}
+
+func newAccessor(d Definition) (accessor, error) {
+	if d.Key == "" {
+		return accessor{}, fmt.Errorf("missing key")
+	}
+	types, ok := goTypes[d.Type]
+	if !ok {
+		return accessor{}, fmt.Errorf("unknown type %q, expected bool, string, int, float or object", d.Type)
+	}
+	name := d.Name
+	if name == "" {
+		name = goName(d.Key)
+	}
+	if !token.IsIdentifier(name) || !token.IsExported(name) {
+		return accessor{}, fmt.Errorf("invalid name %q, expected an exported Go identifier", name)
+	}
+	literal, err := defaultLiteral(d.Type, d.Default)
+	if err != nil {
+		return accessor{}, err
+	}
+	a := accessor{
+		Key:         d.Key,
+		Name:        name,
+		Type:        d.Type,
+		GoType:      types[0],
+		Method:      types[1],
+		Details:     types[2],
+		Default:     literal,
+		DefaultExpr: literal,
+	}
+	if d.Type == TypeObject && literal != "nil" {
+		a.DefaultExpr = "f.Default()"
+	}
+	if description := strings.TrimSpace(d.Description); description != "" {
+		a.Doc = strings.Split(description, "\n")
+		for i, line := range a.Doc {
+			a.Doc[i] = strings.TrimRightFunc(line, unicode.IsSpace)
+		}
+	}
+	return a, nil
+}
+
+// goName converts a flag key to a Go name in camel case, e.g. new-checkout.v2 to NewCheckoutV2
+func goName(key string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range key {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	name := b.String()
+	if name == "" || !unicode.IsUpper([]rune(name)[0]) {
+		name = "Flag" + name
+	}
+	return name
+}
+
+// defaultLiteral returns the Go literal of the default value of a flag, the zero value if it's nil
+func defaultLiteral(flagType string, value any) (string, error) {
+	switch flagType {
+	case TypeBool:
+		if value == nil {
+			return "false", nil
+		}
+		if b, ok := value.(bool); ok {
+			return strconv.FormatBool(b), nil
+		}
+	case TypeString:
+		if value == nil {
+			return `""`, nil
+		}
+		if s, ok := value.(string); ok {
+			return strconv.Quote(s), nil
+		}
+	case TypeInt:
+		if value == nil {
+			return "0", nil
+		}
+		if i, ok := toInt(value); ok {
+			return strconv.FormatInt(i, 10), nil
+		}
+	case TypeFloat:
+		if value == nil {
+			return "0", nil
+		}
+		if f, ok := toFloat(value); ok {
+			return strconv.FormatFloat(f, 'g', -1, 64), nil
+		}
+	case TypeObject:
+		return objectLiteral(value)
+	}
+	return "", fmt.Errorf("invalid %s default value %v", flagType, value)
+}
+
+// objectLiteral returns the Go literal of a JSON-like value.
+// Numbers are float64, like the values of the object flags decoded from JSON.
+func objectLiteral(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "nil", nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case string:
+		return strconv.Quote(v), nil
+	case []any:
+		elems := make([]string, 0, len(v))
+		for _, e := range v {
+			literal, err := objectLiteral(e)
+			if err != nil {
+				return "", err
+			}
+			elems = append(elems, literal)
+		}
+		return "[]any{" + strings.Join(elems, ", ") + "}", nil
+	case map[string]any:
+		fields := make([]string, 0, len(v))
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			literal, err := objectLiteral(v[k])
+			if err != nil {
+				return "", err
+			}
+			fields = append(fields, strconv.Quote(k)+": "+literal)
+		}
+		return "map[string]any{" + strings.Join(fields, ", ") + "}", nil
+	}
+	if f, ok := toFloat(value); ok {
+		return "float64(" + strconv.FormatFloat(f, 'g', -1, 64) + ")", nil
+	}
+	return "", fmt.Errorf("invalid object value %v of type %T", value, value)
+}
+
+// toInt converts an integer, or a float64 with an integer value, to an int64
+func toInt(value any) (int64, bool) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint64:
+		if v <= math.MaxInt64 {
+			return int64(v), true
+		}
+	case float64:
+		// JSON numbers
+		if v == math.Trunc(v) && math.Abs(v) <= 1<<53 {
+			return int64(v), true
+		}
+	}
+	return 0, false
+}
+
+// toFloat converts a number to a finite float64
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, !math.IsInf(v, 0) && !math.IsNaN(v)
+	}
+	return 0, false
+}
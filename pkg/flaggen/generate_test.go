@@ -0,0 +1,159 @@
+package flaggen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+	src, err := Generate(Manifest{Flags: []Definition{
+		{Key: "new-checkout", Type: TypeBool, Default: true, Description: "Enables the new checkout.\n\nRemove it later."},
+	}}, "flags", "flags.yaml")
+	require.NoError(t, err)
+	code := string(src)
+	assert.Contains(t, code, "// Code generated by flaggen from flags.yaml. DO NOT EDIT.\n\npackage flags\n")
+	assert.Contains(t, code, `const NewCheckoutKey = "new-checkout"`)
+	assert.Contains(t, code, "// NewCheckoutFlag is the new-checkout bool flag.\n//\n// Enables the new checkout.\n//\n// Remove it later.\n")
+	assert.Contains(t, code, "func NewCheckout(client openfeature.IClient) NewCheckoutFlag {")
+	assert.Contains(t, code, "return f.client.Boolean(ctx, NewCheckoutKey, true, evalCtx, options...)")
+	assert.Contains(t, code, "return f.client.BooleanValueDetails(ctx, NewCheckoutKey, true, evalCtx, options...)")
+}
+
+func TestGenerateErrors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		desc     string
+		pkg      string
+		flags    []Definition
+		expected string
+	}{
+		{
+			desc:     "invalid package",
+			pkg:      "my-flags",
+			expected: `invalid package name "my-flags"`,
+		},
+		{
+			desc:     "missing key",
+			flags:    []Definition{{Type: TypeBool}},
+			expected: "flag 0 (): missing key",
+		},
+		{
+			desc:     "unknown type",
+			flags:    []Definition{{Key: "f", Type: "boolean"}},
+			expected: `flag 0 (f): unknown type "boolean"`,
+		},
+		{
+			desc:     "invalid name",
+			flags:    []Definition{{Key: "f", Name: "myFlag", Type: TypeBool}},
+			expected: `flag 0 (f): invalid name "myFlag"`,
+		},
+		{
+			desc:     "invalid default",
+			flags:    []Definition{{Key: "f", Type: TypeInt, Default: 1.5}},
+			expected: "flag 0 (f): invalid int default value 1.5",
+		},
+		{
+			desc: "name collision",
+			flags: []Definition{
+				{Key: "new-checkout", Type: TypeBool},
+				{Key: "new_checkout", Type: TypeBool},
+			},
+			expected: "flag new_checkout: NewCheckout is already defined by flag new-checkout, set its name",
+		},
+		{
+			desc: "key constant collision",
+			flags: []Definition{
+				{Key: "checkout", Type: TypeBool},
+				{Key: "checkout-key", Type: TypeString},
+			},
+			expected: "flag checkout-key: CheckoutKey is already defined by flag checkout",
+		},
+		{
+			desc:     "keys function collision",
+			flags:    []Definition{{Key: "keys", Type: TypeString}},
+			expected: "flag keys: Keys is already defined by the Keys function",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			pkg := test.pkg
+			if pkg == "" {
+				pkg = "flags"
+			}
+			_, err := Generate(Manifest{Flags: test.flags}, pkg, "")
+			assert.ErrorContains(t, err, test.expected)
+		})
+	}
+}
+
+func TestGoName(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"new-checkout":    "NewCheckout",
+		"new_checkout.v2": "NewCheckoutV2",
+		"darkMode":        "DarkMode",
+		"2024-banner":     "Flag2024Banner",
+		"---":             "Flag",
+		"été":             "Été",
+	}
+	for key, expected := range tests {
+		assert.Equal(t, expected, goName(key), key)
+	}
+}
+
+func TestDefaultLiteral(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		desc     string
+		flagType string
+		value    any
+		expected string
+	}{
+		{desc: "bool", flagType: TypeBool, value: true, expected: "true"},
+		{desc: "bool zero value", flagType: TypeBool, expected: "false"},
+		{desc: "string", flagType: TypeString, value: "say \"hi\"", expected: `"say \"hi\""`},
+		{desc: "string zero value", flagType: TypeString, expected: `""`},
+		{desc: "int", flagType: TypeInt, value: 10, expected: "10"},
+		{desc: "int from json", flagType: TypeInt, value: float64(-3), expected: "-3"},
+		{desc: "int zero value", flagType: TypeInt, expected: "0"},
+		{desc: "float", flagType: TypeFloat, value: 0.25, expected: "0.25"},
+		{desc: "float from int", flagType: TypeFloat, value: 2, expected: "2"},
+		{desc: "float zero value", flagType: TypeFloat, expected: "0"},
+		{desc: "object zero value", flagType: TypeObject, expected: "nil"},
+		{desc: "object array", flagType: TypeObject, value: []any{1, "a", nil}, expected: `[]any{float64(1), "a", nil}`},
+		{
+			desc:     "object map",
+			flagType: TypeObject,
+			value:    map[string]any{"b": false, "a": map[string]any{"c": 1.5}},
+			expected: `map[string]any{"a": map[string]any{"c": float64(1.5)}, "b": false}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			literal, err := defaultLiteral(test.flagType, test.value)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, literal)
+		})
+	}
+
+	invalid := []struct {
+		flagType string
+		value    any
+	}{
+		{TypeBool, "true"},
+		{TypeString, 1},
+		{TypeInt, "1"},
+		{TypeInt, 1.5},
+		{TypeFloat, "0.5"},
+		{TypeObject, map[any]any{1: "a"}},
+	}
+	for _, test := range invalid {
+		_, err := defaultLiteral(test.flagType, test.value)
+		assert.Error(t, err, "%s %v", test.flagType, test.value)
+	}
+}
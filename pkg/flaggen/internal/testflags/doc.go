@@ -0,0 +1,4 @@
+// Package testflags is generated from flags.yaml, to test the generated code
+package testflags
+
+//go:generate go run ../../../../cmd/flaggen -o flags_gen.go flags.yaml
@@ -0,0 +1,279 @@
+// Code generated by flaggen from flags.yaml. DO NOT EDIT.
+
+package testflags
+
+import (
+	"context"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// Keys returns the keys of the flags, e.g. to compare them with the flags of Bucketeer
+func Keys() []string {
+	return []string{
+		NewCheckoutKey,
+		ThemeKey,
+		MaxItemsKey,
+		DiscountKey,
+		CheckoutConfigKey,
+		Flag2024BannerKey,
+	}
+}
+
+// NewCheckoutKey is the key of the new-checkout flag
+const NewCheckoutKey = "new-checkout"
+
+// NewCheckoutFlag is the new-checkout bool flag.
+//
+// Enables the new checkout flow.
+//
+// Remove it once the rollout is complete.
+type NewCheckoutFlag struct {
+	client openfeature.IClient
+}
+
+// NewCheckout returns the new-checkout flag evaluated by the client, e.g. an OpenFeature client of the provider.
+//
+// Enables the new checkout flow.
+//
+// Remove it once the rollout is complete.
+func NewCheckout(client openfeature.IClient) NewCheckoutFlag {
+	return NewCheckoutFlag{client: client}
+}
+
+// Key returns the key of the flag
+func (NewCheckoutFlag) Key() string {
+	return NewCheckoutKey
+}
+
+// Default returns the default value of the flag
+func (NewCheckoutFlag) Default() bool {
+	return false
+}
+
+// Value evaluates the flag, and returns the default value if the evaluation fails
+func (f NewCheckoutFlag) Value(
+	ctx context.Context,
+	evalCtx openfeature.EvaluationContext,
+	options ...openfeature.Option,
+) bool {
+	return f.client.Boolean(ctx, NewCheckoutKey, false, evalCtx, options...)
+}
+
+// Details evaluates the flag and returns the evaluation details
+func (f NewCheckoutFlag) Details(
+	ctx context.Context,
+	evalCtx openfeature.EvaluationContext,
+	options ...openfeature.Option,
+) (openfeature.BooleanEvaluationDetails, error) {
+	return f.client.BooleanValueDetails(ctx, NewCheckoutKey, false, evalCtx, options...)
+}
+
+// ThemeKey is the key of the theme flag
+const ThemeKey = "theme"
+
+// ThemeFlag is the theme string flag.
+type ThemeFlag struct {
+	client openfeature.IClient
+}
+
+// Theme returns the theme flag evaluated by the client, e.g. an OpenFeature client of the provider.
+func Theme(client openfeature.IClient) ThemeFlag {
+	return ThemeFlag{client: client}
+}
+
+// Key returns the key of the flag
+func (ThemeFlag) Key() string {
+	return ThemeKey
+}
+
+// Default returns the default value of the flag
+func (ThemeFlag) Default() string {
+	return "light"
+}
+
+// Value evaluates the flag, and returns the default value if the evaluation fails
+func (f ThemeFlag) Value(
+	ctx context.Context,
+	evalCtx openfeature.EvaluationContext,
+	options ...openfeature.Option,
+) string {
+	return f.client.String(ctx, ThemeKey, "light", evalCtx, options...)
+}
+
+// Details evaluates the flag and returns the evaluation details
+func (f ThemeFlag) Details(
+	ctx context.Context,
+	evalCtx openfeature.EvaluationContext,
+	options ...openfeature.Option,
+) (openfeature.StringEvaluationDetails, error) {
+	return f.client.StringValueDetails(ctx, ThemeKey, "light", evalCtx, options...)
+}
+
+// MaxItemsKey is the key of the max-items flag
+const MaxItemsKey = "max-items"
+
+// MaxItemsFlag is the max-items int flag.
+//
+// Maximum number of items in the cart.
+type MaxItemsFlag struct {
+	client openfeature.IClient
+}
+
+// MaxItems returns the max-items flag evaluated by the client, e.g. an OpenFeature client of the provider.
+//
+// Maximum number of items in the cart.
+func MaxItems(client openfeature.IClient) MaxItemsFlag {
+	return MaxItemsFlag{client: client}
+}
+
+// Key returns the key of the flag
+func (MaxItemsFlag) Key() string {
+	return MaxItemsKey
+}
+
+// Default returns the default value of the flag
+func (MaxItemsFlag) Default() int64 {
+	return 10
+}
+
+// Value evaluates the flag, and returns the default value if the evaluation fails
+func (f MaxItemsFlag) Value(
+	ctx context.Context,
+	evalCtx openfeature.EvaluationContext,
+	options ...openfeature.Option,
+) int64 {
+	return f.client.Int(ctx, MaxItemsKey, 10, evalCtx, options...)
+}
+
+// Details evaluates the flag and returns the evaluation details
+func (f MaxItemsFlag) Details(
+	ctx context.Context,
+	evalCtx openfeature.EvaluationContext,
+	options ...openfeature.Option,
+) (openfeature.IntEvaluationDetails, error) {
+	return f.client.IntValueDetails(ctx, MaxItemsKey, 10, evalCtx, options...)
+}
+
+// DiscountKey is the key of the discount-rate flag
+const DiscountKey = "discount-rate"
+
+// DiscountFlag is the discount-rate float flag.
+type DiscountFlag struct {
+	client openfeature.IClient
+}
+
+// Discount returns the discount-rate flag evaluated by the client, e.g. an OpenFeature client of the provider.
+func Discount(client openfeature.IClient) DiscountFlag {
+	return DiscountFlag{client: client}
+}
+
+// Key returns the key of the flag
+func (DiscountFlag) Key() string {
+	return DiscountKey
+}
+
+// Default returns the default value of the flag
+func (DiscountFlag) Default() float64 {
+	return 0.1
+}
+
+// Value evaluates the flag, and returns the default value if the evaluation fails
+func (f DiscountFlag) Value(
+	ctx context.Context,
+	evalCtx openfeature.EvaluationContext,
+	options ...openfeature.Option,
+) float64 {
+	return f.client.Float(ctx, DiscountKey, 0.1, evalCtx, options...)
+}
+
+// Details evaluates the flag and returns the evaluation details
+func (f DiscountFlag) Details(
+	ctx context.Context,
+	evalCtx openfeature.EvaluationContext,
+	options ...openfeature.Option,
+) (openfeature.FloatEvaluationDetails, error) {
+	return f.client.FloatValueDetails(ctx, DiscountKey, 0.1, evalCtx, options...)
+}
+
+// CheckoutConfigKey is the key of the checkout.config flag
+const CheckoutConfigKey = "checkout.config"
+
+// CheckoutConfigFlag is the checkout.config object flag.
+type CheckoutConfigFlag struct {
+	client openfeature.IClient
+}
+
+// CheckoutConfig returns the checkout.config flag evaluated by the client, e.g. an OpenFeature client of the provider.
+func CheckoutConfig(client openfeature.IClient) CheckoutConfigFlag {
+	return CheckoutConfigFlag{client: client}
+}
+
+// Key returns the key of the flag
+func (CheckoutConfigFlag) Key() string {
+	return CheckoutConfigKey
+}
+
+// Default returns the default value of the flag
+func (CheckoutConfigFlag) Default() any {
+	return map[string]any{"express": true, "retries": float64(3), "steps": []any{"cart", "payment"}}
+}
+
+// Value evaluates the flag, and returns the default value if the evaluation fails
+func (f CheckoutConfigFlag) Value(
+	ctx context.Context,
+	evalCtx openfeature.EvaluationContext,
+	options ...openfeature.Option,
+) any {
+	return f.client.Object(ctx, CheckoutConfigKey, f.Default(), evalCtx, options...)
+}
+
+// Details evaluates the flag and returns the evaluation details
+func (f CheckoutConfigFlag) Details(
+	ctx context.Context,
+	evalCtx openfeature.EvaluationContext,
+	options ...openfeature.Option,
+) (openfeature.InterfaceEvaluationDetails, error) {
+	return f.client.ObjectValueDetails(ctx, CheckoutConfigKey, f.Default(), evalCtx, options...)
+}
+
+// Flag2024BannerKey is the key of the 2024-banner flag
+const Flag2024BannerKey = "2024-banner"
+
+// Flag2024BannerFlag is the 2024-banner object flag.
+type Flag2024BannerFlag struct {
+	client openfeature.IClient
+}
+
+// Flag2024Banner returns the 2024-banner flag evaluated by the client, e.g. an OpenFeature client of the provider.
+func Flag2024Banner(client openfeature.IClient) Flag2024BannerFlag {
+	return Flag2024BannerFlag{client: client}
+}
+
+// Key returns the key of the flag
+func (Flag2024BannerFlag) Key() string {
+	return Flag2024BannerKey
+}
+
+// Default returns the default value of the flag
+func (Flag2024BannerFlag) Default() any {
+	return nil
+}
+
+// Value evaluates the flag, and returns the default value if the evaluation fails
+func (f Flag2024BannerFlag) Value(
+	ctx context.Context,
+	evalCtx openfeature.EvaluationContext,
+	options ...openfeature.Option,
+) any {
+	return f.client.Object(ctx, Flag2024BannerKey, nil, evalCtx, options...)
+}
+
+// Details evaluates the flag and returns the evaluation details
+func (f Flag2024BannerFlag) Details(
+	ctx context.Context,
+	evalCtx openfeature.EvaluationContext,
+	options ...openfeature.Option,
+) (openfeature.InterfaceEvaluationDetails, error) {
+	return f.client.ObjectValueDetails(ctx, Flag2024BannerKey, nil, evalCtx, options...)
+}
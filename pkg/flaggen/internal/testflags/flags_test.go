@@ -0,0 +1,81 @@
+package testflags
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	provider "github.com/bucketeer-io/openfeature-go-server-sdk/pkg"
+	"github.com/bucketeer-io/openfeature-go-server-sdk/pkg/flaggen"
+	"github.com/bucketeer-io/openfeature-go-server-sdk/pkg/inmemory"
+)
+
+func TestGeneratedUpToDate(t *testing.T) {
+	t.Parallel()
+	m, err := flaggen.LoadManifest("flags.yaml")
+	require.NoError(t, err)
+	src, err := flaggen.Generate(m, "testflags", "flags.yaml")
+	require.NoError(t, err)
+	generated, err := os.ReadFile("flags_gen.go")
+	require.NoError(t, err)
+	assert.Equal(t, string(src), string(generated), `flags_gen.go is outdated, run "go generate"`)
+}
+
+func TestAccessors(t *testing.T) {
+	t.Parallel()
+	sdk := inmemory.New(map[string]inmemory.Flag{
+		NewCheckoutKey: {
+			Variations: []inmemory.Variation{
+				{ID: "on", Name: "on", Value: "true"},
+				{ID: "off", Name: "off", Value: "false"},
+			},
+			DefaultVariation: "off",
+			Targets:          map[string]string{"user-1": "on"},
+		},
+		MaxItemsKey: {
+			Variations:       []inmemory.Variation{{ID: "twenty", Value: "20"}},
+			DefaultVariation: "twenty",
+		},
+	})
+	p, err := provider.NewProviderFromSDK(sdk)
+	require.NoError(t, err)
+	require.NoError(t, openfeature.SetNamedProviderAndWait(t.Name(), p))
+	client := openfeature.NewClient(t.Name())
+	ctx := context.Background()
+	evalCtx := openfeature.NewTargetlessEvaluationContext(nil)
+
+	assert.True(t, NewCheckout(client).Value(ctx, openfeature.NewEvaluationContext("user-1", nil)))
+	assert.False(t, NewCheckout(client).Value(ctx, openfeature.NewEvaluationContext("user-2", nil)))
+	assert.Equal(t, int64(20), MaxItems(client).Value(ctx, openfeature.NewEvaluationContext("user-1", nil)))
+
+	// The flags missing in Bucketeer return their default value
+	assert.Equal(t, "light", Theme(client).Value(ctx, evalCtx))
+	details, err := Discount(client).Details(ctx, openfeature.NewEvaluationContext("user-1", nil))
+	assert.Error(t, err)
+	assert.Equal(t, 0.1, details.Value)
+	assert.Equal(t, openfeature.FlagNotFoundCode, details.ErrorCode)
+	assert.Equal(t, map[string]any{
+		"express": true,
+		"retries": float64(3),
+		"steps":   []any{"cart", "payment"},
+	}, CheckoutConfig(client).Value(ctx, openfeature.NewEvaluationContext("user-1", nil)))
+	assert.Nil(t, Flag2024Banner(client).Value(ctx, openfeature.NewEvaluationContext("user-1", nil)))
+}
+
+func TestKeys(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, []string{
+		"new-checkout",
+		"theme",
+		"max-items",
+		"discount-rate",
+		"checkout.config",
+		"2024-banner",
+	}, Keys())
+	assert.Equal(t, "discount-rate", Discount(nil).Key())
+	assert.Equal(t, int64(10), MaxItems(nil).Default())
+}
@@ -0,0 +1,166 @@
+// Package flaggen generates typed Go accessors for the flags of a manifest,
+// so the flag keys and default values are defined once instead of being string literals in the code.
+//
+// A manifest lists the flags with their type, default value and description, in YAML or JSON:
+//
+//	flags:
+//	  - key: new-checkout
+//	    type: bool
+//	    default: false
+//	    description: Enables the new checkout flow.
+//
+// Generate writes a Go file with an accessor per flag, e.g. NewCheckout(client).Value(ctx, evalCtx),
+// evaluating the flag through an OpenFeature client backed by the provider. Removing a flag from the manifest
+// makes its uses a compile error. A manifest can be exported from the flags of Bucketeer with ManifestFromFlags.
+// See cmd/flaggen.
+package flaggen
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/bucketeer-io/openfeature-go-server-sdk/pkg/inspect"
+)
+
+// Flag types, the same as the types of the flaglint inventory
+const (
+	TypeBool   = "bool"
+	TypeString = "string"
+	TypeInt    = "int"
+	TypeFloat  = "float"
+	TypeObject = "object"
+)
+
+// Manifest lists the flags to generate accessors for
+type Manifest struct {
+	Flags []Definition `yaml:"flags" json:"flags"`
+}
+
+// Definition defines a flag
+type Definition struct {
+	// Key is the flag key, i.e. the Bucketeer feature ID
+	Key string `yaml:"key" json:"key"`
+	// Name is the Go name of the accessor. Defaults to the key in camel case, e.g. NewCheckout for new-checkout.
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+	// Type is bool, string, int, float or object
+	Type string `yaml:"type" json:"type"`
+	// Default is the value returned when the evaluation fails. Defaults to the zero value of the type.
+	Default any `yaml:"default" json:"default"`
+	// Description is the doc comment of the accessor
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+}
+
+// ParseManifest parses a manifest in YAML or JSON. Unknown fields are an error, to catch typos.
+func ParseManifest(data []byte) (Manifest, error) {
+	var m Manifest
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&m); err != nil && !errors.Is(err, io.EOF) {
+		return Manifest{}, fmt.Errorf("failed to parse the manifest: %w", err)
+	}
+	return m, nil
+}
+
+// LoadManifest reads a manifest file in YAML or JSON
+func LoadManifest(path string) (Manifest, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- the manifest path is chosen by the user
+	if err != nil {
+		return Manifest{}, fmt.Errorf("failed to read the manifest: %w", err)
+	}
+	return ParseManifest(data)
+}
+
+// ManifestFromFlags creates the manifest of flags listed by Bucketeer, e.g. with inspect.Inspector.Flags.
+// The archived flags are skipped. The default value of a flag is the value of its off variation,
+// served when the flag is disabled, or the zero value of its type if it has no off variation.
+// A NUMBER flag is an int flag if all its variations are integers, and a float flag otherwise.
+func ManifestFromFlags(flags []inspect.Flag) (Manifest, error) {
+	m := Manifest{Flags: []Definition{}}
+	for _, f := range flags {
+		if f.Archived {
+			continue
+		}
+		d := Definition{Key: f.ID, Description: f.Description}
+		if d.Description == "" && f.Name != f.ID {
+			d.Description = f.Name
+		}
+		switch f.VariationType {
+		case "BOOLEAN":
+			d.Type = TypeBool
+		case "STRING":
+			d.Type = TypeString
+		case "NUMBER":
+			d.Type = TypeInt
+			for _, v := range f.Variations {
+				if _, err := strconv.ParseInt(v.Value, 10, 64); err != nil {
+					d.Type = TypeFloat
+				}
+			}
+		case "JSON", "YAML":
+			d.Type = TypeObject
+		default:
+			return Manifest{}, fmt.Errorf("flag %s: unknown variation type %s", f.ID, f.VariationType)
+		}
+		value, err := offValue(f, d.Type)
+		if err != nil {
+			return Manifest{}, fmt.Errorf("flag %s: %w", f.ID, err)
+		}
+		d.Default = value
+		m.Flags = append(m.Flags, d)
+	}
+	return m, nil
+}
+
+// zeroValues are the default values of the flags without an off variation
+var zeroValues = map[string]any{
+	TypeBool:   false,
+	TypeString: "",
+	TypeInt:    int64(0),
+	TypeFloat:  float64(0),
+	TypeObject: nil,
+}
+
+// offValue returns the value of the off variation of the flag, or the zero value if it has no off variation
+func offValue(f inspect.Flag, flagType string) (any, error) {
+	if f.OffVariation == "" {
+		return zeroValues[flagType], nil
+	}
+	// OffVariation is the name of the variation, or its ID if it has no name
+	for _, v := range f.Variations {
+		if v.Name != f.OffVariation && v.ID != f.OffVariation {
+			continue
+		}
+		var (
+			value any
+			err   error
+		)
+		switch flagType {
+		case TypeBool:
+			value, err = strconv.ParseBool(v.Value)
+		case TypeString:
+			value = v.Value
+		case TypeInt:
+			value, err = strconv.ParseInt(v.Value, 10, 64)
+		case TypeFloat:
+			value, err = strconv.ParseFloat(v.Value, 64)
+		case TypeObject:
+			if f.VariationType == "YAML" {
+				err = yaml.Unmarshal([]byte(v.Value), &value)
+			} else {
+				err = json.Unmarshal([]byte(v.Value), &value)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of the off variation %s: %w", f.OffVariation, err)
+		}
+		return value, nil
+	}
+	return nil, fmt.Errorf("off variation %s not found", f.OffVariation)
+}
@@ -0,0 +1,178 @@
+package flaggen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bucketeer-io/openfeature-go-server-sdk/pkg/inspect"
+)
+
+func TestParseManifest(t *testing.T) {
+	t.Parallel()
+	expected := Manifest{Flags: []Definition{
+		{Key: "new-checkout", Type: TypeBool, Default: true, Description: "Enables the new checkout"},
+		{Key: "max-items", Name: "MaxCartItems", Type: TypeInt, Default: 10},
+		{Key: "config", Type: TypeObject, Default: map[string]any{"retries": 3}},
+	}}
+	tests := []struct {
+		desc string
+		data string
+	}{
+		{
+			desc: "yaml",
+			data: `
+flags:
+  - key: new-checkout
+    type: bool
+    default: true
+    description: Enables the new checkout
+  - key: max-items
+    name: MaxCartItems
+    type: int
+    default: 10
+  - key: config
+    type: object
+    default: {retries: 3}
+`,
+		},
+		{
+			desc: "json",
+			data: `{"flags": [
+				{"key": "new-checkout", "type": "bool", "default": true, "description": "Enables the new checkout"},
+				{"key": "max-items", "name": "MaxCartItems", "type": "int", "default": 10},
+				{"key": "config", "type": "object", "default": {"retries": 3}}
+			]}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			m, err := ParseManifest([]byte(test.data))
+			require.NoError(t, err)
+			assert.Equal(t, expected, m)
+		})
+	}
+}
+
+func TestParseManifestErrors(t *testing.T) {
+	t.Parallel()
+	m, err := ParseManifest(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, m.Flags)
+
+	_, err = ParseManifest([]byte("flags:\n  - key: theme\n    defualt: light\n"))
+	assert.ErrorContains(t, err, "field defualt not found")
+	_, err = ParseManifest([]byte("flags: ["))
+	assert.Error(t, err)
+}
+
+func TestLoadManifest(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "flags.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("flags:\n  - {key: theme, type: string}\n"), 0o600))
+	m, err := LoadManifest(path)
+	require.NoError(t, err)
+	assert.Equal(t, Manifest{Flags: []Definition{{Key: "theme", Type: TypeString}}}, m)
+
+	_, err = LoadManifest(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.ErrorContains(t, err, "failed to read the manifest")
+}
+
+func TestManifestFromFlags(t *testing.T) {
+	t.Parallel()
+	boolVariations := []inspect.Variation{
+		{ID: "variation-on", Name: "on", Value: "true"},
+		{ID: "variation-off", Name: "off", Value: "false"},
+	}
+	m, err := ManifestFromFlags([]inspect.Flag{
+		{
+			ID:            "checkout",
+			Name:          "New checkout",
+			Description:   "Enables the new checkout flow",
+			VariationType: "BOOLEAN",
+			Variations:    boolVariations,
+			OffVariation:  "off",
+		},
+		{ID: "legacy", VariationType: "BOOLEAN", Archived: true},
+		{ID: "theme", Name: "Theme", VariationType: "STRING", Variations: []inspect.Variation{{ID: "dark", Value: "dark"}}},
+		{
+			ID:            "max-items",
+			Name:          "max-items",
+			VariationType: "NUMBER",
+			Variations:    []inspect.Variation{{ID: "ten", Value: "10"}, {ID: "twenty", Value: "20"}},
+			OffVariation:  "ten",
+		},
+		{
+			ID:            "ratio",
+			VariationType: "NUMBER",
+			Variations:    []inspect.Variation{{ID: "half", Value: "0.5"}, {ID: "one", Value: "1"}},
+			OffVariation:  "one",
+		},
+		{
+			ID:            "config",
+			VariationType: "JSON",
+			Variations:    []inspect.Variation{{ID: "v1", Value: `{"retries": 3}`}},
+			OffVariation:  "v1",
+		},
+		{
+			ID:            "layout",
+			VariationType: "YAML",
+			Variations:    []inspect.Variation{{ID: "v1", Value: "columns: 2"}},
+			OffVariation:  "v1",
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, Manifest{Flags: []Definition{
+		{Key: "checkout", Type: TypeBool, Default: false, Description: "Enables the new checkout flow"},
+		{Key: "theme", Type: TypeString, Default: "", Description: "Theme"},
+		{Key: "max-items", Type: TypeInt, Default: int64(10)},
+		{Key: "ratio", Type: TypeFloat, Default: float64(1)},
+		{Key: "config", Type: TypeObject, Default: map[string]any{"retries": float64(3)}},
+		{Key: "layout", Type: TypeObject, Default: map[string]any{"columns": 2}},
+	}}, m)
+
+	// The manifest generates the accessors
+	_, err = Generate(m, "flags", "")
+	assert.NoError(t, err)
+}
+
+func TestManifestFromFlagsErrors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		desc     string
+		flag     inspect.Flag
+		expected string
+	}{
+		{
+			desc:     "unknown variation type",
+			flag:     inspect.Flag{ID: "f", VariationType: "DATE"},
+			expected: "flag f: unknown variation type DATE",
+		},
+		{
+			desc:     "missing off variation",
+			flag:     inspect.Flag{ID: "f", VariationType: "STRING", OffVariation: "off"},
+			expected: "flag f: off variation off not found",
+		},
+		{
+			desc: "invalid off variation value",
+			flag: inspect.Flag{
+				ID:            "f",
+				VariationType: "BOOLEAN",
+				Variations:    []inspect.Variation{{ID: "off", Value: "no"}},
+				OffVariation:  "off",
+			},
+			expected: "flag f: invalid value of the off variation off",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			_, err := ManifestFromFlags([]inspect.Flag{test.flag})
+			assert.ErrorContains(t, err, test.expected)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package provider
+
+// flagKeyConfig maps the flag keys used by the code to Bucketeer feature IDs
+type flagKeyConfig struct {
+	aliases  map[string]string
+	resolver func(key string) string
+	prefix   string
+}
+
+// WithFlagAliases evaluates the flag keys of the mapping with the Bucketeer feature IDs they are mapped to,
+// e.g. {"checkout": "feature-checkout-v2"}, so flags can be renamed in Bucketeer without changing the code.
+// An alias takes precedence over WithFlagResolver and WithFlagPrefix.
+func WithFlagAliases(aliases map[string]string) Option {
+	return func(p *Provider) {
+		if p.flagKeys.aliases == nil {
+			p.flagKeys.aliases = make(map[string]string, len(aliases))
+		}
+		for k, v := range aliases {
+			p.flagKeys.aliases[k] = v
+		}
+	}
+}
+
+// WithFlagResolver evaluates the flags with the Bucketeer feature ID returned by the resolver for the flag key.
+// The prefix set with WithFlagPrefix is prepended to the returned ID.
+func WithFlagResolver(resolver func(key string) string) Option {
+	return func(p *Provider) {
+		p.flagKeys.resolver = resolver
+	}
+}
+
+// WithFlagPrefix prepends the prefix to the flag keys, e.g. "checkout-" to evaluate the flag "new-ui"
+// as the feature "checkout-new-ui", so several applications can share a tag.
+// To use a prefix per OpenFeature domain, bind a provider with its own prefix to each domain.
+func WithFlagPrefix(prefix string) Option {
+	return func(p *Provider) {
+		p.flagKeys.prefix = prefix
+	}
+}
+
+// featureID returns the Bucketeer feature ID of the flag key
+func (c flagKeyConfig) featureID(key string) string {
+	if id, ok := c.aliases[key]; ok {
+		return id
+	}
+	if c.resolver != nil {
+		key = c.resolver(key)
+	}
+	return c.prefix + key
+}
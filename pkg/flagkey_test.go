@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/model"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	mockProvider "github.com/bucketeer-io/openfeature-go-server-sdk/test/mock/provider"
+)
+
+func TestFeatureID(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		desc     string
+		opts     []Option
+		key      string
+		expected string
+	}{
+		{desc: "no mapping", key: "checkout", expected: "checkout"},
+		{
+			desc:     "alias",
+			opts:     []Option{WithFlagAliases(map[string]string{"checkout": "feature-checkout-v2"})},
+			key:      "checkout",
+			expected: "feature-checkout-v2",
+		},
+		{
+			desc: "alias from another option",
+			opts: []Option{
+				WithFlagAliases(map[string]string{"checkout": "feature-checkout-v2"}),
+				WithFlagAliases(map[string]string{"search": "feature-search"}),
+			},
+			key:      "checkout",
+			expected: "feature-checkout-v2",
+		},
+		{
+			desc:     "prefix",
+			opts:     []Option{WithFlagPrefix("app-")},
+			key:      "checkout",
+			expected: "app-checkout",
+		},
+		{
+			desc: "alias without prefix",
+			opts: []Option{
+				WithFlagPrefix("app-"),
+				WithFlagAliases(map[string]string{"checkout": "feature-checkout-v2"}),
+			},
+			key:      "checkout",
+			expected: "feature-checkout-v2",
+		},
+		{
+			desc: "resolver with prefix",
+			opts: []Option{
+				WithFlagPrefix("app-"),
+				WithFlagResolver(func(key string) string { return strings.ReplaceAll(key, ".", "-") }),
+			},
+			key:      "checkout.new-ui",
+			expected: "app-checkout-new-ui",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			p := newTestProvider(nil, test.opts...)
+			assert.Equal(t, test.expected, p.flagKeys.featureID(test.key))
+		})
+	}
+}
+
+func TestEvaluationWithFlagAlias(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSDK := mockProvider.NewMockBucketeerSDK(ctrl)
+	mockSDK.EXPECT().
+		StringVariationDetails(gomock.Any(), gomock.Any(), "feature-checkout-v2", "default").
+		Return(model.BKTEvaluationDetails[string]{
+			FeatureID:      "feature-checkout-v2",
+			FeatureVersion: 3,
+			UserID:         "test-user",
+			VariationID:    "variation-new",
+			VariationName:  "new",
+			VariationValue: "new",
+			Reason:         model.EvaluationReasonRule,
+		}).
+		Times(1)
+	provider := newTestProvider(mockSDK, WithFlagAliases(map[string]string{"checkout": "feature-checkout-v2"}))
+
+	result := provider.StringEvaluation(
+		context.Background(),
+		"checkout",
+		"default",
+		openfeature.FlattenedContext{openfeature.TargetingKey: "test-user"},
+	)
+	assert.Equal(t, openfeature.StringResolutionDetail{
+		Value: "new",
+		ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+			Reason:  openfeature.TargetingMatchReason,
+			Variant: "new",
+			FlagMetadata: openfeature.FlagMetadata{
+				FlagMetadataBucketeerReason: "RULE",
+				FlagMetadataFeatureVersion:  int64(3),
+				FlagMetadataVariationID:     "variation-new",
+				FlagMetadataFeatureID:       "feature-checkout-v2",
+			},
+		},
+	}, result)
+}
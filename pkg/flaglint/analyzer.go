@@ -0,0 +1,195 @@
+// Package flaglint provides a go/analysis analyzer finding the flag evaluations of a Go codebase,
+// to list the flag keys used by the code and diff them against Bucketeer.
+//
+// The analyzer finds the calls to the evaluation methods of the OpenFeature clients and providers,
+// e.g. Client.BooleanValue or FeatureProvider.StringEvaluation, and of the Bucketeer provider.
+// It reports the flag keys that are not constants, which can't be listed.
+// Its result is the evaluations of the package, which NewInventory aggregates to find
+// the flags evaluated with different types or default values, see cmd/flaglint.
+package flaglint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"reflect"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// Packages of the evaluation methods
+const (
+	openFeaturePath = "github.com/open-feature/go-sdk/openfeature"
+	providerPath    = "github.com/bucketeer-io/openfeature-go-server-sdk/pkg"
+)
+
+// Flag types
+const (
+	TypeBool   = "bool"
+	TypeString = "string"
+	TypeInt    = "int"
+	TypeFloat  = "float"
+	TypeObject = "object"
+)
+
+// evaluationMethods are the flag types of the evaluation methods,
+// which take the flag key and the default value as second and third arguments
+var evaluationMethods = map[string]string{
+	"Boolean":             TypeBool,
+	"BooleanValue":        TypeBool,
+	"BooleanValueDetails": TypeBool,
+	"BooleanEvaluation":   TypeBool,
+	"String":              TypeString,
+	"StringValue":         TypeString,
+	"StringValueDetails":  TypeString,
+	"StringEvaluation":    TypeString,
+	"Int":                 TypeInt,
+	"IntValue":            TypeInt,
+	"IntValueDetails":     TypeInt,
+	"IntEvaluation":       TypeInt,
+	"Float":               TypeFloat,
+	"FloatValue":          TypeFloat,
+	"FloatValueDetails":   TypeFloat,
+	"FloatEvaluation":     TypeFloat,
+	"Object":              TypeObject,
+	"ObjectValue":         TypeObject,
+	"ObjectValueDetails":  TypeObject,
+	"ObjectEvaluation":    TypeObject,
+}
+
+// Analyzer finds the flag evaluations. Its result is the []Evaluation of the package.
+var Analyzer = &analysis.Analyzer{
+	Name:       "flaglint",
+	Doc:        "find the OpenFeature flag evaluations and report the flag keys that are not constants",
+	URL:        "https://github.com/bucketeer-io/openfeature-go-server-sdk/tree/main/pkg/flaglint",
+	Requires:   []*analysis.Analyzer{inspect.Analyzer},
+	ResultType: reflect.TypeFor[[]Evaluation](),
+	Run:        run,
+}
+
+// Evaluation is a call evaluating a flag
+type Evaluation struct {
+	// Flag is the flag key, or empty if the key is not a constant
+	Flag string `json:"flag,omitempty"`
+	// KeyExpr is the source of the key expression if the key is not a constant
+	KeyExpr string `json:"keyExpr,omitempty"`
+	// Type is the flag type: bool, string, int, float or object
+	Type string `json:"type"`
+	// Default is the default value if it's a constant: a bool, string, int64 or float64
+	Default any `json:"default,omitempty"`
+	// DefaultExpr is the source of the default value if it's not a constant
+	DefaultExpr string `json:"defaultExpr,omitempty"`
+	// Method is the evaluation method, e.g. Client.BooleanValue
+	Method   string         `json:"method"`
+	Position token.Position `json:"-"`
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	evaluations := []Evaluation{}
+	insp.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		method, flagType, ok := evaluationMethod(pass.TypesInfo, call)
+		if !ok || len(call.Args) < 3 {
+			return
+		}
+		evaluation := Evaluation{
+			Type:     flagType,
+			Method:   method,
+			Position: pass.Fset.Position(call.Pos()),
+		}
+		key, defaultValue := call.Args[1], call.Args[2]
+		if tv := pass.TypesInfo.Types[key]; tv.Value != nil && tv.Value.Kind() == constant.String {
+			evaluation.Flag = constant.StringVal(tv.Value)
+		} else {
+			evaluation.KeyExpr = types.ExprString(key)
+			pass.Reportf(key.Pos(), "flag key of %s is not a constant: %s", method, evaluation.KeyExpr)
+		}
+		if value, ok := constantValue(pass.TypesInfo.Types[defaultValue].Value, flagType); ok {
+			evaluation.Default = value
+		} else {
+			evaluation.DefaultExpr = types.ExprString(defaultValue)
+		}
+		evaluations = append(evaluations, evaluation)
+	})
+	return evaluations, nil
+}
+
+// evaluationMethod returns the name and the flag type of the evaluation method called,
+// and false if the call is not a flag evaluation
+func evaluationMethod(info *types.Info, call *ast.CallExpr) (string, string, bool) {
+	fn, ok := typeutil.Callee(info, call).(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return "", "", false
+	}
+	if path := fn.Pkg().Path(); path != openFeaturePath && path != providerPath {
+		return "", "", false
+	}
+	flagType, ok := evaluationMethods[fn.Name()]
+	if !ok {
+		return "", "", false
+	}
+	sig := fn.Signature()
+	if sig.Recv() == nil || sig.Params().Len() < 3 {
+		return "", "", false
+	}
+	if !types.Identical(sig.Params().At(1).Type(), types.Typ[types.String]) {
+		return "", "", false
+	}
+	return receiverName(sig.Recv().Type()) + "." + fn.Name(), flagType, true
+}
+
+// receiverName returns the name of the receiver type, without the pointer
+func receiverName(t types.Type) string {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if named, ok := t.(*types.Named); ok {
+		return named.Obj().Name()
+	}
+	return t.String()
+}
+
+// constantValue converts a constant to a bool, string, int64 or float64.
+// Numbers are converted to the flag type, so 1 and 1.0 are the same float default value.
+func constantValue(value constant.Value, flagType string) (any, bool) {
+	if value == nil {
+		return nil, false
+	}
+	switch flagType {
+	case TypeInt:
+		value = constant.ToInt(value)
+	case TypeFloat:
+		value = constant.ToFloat(value)
+	}
+	switch value.Kind() {
+	case constant.Bool:
+		return constant.BoolVal(value), true
+	case constant.String:
+		return constant.StringVal(value), true
+	case constant.Int:
+		if i, ok := constant.Int64Val(value); ok {
+			return i, true
+		}
+	case constant.Float:
+		f, _ := constant.Float64Val(value)
+		return f, true
+	}
+	return nil, false
+}
+
+// formatDefault formats a default value for the messages
+func formatDefault(e Evaluation) string {
+	if e.DefaultExpr != "" {
+		return e.DefaultExpr
+	}
+	if s, ok := e.Default.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	return fmt.Sprint(e.Default)
+}
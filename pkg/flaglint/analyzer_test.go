@@ -0,0 +1,75 @@
+package flaglint
+
+import (
+	"go/constant"
+	"go/token"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	t.Parallel()
+	results := analysistest.Run(t, analysistest.TestData(), Analyzer, "app")
+	require.Len(t, results, 1)
+	evaluations, ok := results[0].Result.([]Evaluation)
+	require.True(t, ok)
+
+	// Keep the line of the positions only
+	for i, e := range evaluations {
+		assert.Equal(t, "app.go", filepath.Base(e.Position.Filename))
+		evaluations[i].Position = token.Position{Line: e.Position.Line}
+	}
+	assert.Equal(t, []Evaluation{
+		{Flag: "new-checkout", Type: TypeBool, Default: false, Method: "Client.BooleanValue", Position: line(15)},
+		{Flag: "new-checkout", Type: TypeBool, Default: true, Method: "Client.BooleanValueDetails", Position: line(16)},
+		{Flag: "new-checkout", Type: TypeBool, Default: false, Method: "Client.Boolean", Position: line(17)},
+		{Flag: "theme", Type: TypeString, Default: "light", Method: "Client.StringValue", Position: line(18)},
+		{Flag: "max-items", Type: TypeInt, Default: int64(10), Method: "Client.IntValue", Position: line(19)},
+		{Flag: "ratio", Type: TypeFloat, Default: float64(1), Method: "Client.FloatValue", Position: line(20)},
+		{Flag: "config", Type: TypeObject, DefaultExpr: "nil", Method: "Client.ObjectValue", Position: line(21)},
+		{Flag: "theme", Type: TypeBool, Default: false, Method: "Provider.BooleanEvaluation", Position: line(22)},
+		{KeyExpr: "name", Type: TypeString, Default: "", Method: "Client.StringValue", Position: line(24)},
+		{
+			KeyExpr:  `"flag-" + name`,
+			Type:     TypeBool,
+			Default:  false,
+			Method:   "Client.BooleanValue",
+			Position: line(25),
+		},
+	}, evaluations)
+}
+
+func line(l int) token.Position {
+	return token.Position{Line: l}
+}
+
+func TestConstantValue(t *testing.T) {
+	t.Parallel()
+	patterns := []struct {
+		desc     string
+		flagType string
+		value    constant.Value
+		expected any
+	}{
+		{desc: "bool", flagType: TypeBool, value: constant.MakeBool(true), expected: true},
+		{desc: "string", flagType: TypeString, value: constant.MakeString("on"), expected: "on"},
+		{desc: "int", flagType: TypeInt, value: constant.MakeInt64(3), expected: int64(3)},
+		{desc: "float from an int", flagType: TypeFloat, value: constant.MakeInt64(3), expected: float64(3)},
+		{desc: "float", flagType: TypeFloat, value: constant.MakeFloat64(1.5), expected: 1.5},
+	}
+	for _, p := range patterns {
+		t.Run(p.desc, func(t *testing.T) {
+			t.Parallel()
+			value, ok := constantValue(p.value, p.flagType)
+			assert.True(t, ok)
+			assert.Equal(t, p.expected, value)
+		})
+	}
+
+	_, ok := constantValue(nil, TypeObject)
+	assert.False(t, ok)
+}
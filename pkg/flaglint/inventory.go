@@ -0,0 +1,176 @@
+package flaglint
+
+import (
+	"cmp"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Problem kinds
+const (
+	// ProblemNonConstantKey is an evaluation with a flag key that is not a constant
+	ProblemNonConstantKey = "non-constant-key"
+	// ProblemTypeMismatch is a flag evaluated with different types
+	ProblemTypeMismatch = "type-mismatch"
+	// ProblemInconsistentDefault is a flag evaluated with different constant default values
+	ProblemInconsistentDefault = "inconsistent-default"
+)
+
+// Inventory lists the flags evaluated by a codebase, e.g. to diff them against the flags of Bucketeer
+type Inventory struct {
+	// Flags are the flags with a constant key, sorted by key
+	Flags []Flag `json:"flags"`
+	// NonConstantKeys are the evaluations with a flag key that is not a constant
+	NonConstantKeys []Use `json:"nonConstantKeys"`
+	// Problems are sorted by kind and flag
+	Problems []Problem `json:"problems"`
+}
+
+// Flag is a flag evaluated by the codebase
+type Flag struct {
+	Key string `json:"key"`
+	// Types are the types the flag is evaluated with, sorted
+	Types []string `json:"types"`
+	// Defaults are the constant default values of the flag, in the order of the uses
+	Defaults []any `json:"defaults"`
+	// Uses are the evaluations of the flag, sorted by position
+	Uses []Use `json:"uses"`
+}
+
+// Use is an evaluation with its position
+type Use struct {
+	Evaluation
+	// Pos is the position of the call, as file:line:column
+	Pos string `json:"pos"`
+}
+
+// Problem is a flag evaluated inconsistently, or an evaluation with a key that is not a constant
+type Problem struct {
+	Kind    string `json:"kind"`
+	Flag    string `json:"flag,omitempty"`
+	Message string `json:"message"`
+	// Positions are the positions of the evaluations
+	Positions []string `json:"positions"`
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s", p.Positions[0], p.Message)
+}
+
+// NewInventory aggregates the evaluations of the packages.
+// The positions are relative to dir if they are in dir.
+func NewInventory(evaluations []Evaluation, dir string) Inventory {
+	inventory := Inventory{
+		Flags:           []Flag{},
+		NonConstantKeys: []Use{},
+		Problems:        []Problem{},
+	}
+	byKey := make(map[string][]Use)
+	for _, e := range evaluations {
+		use := Use{Evaluation: e, Pos: relativePosition(e, dir)}
+		if e.Flag == "" {
+			inventory.NonConstantKeys = append(inventory.NonConstantKeys, use)
+			continue
+		}
+		byKey[e.Flag] = append(byKey[e.Flag], use)
+	}
+
+	slices.SortFunc(inventory.NonConstantKeys, compareUses)
+	for _, use := range inventory.NonConstantKeys {
+		inventory.Problems = append(inventory.Problems, Problem{
+			Kind:      ProblemNonConstantKey,
+			Message:   fmt.Sprintf("flag key of %s is not a constant: %s", use.Method, use.KeyExpr),
+			Positions: []string{use.Pos},
+		})
+	}
+	for key, uses := range byKey {
+		slices.SortFunc(uses, compareUses)
+		flag := Flag{Key: key, Types: []string{}, Defaults: []any{}, Uses: uses}
+		for _, use := range uses {
+			if !slices.Contains(flag.Types, use.Type) {
+				flag.Types = append(flag.Types, use.Type)
+			}
+			if use.DefaultExpr == "" && !slices.Contains(flag.Defaults, use.Default) {
+				flag.Defaults = append(flag.Defaults, use.Default)
+			}
+		}
+		slices.Sort(flag.Types)
+		inventory.Flags = append(inventory.Flags, flag)
+		inventory.Problems = append(inventory.Problems, flagProblems(flag)...)
+	}
+	slices.SortFunc(inventory.Flags, func(a, b Flag) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	slices.SortStableFunc(inventory.Problems, func(a, b Problem) int {
+		return cmp.Or(strings.Compare(a.Kind, b.Kind), strings.Compare(a.Flag, b.Flag))
+	})
+	return inventory
+}
+
+// flagProblems returns the type mismatch and the inconsistent default values of the flag
+func flagProblems(flag Flag) []Problem {
+	var problems []Problem
+	if len(flag.Types) > 1 {
+		problems = append(problems, Problem{
+			Kind:      ProblemTypeMismatch,
+			Flag:      flag.Key,
+			Message:   fmt.Sprintf("flag %q is evaluated as %s", flag.Key, strings.Join(flag.Types, " and ")),
+			Positions: positions(flag.Uses, func(Use) bool { return true }),
+		})
+	}
+	for _, flagType := range flag.Types {
+		var defaults []string
+		for _, use := range flag.Uses {
+			if use.Type != flagType || use.DefaultExpr != "" {
+				continue
+			}
+			if d := formatDefault(use.Evaluation); !slices.Contains(defaults, d) {
+				defaults = append(defaults, d)
+			}
+		}
+		if len(defaults) < 2 {
+			continue
+		}
+		problems = append(problems, Problem{
+			Kind: ProblemInconsistentDefault,
+			Flag: flag.Key,
+			Message: fmt.Sprintf("%s flag %q is evaluated with different default values: %s",
+				flagType, flag.Key, strings.Join(defaults, ", ")),
+			Positions: positions(flag.Uses, func(use Use) bool {
+				return use.Type == flagType && use.DefaultExpr == ""
+			}),
+		})
+	}
+	return problems
+}
+
+func positions(uses []Use, keep func(Use) bool) []string {
+	var pos []string
+	for _, use := range uses {
+		if keep(use) {
+			pos = append(pos, use.Pos)
+		}
+	}
+	return pos
+}
+
+func compareUses(a, b Use) int {
+	return cmp.Or(
+		strings.Compare(a.Position.Filename, b.Position.Filename),
+		cmp.Compare(a.Position.Line, b.Position.Line),
+		cmp.Compare(a.Position.Column, b.Position.Column),
+	)
+}
+
+// relativePosition returns the position of the evaluation, relative to dir if it's in dir
+func relativePosition(e Evaluation, dir string) string {
+	pos := e.Position
+	if dir != "" {
+		if rel, err := filepath.Rel(dir, pos.Filename); err == nil && !strings.HasPrefix(rel, "..") {
+			pos.Filename = filepath.ToSlash(rel)
+		}
+	}
+	return pos.String()
+}
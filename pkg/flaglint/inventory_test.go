@@ -0,0 +1,87 @@
+package flaglint
+
+import (
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewInventory(t *testing.T) {
+	t.Parallel()
+	pos := func(file string, line int) token.Position {
+		return token.Position{Filename: file, Line: line, Column: 2}
+	}
+	evaluations := []Evaluation{
+		{Flag: "theme", Type: TypeString, Default: "light", Method: "Client.StringValue", Position: pos("/app/b.go", 3)},
+		{Flag: "theme", Type: TypeString, Default: "dark", Method: "Client.StringValue", Position: pos("/app/a.go", 7)},
+		{Flag: "theme", Type: TypeBool, Default: false, Method: "Client.BooleanValue", Position: pos("/app/a.go", 9)},
+		{
+			Flag:        "theme",
+			Type:        TypeString,
+			DefaultExpr: "fallback",
+			Method:      "Client.StringValue",
+			Position:    pos("/app/c.go", 1),
+		},
+		{Flag: "checkout", Type: TypeBool, Default: false, Method: "Client.BooleanValue", Position: pos("/app/a.go", 5)},
+		{Flag: "checkout", Type: TypeBool, Default: false, Method: "Client.Boolean", Position: pos("/app/b.go", 1)},
+		{KeyExpr: "name", Type: TypeInt, Default: int64(0), Method: "Client.IntValue", Position: pos("/lib/d.go", 4)},
+	}
+
+	inventory := NewInventory(evaluations, "/app")
+
+	use := func(e Evaluation, p string) Use {
+		return Use{Evaluation: e, Pos: p}
+	}
+	assert.Equal(t, Inventory{
+		Flags: []Flag{
+			{
+				Key:      "checkout",
+				Types:    []string{TypeBool},
+				Defaults: []any{false},
+				Uses:     []Use{use(evaluations[4], "a.go:5:2"), use(evaluations[5], "b.go:1:2")},
+			},
+			{
+				Key:      "theme",
+				Types:    []string{TypeBool, TypeString},
+				Defaults: []any{"dark", false, "light"},
+				Uses: []Use{
+					use(evaluations[1], "a.go:7:2"),
+					use(evaluations[2], "a.go:9:2"),
+					use(evaluations[0], "b.go:3:2"),
+					use(evaluations[3], "c.go:1:2"),
+				},
+			},
+		},
+		NonConstantKeys: []Use{use(evaluations[6], "/lib/d.go:4:2")},
+		Problems: []Problem{
+			{
+				Kind:      ProblemInconsistentDefault,
+				Flag:      "theme",
+				Message:   `string flag "theme" is evaluated with different default values: "dark", "light"`,
+				Positions: []string{"a.go:7:2", "b.go:3:2"},
+			},
+			{
+				Kind:      ProblemNonConstantKey,
+				Message:   "flag key of Client.IntValue is not a constant: name",
+				Positions: []string{"/lib/d.go:4:2"},
+			},
+			{
+				Kind:      ProblemTypeMismatch,
+				Flag:      "theme",
+				Message:   `flag "theme" is evaluated as bool and string`,
+				Positions: []string{"a.go:7:2", "a.go:9:2", "b.go:3:2", "c.go:1:2"},
+			},
+		},
+	}, inventory)
+	assert.Equal(t, `/lib/d.go:4:2: flag key of Client.IntValue is not a constant: name`, inventory.Problems[1].String())
+}
+
+func TestNewInventoryEmpty(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, Inventory{
+		Flags:           []Flag{},
+		NonConstantKeys: []Use{},
+		Problems:        []Problem{},
+	}, NewInventory(nil, ""))
+}
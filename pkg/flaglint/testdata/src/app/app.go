@@ -0,0 +1,28 @@
+package app
+
+import (
+	"context"
+
+	"github.com/open-feature/go-sdk/openfeature"
+
+	provider "github.com/bucketeer-io/openfeature-go-server-sdk/pkg"
+)
+
+const newCheckout = "new-checkout"
+
+func evaluate(ctx context.Context, client *openfeature.Client, p *provider.Provider, name string) {
+	evalCtx := openfeature.EvaluationContext{}
+	client.BooleanValue(ctx, newCheckout, false, evalCtx)
+	client.BooleanValueDetails(ctx, "new-checkout", true, evalCtx)
+	client.Boolean(ctx, "new-"+"checkout", false, evalCtx)
+	client.StringValue(ctx, "theme", "light", evalCtx)
+	client.IntValue(ctx, "max-items", 10, evalCtx)
+	client.FloatValue(ctx, "ratio", 1, evalCtx)
+	client.ObjectValue(ctx, "config", nil, evalCtx)
+	p.BooleanEvaluation(ctx, "theme", false, nil)
+
+	client.StringValue(ctx, name, "", evalCtx)             // want `flag key of Client.StringValue is not a constant: name`
+	client.BooleanValue(ctx, "flag-"+name, false, evalCtx) // want `flag key of Client.BooleanValue is not a constant: "flag-" \+ name`
+
+	client.Track(ctx, name, evalCtx)
+}
@@ -0,0 +1,13 @@
+package provider
+
+import "context"
+
+type FlattenedContext map[string]any
+
+type BoolResolutionDetail struct{ Value bool }
+
+type Provider struct{}
+
+func (p *Provider) BooleanEvaluation(ctx context.Context, flag string, defaultValue bool, evalCtx FlattenedContext) BoolResolutionDetail {
+	return BoolResolutionDetail{Value: defaultValue}
+}
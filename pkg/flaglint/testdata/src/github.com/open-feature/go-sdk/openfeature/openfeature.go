@@ -0,0 +1,40 @@
+package openfeature
+
+import "context"
+
+type EvaluationContext struct{}
+
+type BooleanEvaluationDetails struct{ Value bool }
+
+type Client struct{}
+
+func (c *Client) BooleanValue(ctx context.Context, flag string, defaultValue bool, evalCtx EvaluationContext) (bool, error) {
+	return defaultValue, nil
+}
+
+func (c *Client) BooleanValueDetails(ctx context.Context, flag string, defaultValue bool, evalCtx EvaluationContext) (BooleanEvaluationDetails, error) {
+	return BooleanEvaluationDetails{Value: defaultValue}, nil
+}
+
+func (c *Client) Boolean(ctx context.Context, flag string, defaultValue bool, evalCtx EvaluationContext) bool {
+	return defaultValue
+}
+
+func (c *Client) StringValue(ctx context.Context, flag string, defaultValue string, evalCtx EvaluationContext) (string, error) {
+	return defaultValue, nil
+}
+
+func (c *Client) IntValue(ctx context.Context, flag string, defaultValue int64, evalCtx EvaluationContext) (int64, error) {
+	return defaultValue, nil
+}
+
+func (c *Client) FloatValue(ctx context.Context, flag string, defaultValue float64, evalCtx EvaluationContext) (float64, error) {
+	return defaultValue, nil
+}
+
+func (c *Client) ObjectValue(ctx context.Context, flag string, defaultValue any, evalCtx EvaluationContext) (any, error) {
+	return defaultValue, nil
+}
+
+// Track is not an evaluation
+func (c *Client) Track(ctx context.Context, trackingEventName string, evalCtx EvaluationContext) {}
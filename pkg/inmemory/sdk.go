@@ -0,0 +1,201 @@
+// Package inmemory provides a Bucketeer SDK evaluating flags defined in memory, without connecting to Bucketeer.
+//
+// It's meant for tests and local development: flags are evaluated like the Bucketeer SDK does,
+// with the same reasons and value parsing, but no evaluation or goal event is sent.
+package inmemory
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/model"
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/user"
+
+	provider "github.com/bucketeer-io/openfeature-go-server-sdk/pkg"
+	"github.com/bucketeer-io/openfeature-go-server-sdk/pkg/internal/variation"
+)
+
+var _ provider.BucketeerSDK = (*SDK)(nil)
+
+// Variation is a variation of a flag
+type Variation struct {
+	ID    string
+	Name  string
+	Value string
+}
+
+// Rule serves a variation to the users whose attribute is one of the values
+type Rule struct {
+	Attribute string
+	Values    []string
+	Variation string
+}
+
+// Flag is a flag evaluated by the SDK. Variations are referenced by their ID.
+//
+// A disabled flag serves OffVariation with the OFF_VARIATION reason.
+// Otherwise, the variation targeting the user ID is served with the TARGET reason,
+// then the variation of the first matching rule with the RULE reason,
+// and DefaultVariation with the DEFAULT reason.
+type Flag struct {
+	Version          int32
+	Variations       []Variation
+	DefaultVariation string
+	OffVariation     string
+	Disabled         bool
+	// Targets maps user IDs to variation IDs
+	Targets map[string]string
+	Rules   []Rule
+}
+
+// SDK evaluates flags defined in memory. It's safe for concurrent use.
+type SDK struct {
+	mu            sync.RWMutex
+	flags         map[string]Flag
+	failureReason model.EvaluationReason
+	closed        bool
+}
+
+// New returns an SDK evaluating the flags, keyed by flag ID
+func New(flags map[string]Flag) *SDK {
+	s := &SDK{flags: make(map[string]Flag, len(flags))}
+	for id, flag := range flags {
+		s.flags[id] = flag
+	}
+	return s
+}
+
+// SetFlag adds or replaces a flag
+func (s *SDK) SetFlag(id string, flag Flag) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.flags[id] = flag
+}
+
+// DeleteFlag deletes a flag, so it's not found anymore
+func (s *SDK) DeleteFlag(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.flags, id)
+}
+
+// SetFailureReason makes every evaluation return the default value with the reason,
+// e.g. model.EvaluationReasonErrorException to simulate a Bucketeer outage.
+// An empty reason evaluates the flags again.
+func (s *SDK) SetFailureReason(reason model.EvaluationReason) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failureReason = reason
+}
+
+// Closed returns true if the SDK has been closed
+func (s *SDK) Closed() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.closed
+}
+
+// BoolVariationDetails evaluates a boolean flag
+func (s *SDK) BoolVariationDetails(
+	ctx context.Context,
+	user *user.User,
+	featureID string,
+	defaultValue bool,
+) model.BKTEvaluationDetails[bool] {
+	return variation.Details(s.evaluate, user, featureID, defaultValue, variation.ParseBool)
+}
+
+// StringVariationDetails evaluates a string flag
+func (s *SDK) StringVariationDetails(
+	ctx context.Context,
+	user *user.User,
+	featureID string,
+	defaultValue string,
+) model.BKTEvaluationDetails[string] {
+	return variation.Details(s.evaluate, user, featureID, defaultValue, variation.ParseString)
+}
+
+// Int64VariationDetails evaluates an integer flag.
+// Like the Bucketeer SDK, a float value is truncated.
+func (s *SDK) Int64VariationDetails(
+	ctx context.Context,
+	user *user.User,
+	featureID string,
+	defaultValue int64,
+) model.BKTEvaluationDetails[int64] {
+	return variation.Details(s.evaluate, user, featureID, defaultValue, variation.ParseInt64)
+}
+
+// Float64VariationDetails evaluates a float flag
+func (s *SDK) Float64VariationDetails(
+	ctx context.Context,
+	user *user.User,
+	featureID string,
+	defaultValue float64,
+) model.BKTEvaluationDetails[float64] {
+	return variation.Details(s.evaluate, user, featureID, defaultValue, variation.ParseFloat64)
+}
+
+// ObjectVariationDetails evaluates a JSON flag
+func (s *SDK) ObjectVariationDetails(
+	ctx context.Context,
+	user *user.User,
+	featureID string,
+	defaultValue interface{},
+) model.BKTEvaluationDetails[interface{}] {
+	return variation.Details(s.evaluate, user, featureID, defaultValue, variation.ParseObject)
+}
+
+// Close closes the SDK. Flags are still evaluated after it's closed.
+func (s *SDK) Close(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+// evaluate evaluates the flag for the user, see variation.EvaluateFunc
+func (s *SDK) evaluate(user *user.User, featureID string) (model.BKTEvaluationDetails[string], bool) {
+	s.mu.RLock()
+	flag, ok := s.flags[featureID]
+	failureReason := s.failureReason
+	s.mu.RUnlock()
+	if failureReason != "" {
+		return model.BKTEvaluationDetails[string]{Reason: failureReason}, false
+	}
+	if !ok {
+		return model.BKTEvaluationDetails[string]{Reason: model.EvaluationReasonErrorFlagNotFound}, false
+	}
+
+	variationID, reason := flag.evaluate(user)
+	i := slices.IndexFunc(flag.Variations, func(v Variation) bool { return v.ID == variationID })
+	if i < 0 {
+		return model.BKTEvaluationDetails[string]{Reason: model.EvaluationReasonErrorException}, false
+	}
+	return model.BKTEvaluationDetails[string]{
+		FeatureID:      featureID,
+		FeatureVersion: flag.Version,
+		UserID:         user.ID,
+		VariationID:    flag.Variations[i].ID,
+		VariationName:  flag.Variations[i].Name,
+		VariationValue: flag.Variations[i].Value,
+		Reason:         reason,
+	}, true
+}
+
+// evaluate returns the ID of the variation served to the user and the reason
+func (f Flag) evaluate(user *user.User) (string, model.EvaluationReason) {
+	if f.Disabled {
+		return f.OffVariation, model.EvaluationReasonOffVariation
+	}
+	if variationID, ok := f.Targets[user.ID]; ok {
+		return variationID, model.EvaluationReasonTarget
+	}
+	for _, rule := range f.Rules {
+		if value, ok := user.Data[rule.Attribute]; ok && slices.Contains(rule.Values, value) {
+			return rule.Variation, model.EvaluationReasonRule
+		}
+	}
+	return f.DefaultVariation, model.EvaluationReasonDefault
+}
@@ -0,0 +1,204 @@
+package inmemory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/model"
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/user"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSDK() *SDK {
+	return New(map[string]Flag{
+		"feature": {
+			Version: 3,
+			Variations: []Variation{
+				{ID: "variation-on", Name: "on", Value: "true"},
+				{ID: "variation-off", Name: "off", Value: "false"},
+			},
+			DefaultVariation: "variation-off",
+			OffVariation:     "variation-off",
+			Targets:          map[string]string{"beta-user": "variation-on"},
+			Rules: []Rule{
+				{Attribute: "plan", Values: []string{"pro", "enterprise"}, Variation: "variation-on"},
+			},
+		},
+		"disabled": {
+			Variations: []Variation{
+				{ID: "variation-on", Name: "on", Value: "true"},
+				{ID: "variation-off", Name: "off", Value: "false"},
+			},
+			DefaultVariation: "variation-on",
+			OffVariation:     "variation-off",
+			Disabled:         true,
+		},
+		"text": {
+			Variations:       []Variation{{ID: "variation-text", Name: "text", Value: "hello"}},
+			DefaultVariation: "variation-text",
+		},
+		"number": {
+			Variations:       []Variation{{ID: "variation-number", Name: "number", Value: "2.5"}},
+			DefaultVariation: "variation-number",
+		},
+	})
+}
+
+func TestBoolVariationDetails(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		desc     string
+		user     *user.User
+		flag     string
+		expected model.BKTEvaluationDetails[bool]
+	}{
+		{
+			desc: "target",
+			user: user.NewUser("beta-user", nil),
+			flag: "feature",
+			expected: model.BKTEvaluationDetails[bool]{
+				FeatureID:      "feature",
+				FeatureVersion: 3,
+				UserID:         "beta-user",
+				VariationID:    "variation-on",
+				VariationName:  "on",
+				VariationValue: true,
+				Reason:         model.EvaluationReasonTarget,
+			},
+		},
+		{
+			desc: "rule",
+			user: user.NewUser("user", map[string]string{"plan": "pro"}),
+			flag: "feature",
+			expected: model.BKTEvaluationDetails[bool]{
+				FeatureID:      "feature",
+				FeatureVersion: 3,
+				UserID:         "user",
+				VariationID:    "variation-on",
+				VariationName:  "on",
+				VariationValue: true,
+				Reason:         model.EvaluationReasonRule,
+			},
+		},
+		{
+			desc: "default",
+			user: user.NewUser("user", map[string]string{"plan": "free"}),
+			flag: "feature",
+			expected: model.BKTEvaluationDetails[bool]{
+				FeatureID:      "feature",
+				FeatureVersion: 3,
+				UserID:         "user",
+				VariationID:    "variation-off",
+				VariationName:  "off",
+				VariationValue: false,
+				Reason:         model.EvaluationReasonDefault,
+			},
+		},
+		{
+			desc: "disabled",
+			user: user.NewUser("user", nil),
+			flag: "disabled",
+			expected: model.BKTEvaluationDetails[bool]{
+				FeatureID:      "disabled",
+				UserID:         "user",
+				VariationID:    "variation-off",
+				VariationName:  "off",
+				VariationValue: false,
+				Reason:         model.EvaluationReasonOffVariation,
+			},
+		},
+		{
+			desc: "flag not found",
+			user: user.NewUser("user", nil),
+			flag: "missing",
+			expected: model.BKTEvaluationDetails[bool]{
+				FeatureID:      "missing",
+				UserID:         "user",
+				VariationValue: true,
+				Reason:         model.EvaluationReasonErrorFlagNotFound,
+			},
+		},
+		{
+			desc: "wrong type",
+			user: user.NewUser("user", nil),
+			flag: "text",
+			expected: model.BKTEvaluationDetails[bool]{
+				FeatureID:      "text",
+				UserID:         "user",
+				VariationValue: true,
+				Reason:         model.EvaluationReasonErrorWrongType,
+			},
+		},
+		{
+			desc: "user ID not specified",
+			user: user.NewUser("", nil),
+			flag: "feature",
+			expected: model.BKTEvaluationDetails[bool]{
+				FeatureID:      "feature",
+				VariationValue: true,
+				Reason:         model.EvaluationReasonErrorUserIDNotSpecified,
+			},
+		},
+		{
+			desc: "feature ID not specified",
+			user: user.NewUser("user", nil),
+			flag: "",
+			expected: model.BKTEvaluationDetails[bool]{
+				UserID:         "user",
+				VariationValue: true,
+				Reason:         model.EvaluationReasonErrorFeatureFlagIDNotSpecified,
+			},
+		},
+	}
+
+	sdk := newTestSDK()
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, test.expected, sdk.BoolVariationDetails(context.Background(), test.user, test.flag, true))
+		})
+	}
+}
+
+func TestValueParsing(t *testing.T) {
+	t.Parallel()
+	sdk := newTestSDK()
+	u := user.NewUser("user", nil)
+
+	assert.Equal(t, "hello", sdk.StringVariationDetails(context.Background(), u, "text", "").VariationValue)
+	assert.Equal(t, 2.5, sdk.Float64VariationDetails(context.Background(), u, "number", 0).VariationValue)
+	// Like the Bucketeer SDK, floats are truncated
+	assert.Equal(t, int64(2), sdk.Int64VariationDetails(context.Background(), u, "number", 0).VariationValue)
+	assert.Equal(t, 2.5, sdk.ObjectVariationDetails(context.Background(), u, "number", nil).VariationValue)
+	assert.Equal(t,
+		model.EvaluationReasonErrorWrongType,
+		sdk.ObjectVariationDetails(context.Background(), u, "text", nil).Reason,
+	)
+}
+
+func TestUpdates(t *testing.T) {
+	t.Parallel()
+	sdk := newTestSDK()
+	u := user.NewUser("user", nil)
+
+	sdk.SetFlag("text", Flag{
+		Variations:       []Variation{{ID: "variation-text", Name: "text", Value: "updated"}},
+		DefaultVariation: "variation-text",
+	})
+	assert.Equal(t, "updated", sdk.StringVariationDetails(context.Background(), u, "text", "").VariationValue)
+
+	sdk.SetFailureReason(model.EvaluationReasonErrorException)
+	evaluation := sdk.StringVariationDetails(context.Background(), u, "text", "default")
+	assert.Equal(t, "default", evaluation.VariationValue)
+	assert.Equal(t, model.EvaluationReasonErrorException, evaluation.Reason)
+	sdk.SetFailureReason("")
+
+	sdk.DeleteFlag("text")
+	evaluation = sdk.StringVariationDetails(context.Background(), u, "text", "default")
+	assert.Equal(t, "default", evaluation.VariationValue)
+	assert.Equal(t, model.EvaluationReasonErrorFlagNotFound, evaluation.Reason)
+
+	assert.False(t, sdk.Closed())
+	assert.NoError(t, sdk.Close(context.Background()))
+	assert.True(t, sdk.Closed())
+}
@@ -0,0 +1,182 @@
+package inspect
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Graph is the dependency graph of the flags, exported as JSON with encoding/json or as DOT with WriteDOT
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	// Edges go from a flag to the flag it depends on
+	Edges []Edge `json:"edges"`
+
+	dependencies map[string][]string
+	dependents   map[string][]string
+}
+
+// Node is a flag of the graph
+type Node struct {
+	ID       string `json:"id"`
+	Name     string `json:"name,omitempty"`
+	Enabled  bool   `json:"enabled"`
+	Archived bool   `json:"archived,omitempty"`
+	// Missing is a flag depended on, but not in the cache, e.g. a deleted flag
+	Missing bool `json:"missing,omitempty"`
+}
+
+// Edge is a dependency of a flag on another flag
+type Edge struct {
+	From string         `json:"from"`
+	To   string         `json:"to"`
+	Kind DependencyKind `json:"kind"`
+	// Variations are the names of the variations of To the flag requires, or their IDs if they have no name
+	Variations []string `json:"variations"`
+}
+
+// newGraph creates the graph of the flags, sorted by ID
+func newGraph(flags []Flag) *Graph {
+	g := &Graph{
+		Nodes:        make([]Node, 0, len(flags)),
+		Edges:        []Edge{},
+		dependencies: make(map[string][]string),
+		dependents:   make(map[string][]string),
+	}
+	byID := make(map[string]Flag, len(flags))
+	for _, f := range flags {
+		byID[f.ID] = f
+		g.Nodes = append(g.Nodes, Node{ID: f.ID, Name: f.Name, Enabled: f.Enabled, Archived: f.Archived})
+	}
+	missing := make(map[string]bool)
+	for _, f := range flags {
+		for _, d := range f.Dependencies {
+			to, ok := byID[d.FlagID]
+			if !ok && !missing[d.FlagID] {
+				missing[d.FlagID] = true
+				g.Nodes = append(g.Nodes, Node{ID: d.FlagID, Missing: true})
+			}
+			variations := make([]string, 0, len(d.Variations))
+			for _, v := range d.Variations {
+				variations = append(variations, to.variationName(v))
+			}
+			g.Edges = append(g.Edges, Edge{From: f.ID, To: d.FlagID, Kind: d.Kind, Variations: variations})
+			g.dependencies[f.ID] = append(g.dependencies[f.ID], d.FlagID)
+			g.dependents[d.FlagID] = append(g.dependents[d.FlagID], f.ID)
+		}
+	}
+	slices.SortFunc(g.Nodes, func(a, b Node) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return g
+}
+
+// find returns ErrFlagNotFound if a flag isn't a node of the graph.
+// The missing flags depended on are nodes, so the flags depending on a deleted flag can be found.
+func (g *Graph) find(ids ...string) error {
+	for _, id := range ids {
+		_, ok := slices.BinarySearchFunc(g.Nodes, id, func(n Node, id string) int {
+			return strings.Compare(n.ID, id)
+		})
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrFlagNotFound, id)
+		}
+	}
+	return nil
+}
+
+// variationName returns the name of the variation, or its ID if it has no name or the flag is missing
+func (f Flag) variationName(id string) string {
+	for _, v := range f.Variations {
+		if v.ID == id && v.Name != "" {
+			return v.Name
+		}
+	}
+	return id
+}
+
+// reachable returns the flags reachable from ids by following next, without ids, sorted by ID
+func (g *Graph) reachable(ids []string, next map[string][]string) []string {
+	visited := make(map[string]bool)
+	queue := slices.Clone(ids)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, n := range next[id] {
+			if !visited[n] {
+				visited[n] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+	for _, id := range ids {
+		delete(visited, id)
+	}
+	reached := make([]string, 0, len(visited))
+	for id := range visited {
+		reached = append(reached, id)
+	}
+	slices.Sort(reached)
+	return reached
+}
+
+// subgraph returns the graph of the flags, with the edges between them
+func (g *Graph) subgraph(ids []string) *Graph {
+	sub := &Graph{
+		Nodes:        []Node{},
+		Edges:        []Edge{},
+		dependencies: make(map[string][]string),
+		dependents:   make(map[string][]string),
+	}
+	for _, n := range g.Nodes {
+		if slices.Contains(ids, n.ID) {
+			sub.Nodes = append(sub.Nodes, n)
+		}
+	}
+	for _, e := range g.Edges {
+		if slices.Contains(ids, e.From) && slices.Contains(ids, e.To) {
+			sub.Edges = append(sub.Edges, e)
+			sub.dependencies[e.From] = append(sub.dependencies[e.From], e.To)
+			sub.dependents[e.To] = append(sub.dependents[e.To], e.From)
+		}
+	}
+	return sub
+}
+
+// WriteDOT writes the graph in the Graphviz DOT format, e.g. to render it with "dot -Tsvg".
+// Disabled flags are gray, archived flags are dashed, and missing flags are red.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph flags {")
+	fmt.Fprintln(bw, "  node [shape=box];")
+	for _, n := range g.Nodes {
+		label := n.ID
+		if n.Name != "" && n.Name != n.ID {
+			label = n.Name + "\n" + n.ID
+		}
+		attrs := []string{"label=" + strconv.Quote(label)}
+		switch {
+		case n.Missing:
+			attrs = append(attrs, `color="red"`, `fontcolor="red"`)
+		case !n.Enabled:
+			attrs = append(attrs, `color="gray"`, `fontcolor="gray"`)
+		}
+		if n.Archived {
+			attrs = append(attrs, `style="dashed"`)
+		}
+		fmt.Fprintf(bw, "  %s [%s];\n", strconv.Quote(n.ID), strings.Join(attrs, ", "))
+	}
+	for _, e := range g.Edges {
+		label := fmt.Sprintf("%s: %s", e.Kind, strings.Join(e.Variations, ", "))
+		attrs := []string{"label=" + strconv.Quote(label)}
+		if e.Kind == DependencyRule {
+			attrs = append(attrs, `style="dotted"`)
+		}
+		fmt.Fprintf(bw, "  %s -> %s [%s];\n", strconv.Quote(e.From), strconv.Quote(e.To), strings.Join(attrs, ", "))
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
@@ -0,0 +1,104 @@
+package inspect
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraph(t *testing.T) {
+	t.Parallel()
+	i := New(fakeSource{features: testFeatures()})
+
+	g, err := i.Graph()
+	require.NoError(t, err)
+	assert.Equal(t, []Node{
+		{ID: "banner", Archived: true},
+		{ID: "checkout", Name: "New checkout", Enabled: true},
+		{ID: "payments", Enabled: true},
+		{ID: "platform"},
+		{ID: "removed", Missing: true},
+		{ID: "search", Name: "Search", Enabled: true},
+	}, g.Nodes)
+	assert.Equal(t, []Edge{
+		{From: "banner", To: "removed", Kind: DependencyPrerequisite, Variations: []string{"variation-1"}},
+		{From: "checkout", To: "payments", Kind: DependencyPrerequisite, Variations: []string{"on"}},
+		{From: "payments", To: "platform", Kind: DependencyRule, Variations: []string{"on"}},
+	}, g.Edges)
+}
+
+func TestSubgraph(t *testing.T) {
+	t.Parallel()
+	i := New(fakeSource{features: testFeatures()})
+
+	g, err := i.Graph("payments")
+	require.NoError(t, err)
+	assert.Equal(t, []Node{
+		{ID: "checkout", Name: "New checkout", Enabled: true},
+		{ID: "payments", Enabled: true},
+		{ID: "platform"},
+	}, g.Nodes)
+	assert.Len(t, g.Edges, 2)
+
+	g, err = i.Graph("search")
+	require.NoError(t, err)
+	assert.Equal(t, []Node{{ID: "search", Name: "Search", Enabled: true}}, g.Nodes)
+	assert.Empty(t, g.Edges)
+}
+
+func TestGraphNotFound(t *testing.T) {
+	t.Parallel()
+	i := New(fakeSource{features: testFeatures()})
+	_, err := i.Graph("payments", "unknown")
+	assert.ErrorIs(t, err, ErrFlagNotFound)
+
+	// A missing flag depended on is in the graph
+	g, err := i.Graph("removed")
+	require.NoError(t, err)
+	assert.Len(t, g.Nodes, 2)
+}
+
+func TestGraphJSON(t *testing.T) {
+	t.Parallel()
+	i := New(fakeSource{features: testFeatures()})
+	g, err := i.Graph("banner")
+	require.NoError(t, err)
+
+	b, err := json.Marshal(g)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"nodes": [
+			{"id": "banner", "enabled": false, "archived": true},
+			{"id": "removed", "enabled": false, "missing": true}
+		],
+		"edges": [
+			{"from": "banner", "to": "removed", "kind": "prerequisite", "variations": ["variation-1"]}
+		]
+	}`, string(b))
+}
+
+func TestWriteDOT(t *testing.T) {
+	t.Parallel()
+	i := New(fakeSource{features: testFeatures()})
+	g, err := i.Graph()
+	require.NoError(t, err)
+
+	var b strings.Builder
+	require.NoError(t, g.WriteDOT(&b))
+	assert.Equal(t, `digraph flags {
+  node [shape=box];
+  "banner" [label="banner", color="gray", fontcolor="gray", style="dashed"];
+  "checkout" [label="New checkout\ncheckout"];
+  "payments" [label="payments"];
+  "platform" [label="platform", color="gray", fontcolor="gray"];
+  "removed" [label="removed", color="red", fontcolor="red"];
+  "search" [label="Search\nsearch"];
+  "banner" -> "removed" [label="prerequisite: variation-1"];
+  "checkout" -> "payments" [label="prerequisite: on"];
+  "payments" -> "platform" [label="rule: on", style="dotted"];
+}
+`, b.String())
+}
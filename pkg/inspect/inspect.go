@@ -0,0 +1,289 @@
+// Package inspect summarizes the flags evaluated locally, and the dependencies between them,
+// to know what depends on a flag before cleaning it up.
+//
+// A flag depends on another flag through a prerequisite, or through a rule targeting the users
+// who get some variations of the other flag. The flags are read from the cache of an SDK evaluating
+// the flags locally, e.g. dryrun.SDK, since the Bucketeer SDK doesn't expose its cache.
+package inspect
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	ftproto "github.com/bucketeer-io/bucketeer/v2/proto/feature"
+)
+
+// ErrFlagNotFound is returned when the flag is not in the cache
+var ErrFlagNotFound = errors.New("flag not found")
+
+// rolloutWeightTotal is the sum of the weights of a rollout strategy, i.e. 100%
+const rolloutWeightTotal = 100000
+
+// Source lists the flags of a local cache, e.g. dryrun.SDK
+type Source interface {
+	Features() ([]*ftproto.Feature, error)
+}
+
+// DependencyKind is how a flag depends on another flag
+type DependencyKind string
+
+// Dependency kinds
+const (
+	// DependencyPrerequisite is a prerequisite of the flag
+	DependencyPrerequisite DependencyKind = "prerequisite"
+	// DependencyRule is a rule clause matching the variations of the other flag
+	DependencyRule DependencyKind = "rule"
+)
+
+// Flag is the summary of a flag
+type Flag struct {
+	ID            string      `json:"id"`
+	Name          string      `json:"name"`
+	Description   string      `json:"description,omitempty"`
+	Enabled       bool        `json:"enabled"`
+	Archived      bool        `json:"archived,omitempty"`
+	Version       int32       `json:"version"`
+	VariationType string      `json:"variationType"`
+	Tags          []string    `json:"tags,omitempty"`
+	Maintainer    string      `json:"maintainer,omitempty"`
+	Variations    []Variation `json:"variations"`
+	// OffVariation is the name of the variation served when the flag is disabled
+	OffVariation string `json:"offVariation,omitempty"`
+	// Dependencies are the flags the flag depends on, prerequisites first
+	Dependencies []Dependency `json:"dependencies,omitempty"`
+	Targeting    Targeting    `json:"targeting"`
+}
+
+// Variation is a variation of a flag
+type Variation struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Dependency is a flag another flag depends on
+type Dependency struct {
+	FlagID string         `json:"flagId"`
+	Kind   DependencyKind `json:"kind"`
+	// Variations are the IDs of the variations of FlagID the flag requires
+	Variations []string `json:"variations"`
+}
+
+// Targeting summarizes how the variations are served to the users when the flag is enabled.
+// The variations are named by their name, or by their ID if they have no name.
+type Targeting struct {
+	// Targets are the variations served to individual users, without the users
+	Targets []Target `json:"targets,omitempty"`
+	// Rules are evaluated in order
+	Rules []Rule `json:"rules,omitempty"`
+	// Default is what is served to the users not matching a target or a rule
+	Default string `json:"default"`
+}
+
+// Target is a variation served to individual users
+type Target struct {
+	Variation string `json:"variation"`
+	Users     int    `json:"users"`
+}
+
+// Rule is a targeting rule
+type Rule struct {
+	ID string `json:"id"`
+	// Clauses are all matched by the users of the rule, e.g. "plan IN pro, enterprise"
+	Clauses []string `json:"clauses"`
+	// Serve is the variation or the rollout served to the users of the rule, e.g. "on 20%, off 80%"
+	Serve string `json:"serve"`
+}
+
+// Summarize returns the summary of a flag
+func Summarize(feature *ftproto.Feature) Flag {
+	f := Flag{
+		ID:            feature.GetId(),
+		Name:          feature.GetName(),
+		Description:   feature.GetDescription(),
+		Enabled:       feature.GetEnabled(),
+		Archived:      feature.GetArchived(),
+		Version:       feature.GetVersion(),
+		VariationType: feature.GetVariationType().String(),
+		Tags:          feature.GetTags(),
+		Maintainer:    feature.GetMaintainer(),
+		Variations:    make([]Variation, 0, len(feature.GetVariations())),
+		Targeting: Targeting{
+			Default: serve(feature, feature.GetDefaultStrategy()),
+		},
+	}
+	for _, v := range feature.GetVariations() {
+		f.Variations = append(f.Variations, Variation{ID: v.GetId(), Name: v.GetName(), Value: v.GetValue()})
+	}
+	if feature.GetOffVariation() != "" {
+		f.OffVariation = variationName(feature, feature.GetOffVariation())
+	}
+	for _, p := range feature.GetPrerequisites() {
+		f.Dependencies = append(f.Dependencies, Dependency{
+			FlagID:     p.GetFeatureId(),
+			Kind:       DependencyPrerequisite,
+			Variations: []string{p.GetVariationId()},
+		})
+	}
+	for _, t := range feature.GetTargets() {
+		if len(t.GetUsers()) == 0 {
+			continue
+		}
+		f.Targeting.Targets = append(f.Targeting.Targets, Target{
+			Variation: variationName(feature, t.GetVariation()),
+			Users:     len(t.GetUsers()),
+		})
+	}
+	for _, r := range feature.GetRules() {
+		rule := Rule{
+			ID:      r.GetId(),
+			Clauses: make([]string, 0, len(r.GetClauses())),
+			Serve:   serve(feature, r.GetStrategy()),
+		}
+		for _, c := range r.GetClauses() {
+			rule.Clauses = append(rule.Clauses, clause(c))
+			if c.GetOperator() == ftproto.Clause_FEATURE_FLAG {
+				f.Dependencies = append(f.Dependencies, Dependency{
+					FlagID:     c.GetAttribute(),
+					Kind:       DependencyRule,
+					Variations: c.GetValues(),
+				})
+			}
+		}
+		f.Targeting.Rules = append(f.Targeting.Rules, rule)
+	}
+	return f
+}
+
+// clause formats a rule clause, e.g. "plan IN pro, enterprise"
+func clause(c *ftproto.Clause) string {
+	switch c.GetOperator() {
+	case ftproto.Clause_SEGMENT:
+		return "segment IN " + strings.Join(c.GetValues(), ", ")
+	case ftproto.Clause_FEATURE_FLAG:
+		return fmt.Sprintf("flag %s IN %s", c.GetAttribute(), strings.Join(c.GetValues(), ", "))
+	}
+	return fmt.Sprintf("%s %s %s", c.GetAttribute(), c.GetOperator(), strings.Join(c.GetValues(), ", "))
+}
+
+// serve formats a strategy, e.g. "on" or "on 20%, off 80%"
+func serve(feature *ftproto.Feature, strategy *ftproto.Strategy) string {
+	if strategy == nil {
+		return ""
+	}
+	if strategy.GetType() == ftproto.Strategy_FIXED {
+		return variationName(feature, strategy.GetFixedStrategy().GetVariation())
+	}
+	rollout := strategy.GetRolloutStrategy()
+	parts := make([]string, 0, len(rollout.GetVariations()))
+	for _, v := range rollout.GetVariations() {
+		percentage := float64(v.GetWeight()) * 100 / rolloutWeightTotal
+		parts = append(parts, variationName(feature, v.GetVariation())+" "+
+			strconv.FormatFloat(percentage, 'f', -1, 64)+"%")
+	}
+	s := strings.Join(parts, ", ")
+	if audience := rollout.GetAudience(); audience != nil && audience.GetPercentage() > 0 {
+		s = fmt.Sprintf("%s to %d%% of the users, %s to the others",
+			s, audience.GetPercentage(), variationName(feature, audience.GetDefaultVariation()))
+	}
+	return s
+}
+
+// variationName returns the name of the variation, or its ID if it has no name
+func variationName(feature *ftproto.Feature, id string) string {
+	for _, v := range feature.GetVariations() {
+		if v.GetId() == id && v.GetName() != "" {
+			return v.GetName()
+		}
+	}
+	return id
+}
+
+// Inspector inspects the flags of a Source
+type Inspector struct {
+	source Source
+}
+
+// New creates an Inspector reading the flags of the source
+func New(source Source) *Inspector {
+	return &Inspector{source: source}
+}
+
+// FromSDK creates an Inspector reading the flags of the SDK's local cache, e.g. the dryrun.SDK given to
+// provider.NewProviderFromSDK or provider.WithDryRunSDK. It returns false if the SDK doesn't list its flags,
+// like the Bucketeer SDK, which doesn't expose its cache even when it evaluates the flags locally.
+func FromSDK(sdk any) (*Inspector, bool) {
+	source, ok := sdk.(Source)
+	if !ok {
+		return nil, false
+	}
+	return New(source), true
+}
+
+// Flags returns the summaries of the flags, sorted by ID
+func (i *Inspector) Flags() ([]Flag, error) {
+	features, err := i.source.Features()
+	if err != nil {
+		return nil, err
+	}
+	flags := make([]Flag, 0, len(features))
+	for _, feature := range features {
+		flags = append(flags, Summarize(feature))
+	}
+	slices.SortFunc(flags, func(a, b Flag) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return flags, nil
+}
+
+// Flag returns the summary of the flag, or ErrFlagNotFound
+func (i *Inspector) Flag(id string) (Flag, error) {
+	flags, err := i.Flags()
+	if err != nil {
+		return Flag{}, err
+	}
+	for _, f := range flags {
+		if f.ID == id {
+			return f, nil
+		}
+	}
+	return Flag{}, fmt.Errorf("%w: %s", ErrFlagNotFound, id)
+}
+
+// Dependents returns the IDs of the flags depending on the flag, directly or not, sorted by ID.
+// A flag can be deleted safely when it has no dependents.
+// It returns ErrFlagNotFound if the flag is neither in the cache nor depended on, e.g. a mistyped ID.
+func (i *Inspector) Dependents(id string) ([]string, error) {
+	flags, err := i.Flags()
+	if err != nil {
+		return nil, err
+	}
+	g := newGraph(flags)
+	if err := g.find(id); err != nil {
+		return nil, err
+	}
+	return g.reachable([]string{id}, g.dependents), nil
+}
+
+// Graph returns the dependency graph of the flags.
+// With ids, it returns the subgraph of these flags, of the flags they depend on, and of the flags depending on them.
+// It returns ErrFlagNotFound if a flag is neither in the cache nor depended on, e.g. a mistyped ID.
+func (i *Inspector) Graph(ids ...string) (*Graph, error) {
+	flags, err := i.Flags()
+	if err != nil {
+		return nil, err
+	}
+	g := newGraph(flags)
+	if len(ids) == 0 {
+		return g, nil
+	}
+	if err := g.find(ids...); err != nil {
+		return nil, err
+	}
+	keep := append(g.reachable(ids, g.dependencies), g.reachable(ids, g.dependents)...)
+	keep = append(keep, ids...)
+	return g.subgraph(keep), nil
+}
@@ -0,0 +1,267 @@
+package inspect
+
+import (
+	"errors"
+	"testing"
+
+	ftproto "github.com/bucketeer-io/bucketeer/v2/proto/feature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSource struct {
+	features []*ftproto.Feature
+	err      error
+}
+
+func (s fakeSource) Features() ([]*ftproto.Feature, error) {
+	return s.features, s.err
+}
+
+func boolVariations() []*ftproto.Variation {
+	return []*ftproto.Variation{
+		{Id: "variation-on", Name: "on", Value: "true"},
+		{Id: "variation-off", Name: "off", Value: "false"},
+	}
+}
+
+func fixed(variation string) *ftproto.Strategy {
+	return &ftproto.Strategy{
+		Type:          ftproto.Strategy_FIXED,
+		FixedStrategy: &ftproto.FixedStrategy{Variation: variation},
+	}
+}
+
+// testFeatures returns the flags:
+//
+//	checkout -> payments (prerequisite) -> platform (rule)
+//	banner -> removed (missing)
+//	search
+func testFeatures() []*ftproto.Feature {
+	return []*ftproto.Feature{
+		{
+			Id:              "search",
+			Name:            "Search",
+			Enabled:         true,
+			Variations:      boolVariations(),
+			DefaultStrategy: fixed("variation-off"),
+		},
+		{
+			Id:              "checkout",
+			Name:            "New checkout",
+			Description:     "Enables the new checkout flow",
+			Enabled:         true,
+			Version:         4,
+			VariationType:   ftproto.Feature_BOOLEAN,
+			Tags:            []string{"web"},
+			Maintainer:      "alice@example.com",
+			Variations:      boolVariations(),
+			OffVariation:    "variation-off",
+			Prerequisites:   []*ftproto.Prerequisite{{FeatureId: "payments", VariationId: "variation-on"}},
+			Targets:         []*ftproto.Target{{Variation: "variation-on", Users: []string{"user-1", "user-2"}}},
+			DefaultStrategy: fixed("variation-off"),
+			Rules: []*ftproto.Rule{{
+				Id: "rule-1",
+				Clauses: []*ftproto.Clause{
+					{Attribute: "plan", Operator: ftproto.Clause_IN, Values: []string{"pro", "enterprise"}},
+					{Operator: ftproto.Clause_SEGMENT, Values: []string{"beta-testers"}},
+				},
+				Strategy: &ftproto.Strategy{
+					Type: ftproto.Strategy_ROLLOUT,
+					RolloutStrategy: &ftproto.RolloutStrategy{
+						Variations: []*ftproto.RolloutStrategy_Variation{
+							{Variation: "variation-on", Weight: 20000},
+							{Variation: "variation-off", Weight: 80000},
+						},
+					},
+				},
+			}},
+		},
+		{
+			Id:         "payments",
+			Enabled:    true,
+			Variations: boolVariations(),
+			Rules: []*ftproto.Rule{{
+				Id: "rule-platform",
+				Clauses: []*ftproto.Clause{
+					{Attribute: "platform", Operator: ftproto.Clause_FEATURE_FLAG, Values: []string{"variation-on"}},
+				},
+				Strategy: fixed("variation-on"),
+			}},
+			DefaultStrategy: fixed("variation-off"),
+		},
+		{
+			Id:              "platform",
+			Enabled:         false,
+			Variations:      boolVariations(),
+			DefaultStrategy: fixed("variation-on"),
+		},
+		{
+			Id:              "banner",
+			Archived:        true,
+			Variations:      boolVariations(),
+			Prerequisites:   []*ftproto.Prerequisite{{FeatureId: "removed", VariationId: "variation-1"}},
+			DefaultStrategy: fixed("variation-on"),
+		},
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, Flag{
+		ID:            "checkout",
+		Name:          "New checkout",
+		Description:   "Enables the new checkout flow",
+		Enabled:       true,
+		Version:       4,
+		VariationType: "BOOLEAN",
+		Tags:          []string{"web"},
+		Maintainer:    "alice@example.com",
+		Variations: []Variation{
+			{ID: "variation-on", Name: "on", Value: "true"},
+			{ID: "variation-off", Name: "off", Value: "false"},
+		},
+		OffVariation: "off",
+		Dependencies: []Dependency{
+			{FlagID: "payments", Kind: DependencyPrerequisite, Variations: []string{"variation-on"}},
+		},
+		Targeting: Targeting{
+			Targets: []Target{{Variation: "on", Users: 2}},
+			Rules: []Rule{{
+				ID:      "rule-1",
+				Clauses: []string{"plan IN pro, enterprise", "segment IN beta-testers"},
+				Serve:   "on 20%, off 80%",
+			}},
+			Default: "off",
+		},
+	}, Summarize(testFeatures()[1]))
+}
+
+func TestServe(t *testing.T) {
+	t.Parallel()
+	feature := &ftproto.Feature{Variations: []*ftproto.Variation{
+		{Id: "variation-a", Name: "a"},
+		{Id: "variation-b"},
+	}}
+	tests := []struct {
+		desc     string
+		strategy *ftproto.Strategy
+		expected string
+	}{
+		{desc: "no strategy", expected: ""},
+		{desc: "fixed", strategy: fixed("variation-a"), expected: "a"},
+		{desc: "variation without name", strategy: fixed("variation-b"), expected: "variation-b"},
+		{
+			desc: "rollout with audience",
+			strategy: &ftproto.Strategy{
+				Type: ftproto.Strategy_ROLLOUT,
+				RolloutStrategy: &ftproto.RolloutStrategy{
+					Variations: []*ftproto.RolloutStrategy_Variation{
+						{Variation: "variation-a", Weight: 12500},
+						{Variation: "variation-b", Weight: 87500},
+					},
+					Audience: &ftproto.Audience{Percentage: 10, DefaultVariation: "variation-a"},
+				},
+			},
+			expected: "a 12.5%, variation-b 87.5% to 10% of the users, a to the others",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, test.expected, serve(feature, test.strategy))
+		})
+	}
+}
+
+func TestInspectorFlag(t *testing.T) {
+	t.Parallel()
+	i := New(fakeSource{features: testFeatures()})
+
+	flags, err := i.Flags()
+	require.NoError(t, err)
+	ids := make([]string, 0, len(flags))
+	for _, f := range flags {
+		ids = append(ids, f.ID)
+	}
+	assert.Equal(t, []string{"banner", "checkout", "payments", "platform", "search"}, ids)
+
+	payments, err := i.Flag("payments")
+	require.NoError(t, err)
+	assert.Equal(t, []Dependency{
+		{FlagID: "platform", Kind: DependencyRule, Variations: []string{"variation-on"}},
+	}, payments.Dependencies)
+	assert.Equal(t, []string{"flag platform IN variation-on"}, payments.Targeting.Rules[0].Clauses)
+
+	_, err = i.Flag("missing")
+	assert.ErrorIs(t, err, ErrFlagNotFound)
+}
+
+func TestFromSDK(t *testing.T) {
+	t.Parallel()
+	_, ok := FromSDK(struct{}{})
+	assert.False(t, ok)
+
+	inspector, ok := FromSDK(fakeSource{features: testFeatures()})
+	require.True(t, ok)
+	flag, err := inspector.Flag("checkout")
+	require.NoError(t, err)
+	assert.Equal(t, "checkout", flag.ID)
+}
+
+func TestInspectorSourceError(t *testing.T) {
+	t.Parallel()
+	errSource := errors.New("not ready")
+	i := New(fakeSource{err: errSource})
+	_, err := i.Flags()
+	assert.ErrorIs(t, err, errSource)
+	_, err = i.Flag("checkout")
+	assert.ErrorIs(t, err, errSource)
+	_, err = i.Dependents("checkout")
+	assert.ErrorIs(t, err, errSource)
+	_, err = i.Graph()
+	assert.ErrorIs(t, err, errSource)
+}
+
+func TestDependents(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		flag     string
+		expected []string
+	}{
+		{flag: "platform", expected: []string{"checkout", "payments"}},
+		{flag: "payments", expected: []string{"checkout"}},
+		{flag: "checkout", expected: []string{}},
+		{flag: "removed", expected: []string{"banner"}},
+	}
+
+	i := New(fakeSource{features: testFeatures()})
+	for _, test := range tests {
+		t.Run(test.flag, func(t *testing.T) {
+			t.Parallel()
+			dependents, err := i.Dependents(test.flag)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, dependents)
+		})
+	}
+}
+
+func TestDependentsNotFound(t *testing.T) {
+	t.Parallel()
+	// A mistyped flag must not look safe to delete
+	_, err := New(fakeSource{features: testFeatures()}).Dependents("paymnets")
+	assert.ErrorIs(t, err, ErrFlagNotFound)
+	assert.EqualError(t, err, "flag not found: paymnets")
+}
+
+func TestDependentsCycle(t *testing.T) {
+	t.Parallel()
+	i := New(fakeSource{features: []*ftproto.Feature{
+		{Id: "a", Prerequisites: []*ftproto.Prerequisite{{FeatureId: "b"}}},
+		{Id: "b", Prerequisites: []*ftproto.Prerequisite{{FeatureId: "a"}}},
+	}})
+	dependents, err := i.Dependents("a")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b"}, dependents)
+}
@@ -0,0 +1,93 @@
+// Package variation converts the variation values of Bucketeer flags like the Bucketeer SDK,
+// for the SDKs evaluating the flags themselves.
+package variation
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/model"
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/user"
+)
+
+// EvaluateFunc evaluates a flag for a valid user and returns the variation value as a string.
+// It returns false with the error reason in the details if the flag can't be evaluated.
+type EvaluateFunc func(u *user.User, featureID string) (model.BKTEvaluationDetails[string], bool)
+
+// Details evaluates the flag and parses the variation value, like the *VariationDetails functions
+// of the Bucketeer SDK. It returns defaultValue with the error reason if the user or the feature ID is missing,
+// the flag can't be evaluated, or the value can't be parsed.
+func Details[T model.EvaluationValue](
+	evaluate EvaluateFunc,
+	u *user.User,
+	featureID string,
+	defaultValue T,
+	parse func(value string) (T, error),
+) model.BKTEvaluationDetails[T] {
+	if err := u.Validate(); err != nil {
+		return failure(featureID, "", defaultValue, model.EvaluationReasonErrorUserIDNotSpecified)
+	}
+	if featureID == "" {
+		return failure(featureID, u.ID, defaultValue, model.EvaluationReasonErrorFeatureFlagIDNotSpecified)
+	}
+	details, ok := evaluate(u, featureID)
+	if !ok {
+		return failure(featureID, u.ID, defaultValue, details.Reason)
+	}
+	value, err := parse(details.VariationValue)
+	if err != nil {
+		return failure(featureID, u.ID, defaultValue, model.EvaluationReasonErrorWrongType)
+	}
+	return model.BKTEvaluationDetails[T]{
+		FeatureID:      details.FeatureID,
+		FeatureVersion: details.FeatureVersion,
+		UserID:         details.UserID,
+		VariationID:    details.VariationID,
+		VariationName:  details.VariationName,
+		VariationValue: value,
+		Reason:         details.Reason,
+	}
+}
+
+// failure returns the default value with the error reason, like the Bucketeer SDK
+func failure[T model.EvaluationValue](
+	featureID string,
+	userID string,
+	defaultValue T,
+	reason model.EvaluationReason,
+) model.BKTEvaluationDetails[T] {
+	return model.BKTEvaluationDetails[T]{
+		FeatureID:      featureID,
+		UserID:         userID,
+		VariationValue: defaultValue,
+		Reason:         reason,
+	}
+}
+
+// ParseBool parses a boolean variation.
+func ParseBool(value string) (bool, error) {
+	return strconv.ParseBool(value)
+}
+
+// ParseString returns a string variation as is.
+func ParseString(value string) (string, error) {
+	return value, nil
+}
+
+// ParseInt64 parses an integer variation. Like the Bucketeer SDK, a float value is truncated.
+func ParseInt64(value string) (int64, error) {
+	f, err := strconv.ParseFloat(value, 64)
+	return int64(f), err
+}
+
+// ParseFloat64 parses a float variation.
+func ParseFloat64(value string) (float64, error) {
+	return strconv.ParseFloat(value, 64)
+}
+
+// ParseObject parses a JSON variation.
+func ParseObject(value string) (interface{}, error) {
+	var v interface{}
+	err := json.Unmarshal([]byte(value), &v)
+	return v, err
+}
@@ -0,0 +1,131 @@
+package variation
+
+import (
+	"testing"
+
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/model"
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/user"
+	"github.com/stretchr/testify/assert"
+)
+
+// evaluateTo returns an EvaluateFunc serving the value, or failing with the reason if it's not empty
+func evaluateTo(value string, failureReason model.EvaluationReason) EvaluateFunc {
+	return func(u *user.User, featureID string) (model.BKTEvaluationDetails[string], bool) {
+		if failureReason != "" {
+			return model.BKTEvaluationDetails[string]{Reason: failureReason}, false
+		}
+		return model.BKTEvaluationDetails[string]{
+			FeatureID:      featureID,
+			FeatureVersion: 2,
+			UserID:         u.ID,
+			VariationID:    "variation-id",
+			VariationName:  "variation-name",
+			VariationValue: value,
+			Reason:         model.EvaluationReasonRule,
+		}, true
+	}
+}
+
+func TestDetails(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		desc      string
+		evaluate  EvaluateFunc
+		user      *user.User
+		featureID string
+		expected  model.BKTEvaluationDetails[int64]
+	}{
+		{
+			desc:      "evaluated",
+			evaluate:  evaluateTo("1.9", ""),
+			user:      user.NewUser("user-1", nil),
+			featureID: "flag",
+			expected: model.BKTEvaluationDetails[int64]{
+				FeatureID:      "flag",
+				FeatureVersion: 2,
+				UserID:         "user-1",
+				VariationID:    "variation-id",
+				VariationName:  "variation-name",
+				VariationValue: 1,
+				Reason:         model.EvaluationReasonRule,
+			},
+		},
+		{
+			desc:      "missing user",
+			evaluate:  evaluateTo("1", ""),
+			featureID: "flag",
+			expected: model.BKTEvaluationDetails[int64]{
+				FeatureID:      "flag",
+				VariationValue: 42,
+				Reason:         model.EvaluationReasonErrorUserIDNotSpecified,
+			},
+		},
+		{
+			desc:     "missing feature ID",
+			evaluate: evaluateTo("1", ""),
+			user:     user.NewUser("user-1", nil),
+			expected: model.BKTEvaluationDetails[int64]{
+				UserID:         "user-1",
+				VariationValue: 42,
+				Reason:         model.EvaluationReasonErrorFeatureFlagIDNotSpecified,
+			},
+		},
+		{
+			desc:      "evaluation failed",
+			evaluate:  evaluateTo("", model.EvaluationReasonErrorFlagNotFound),
+			user:      user.NewUser("user-1", nil),
+			featureID: "flag",
+			expected: model.BKTEvaluationDetails[int64]{
+				FeatureID:      "flag",
+				UserID:         "user-1",
+				VariationValue: 42,
+				Reason:         model.EvaluationReasonErrorFlagNotFound,
+			},
+		},
+		{
+			desc:      "wrong type",
+			evaluate:  evaluateTo("text", ""),
+			user:      user.NewUser("user-1", nil),
+			featureID: "flag",
+			expected: model.BKTEvaluationDetails[int64]{
+				FeatureID:      "flag",
+				UserID:         "user-1",
+				VariationValue: 42,
+				Reason:         model.EvaluationReasonErrorWrongType,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, test.expected, Details(test.evaluate, test.user, test.featureID, int64(42), ParseInt64))
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	b, err := ParseBool("true")
+	assert.NoError(t, err)
+	assert.True(t, b)
+
+	s, err := ParseString("text")
+	assert.NoError(t, err)
+	assert.Equal(t, "text", s)
+
+	i, err := ParseInt64("-1.9")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-1), i)
+
+	f, err := ParseFloat64("1.5")
+	assert.NoError(t, err)
+	assert.Equal(t, 1.5, f)
+
+	o, err := ParseObject(`{"a":[1,"b"]}`)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": []interface{}{float64(1), "b"}}, o)
+
+	_, err = ParseObject("{")
+	assert.Error(t, err)
+}
@@ -0,0 +1,70 @@
+package killswitch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	provider "github.com/bucketeer-io/openfeature-go-server-sdk/pkg"
+)
+
+// LoadFile reads the overrides of a JSON file mapping the flags to their value,
+// e.g. {"new-checkout": false, "banner": "maintenance"}.
+func LoadFile(path string) (map[string]string, error) {
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	values := make(map[string]string, len(raw))
+	for flag, r := range raw {
+		value, err := parseValue(r)
+		if err != nil {
+			return nil, fmt.Errorf("flag %q: %w", flag, err)
+		}
+		values[flag] = value
+	}
+	return values, nil
+}
+
+// WatchFile replaces the overrides with the overrides of the file, and reloads the file on SIGHUP until ctx is done.
+// It returns an error if the file can't be loaded the first time. Reload errors are passed to onError,
+// and the overrides are kept unchanged.
+//
+// Reloading replaces all the overrides, including the ones set with the Handler.
+func WatchFile(ctx context.Context, path string, overrides *provider.Overrides, onError func(error)) error {
+	values, err := LoadFile(path)
+	if err != nil {
+		return err
+	}
+	overrides.Replace(values)
+
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(reload)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-reload:
+				values, err := LoadFile(path)
+				if err != nil {
+					if onError != nil {
+						onError(err)
+					}
+					continue
+				}
+				overrides.Replace(values)
+			}
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,97 @@
+package killswitch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	provider "github.com/bucketeer-io/openfeature-go-server-sdk/pkg"
+)
+
+func TestLoadFile(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		desc        string
+		content     string
+		expected    map[string]string
+		expectedErr string
+	}{
+		{
+			desc:    "values",
+			content: `{"checkout": false, "banner": "maintenance", "limit": 3, "limits": {"max": 10}}`,
+			expected: map[string]string{
+				"checkout": "false",
+				"banner":   "maintenance",
+				"limit":    "3",
+				"limits":   `{"max":10}`,
+			},
+		},
+		{desc: "empty", content: `{}`, expected: map[string]string{}},
+		{desc: "not an object", content: `["checkout"]`, expectedErr: "failed to parse"},
+		{desc: "invalid JSON", content: `{"checkout": }`, expectedErr: "failed to parse"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), "overrides.json")
+			require.NoError(t, os.WriteFile(path, []byte(test.content), 0o600))
+			values, err := LoadFile(path)
+			if test.expectedErr != "" {
+				assert.ErrorContains(t, err, test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, values)
+		})
+	}
+}
+
+func TestLoadFileMissing(t *testing.T) {
+	t.Parallel()
+	_, err := LoadFile(filepath.Join(t.TempDir(), "missing.json"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+// TestWatchFile sends SIGHUP to the test process, so it must not run in parallel with other tests watching files
+func TestWatchFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overrides.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"checkout": false}`), 0o600))
+	overrides := provider.NewOverrides()
+	overrides.Set("banner", "maintenance")
+	errs := make(chan error, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, WatchFile(ctx, path, overrides, func(err error) { errs <- err }))
+	assert.Equal(t, map[string]string{"checkout": "false"}, overrides.All())
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"checkout": true}`), 0o600))
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	assert.Eventually(t, func() bool {
+		value, _ := overrides.Get("checkout")
+		return value == "true"
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"checkout": `), 0o600))
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	select {
+	case err := <-errs:
+		assert.ErrorContains(t, err, "failed to parse")
+	case <-time.After(time.Second):
+		t.Fatal("reload error not reported")
+	}
+	assert.Equal(t, map[string]string{"checkout": "true"}, overrides.All())
+}
+
+func TestWatchFileMissing(t *testing.T) {
+	t.Parallel()
+	err := WatchFile(context.Background(), filepath.Join(t.TempDir(), "missing.json"), provider.NewOverrides(), nil)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,131 @@
+// Package killswitch controls the overrides of the provider at runtime, to force flags to their safe value
+// during an incident, even if the Bucketeer console or API is unavailable.
+//
+// The overrides are set with an authenticated HTTP handler, or read from a JSON file reloaded on SIGHUP.
+// See provider.Overrides for how the overridden flags are evaluated.
+package killswitch
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	provider "github.com/bucketeer-io/openfeature-go-server-sdk/pkg"
+)
+
+const (
+	// maxRequestBodySize limits the size of an override value.
+	maxRequestBodySize = 1 << 20
+
+	overridesPath = "/overrides"
+	overridePath  = "/overrides/{flag}"
+)
+
+// Authorizer returns true if the request is allowed to read and change the overrides.
+type Authorizer func(r *http.Request) bool
+
+// BearerToken returns an Authorizer allowing the requests with the "Authorization: Bearer <token>" header.
+func BearerToken(token string) Authorizer {
+	return func(r *http.Request) bool {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		return ok && token != "" && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+	}
+}
+
+// Handler serves the overrides:
+//
+//	GET /overrides          lists the overrides as a JSON object
+//	PUT /overrides/{flag}   overrides the flag with the JSON value of the body, e.g. false
+//	DELETE /overrides/{flag} removes the override
+type Handler struct {
+	overrides *provider.Overrides
+	authorize Authorizer
+	mux       *http.ServeMux
+}
+
+// NewHandler creates a Handler changing the overrides.
+// Requests not allowed by authorize are rejected with 401, and all requests are rejected if authorize is nil.
+func NewHandler(overrides *provider.Overrides, authorize Authorizer) *Handler {
+	h := &Handler{
+		overrides: overrides,
+		authorize: authorize,
+		mux:       http.NewServeMux(),
+	}
+	h.mux.HandleFunc("GET "+overridesPath, h.list)
+	h.mux.HandleFunc("PUT "+overridePath, h.set)
+	h.mux.HandleFunc("DELETE "+overridePath, h.delete)
+	return h
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.authorize == nil || !h.authorize(r) {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) list(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.overrides.All())
+}
+
+func (h *Handler) set(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBodySize))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("failed to read request body: %v", err))
+		return
+	}
+	value, err := parseValue(body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	h.overrides.Set(r.PathValue("flag"), value)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) delete(w http.ResponseWriter, r *http.Request) {
+	h.overrides.Delete(r.PathValue("flag"))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseValue converts a JSON value to an override value:
+// a string is used as is, and other values are kept as compact JSON.
+func parseValue(raw []byte) (string, error) {
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return "", fmt.Errorf("invalid override value: %w", err)
+	}
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return "", fmt.Errorf("invalid override value: %w", err)
+	}
+	return buf.String(), nil
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, errorResponse{Error: msg})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
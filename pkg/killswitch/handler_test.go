@@ -0,0 +1,170 @@
+package killswitch
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	provider "github.com/bucketeer-io/openfeature-go-server-sdk/pkg"
+)
+
+const testToken = "secret"
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		desc             string
+		method           string
+		path             string
+		body             string
+		token            string
+		expectedStatus   int
+		expectedBody     string
+		expectedOverride map[string]string
+	}{
+		{
+			desc:             "list",
+			method:           http.MethodGet,
+			path:             "/overrides",
+			token:            testToken,
+			expectedStatus:   http.StatusOK,
+			expectedBody:     `{"banner":"maintenance"}`,
+			expectedOverride: map[string]string{"banner": "maintenance"},
+		},
+		{
+			desc:             "set a bool",
+			method:           http.MethodPut,
+			path:             "/overrides/checkout",
+			body:             "false",
+			token:            testToken,
+			expectedStatus:   http.StatusNoContent,
+			expectedOverride: map[string]string{"banner": "maintenance", "checkout": "false"},
+		},
+		{
+			desc:             "set a string",
+			method:           http.MethodPut,
+			path:             "/overrides/banner",
+			body:             `"off"`,
+			token:            testToken,
+			expectedStatus:   http.StatusNoContent,
+			expectedOverride: map[string]string{"banner": "off"},
+		},
+		{
+			desc:             "set an object",
+			method:           http.MethodPut,
+			path:             "/overrides/limits",
+			body:             `{ "max": 10 }`,
+			token:            testToken,
+			expectedStatus:   http.StatusNoContent,
+			expectedOverride: map[string]string{"banner": "maintenance", "limits": `{"max":10}`},
+		},
+		{
+			desc:             "invalid value",
+			method:           http.MethodPut,
+			path:             "/overrides/checkout",
+			body:             "flase",
+			token:            testToken,
+			expectedStatus:   http.StatusBadRequest,
+			expectedOverride: map[string]string{"banner": "maintenance"},
+		},
+		{
+			desc:             "delete",
+			method:           http.MethodDelete,
+			path:             "/overrides/banner",
+			token:            testToken,
+			expectedStatus:   http.StatusNoContent,
+			expectedOverride: map[string]string{},
+		},
+		{
+			desc:             "wrong token",
+			method:           http.MethodDelete,
+			path:             "/overrides/banner",
+			token:            "wrong",
+			expectedStatus:   http.StatusUnauthorized,
+			expectedBody:     `{"error":"unauthorized"}`,
+			expectedOverride: map[string]string{"banner": "maintenance"},
+		},
+		{
+			desc:             "no token",
+			method:           http.MethodGet,
+			path:             "/overrides",
+			expectedStatus:   http.StatusUnauthorized,
+			expectedOverride: map[string]string{"banner": "maintenance"},
+		},
+		{
+			desc:             "unknown path",
+			method:           http.MethodGet,
+			path:             "/flags",
+			token:            testToken,
+			expectedStatus:   http.StatusNotFound,
+			expectedOverride: map[string]string{"banner": "maintenance"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			overrides := provider.NewOverrides()
+			overrides.Set("banner", "maintenance")
+			handler := NewHandler(overrides, BearerToken(testToken))
+
+			req := httptest.NewRequest(test.method, test.path, strings.NewReader(test.body))
+			if test.token != "" {
+				req.Header.Set("Authorization", "Bearer "+test.token)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.expectedStatus, rec.Code)
+			if test.expectedBody != "" {
+				assert.JSONEq(t, test.expectedBody, rec.Body.String())
+			}
+			if rec.Code == http.StatusBadRequest {
+				var body errorResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+				assert.Contains(t, body.Error, "invalid override value")
+			}
+			assert.Equal(t, test.expectedOverride, overrides.All())
+		})
+	}
+}
+
+func TestHandlerWithoutAuthorizer(t *testing.T) {
+	t.Parallel()
+	handler := NewHandler(provider.NewOverrides(), nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/overrides", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestBearerToken(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		desc     string
+		token    string
+		header   string
+		expected bool
+	}{
+		{desc: "valid", token: "secret", header: "Bearer secret", expected: true},
+		{desc: "wrong token", token: "secret", header: "Bearer other"},
+		{desc: "wrong scheme", token: "secret", header: "Basic secret"},
+		{desc: "missing header", token: "secret"},
+		{desc: "empty token", token: "", header: "Bearer "},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest(http.MethodGet, "/overrides", nil)
+			if test.header != "" {
+				req.Header.Set("Authorization", test.header)
+			}
+			assert.Equal(t, test.expected, BearerToken(test.token)(req))
+		})
+	}
+}
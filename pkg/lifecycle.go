@@ -0,0 +1,156 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+var _ openfeature.ContextAwareStateHandler = (*Provider)(nil)
+
+var (
+	errProviderShutdown = errors.New("provider has been shut down")
+	errNilProvider      = errors.New("provider is nil, NewProvider may have failed")
+)
+
+// ShutdownError is returned by ShutdownWithContext when the SDK fails to close,
+// e.g. because the pending events could not be flushed before the deadline.
+// The Bucketeer SDK doesn't report how many events are still queued,
+// so the number of dropped events is unknown.
+type ShutdownError struct {
+	Err error
+}
+
+func (e *ShutdownError) Error() string {
+	return fmt.Sprintf("failed to shut down provider, pending events may be dropped: %v", e.Err)
+}
+
+func (e *ShutdownError) Unwrap() error {
+	return e.Err
+}
+
+// Init is called by the OpenFeature SDK when the provider is set.
+// The SDK is created with the provider, so there is nothing to initialize.
+func (p *Provider) Init(evalCtx openfeature.EvaluationContext) error {
+	return p.InitWithContext(context.Background(), evalCtx)
+}
+
+// InitWithContext is called by the OpenFeature SDK when the provider is set.
+// It returns an error if the provider has been shut down, because it can't be reused,
+// or if it's nil, e.g. when NewProvider failed.
+func (p *Provider) InitWithContext(ctx context.Context, evalCtx openfeature.EvaluationContext) error {
+	if p == nil {
+		return errNilProvider
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return errProviderShutdown
+	}
+	return nil
+}
+
+// Shutdown closes the SDK, waiting until the pending events are sent
+func (p *Provider) Shutdown() {
+	_ = p.ShutdownWithContext(context.Background())
+}
+
+// ShutdownWithContext closes the SDK, sending the pending events until ctx is done.
+//
+// Evaluations started after the shutdown return the default value with a PROVIDER_NOT_READY error.
+// It's safe to call ShutdownWithContext several times and concurrently:
+// the SDK is closed once, and every call returns the result of the shutdown,
+// or ctx.Err() if its ctx is done first. The shutdown goes on while a call is waiting for it,
+// and is canceled when the ctx of every waiting call is done.
+// A nil provider, e.g. when NewProvider failed, has nothing to shut down.
+func (p *Provider) ShutdownWithContext(ctx context.Context) error {
+	if p == nil {
+		return nil
+	}
+	p.shutdownOnce.Do(func() {
+		shutdownCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		p.shutdownDone = make(chan struct{})
+		p.cancelShutdown = cancel
+		go func() {
+			defer close(p.shutdownDone)
+			defer cancel()
+			p.shutdownErr = p.shutdown(shutdownCtx)
+		}()
+	})
+
+	p.mu.Lock()
+	p.shutdownWaiters++
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.shutdownWaiters--
+		if p.shutdownWaiters == 0 {
+			p.cancelShutdown()
+		}
+	}()
+
+	select {
+	case <-p.shutdownDone:
+		return p.shutdownErr
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *Provider) shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	p.closed = true
+	p.idle = make(chan struct{}, 1)
+	p.mu.Unlock()
+	if p.breaker != nil {
+		p.breaker.stop()
+	}
+
+	// Wait for the evaluations in progress, so their events are flushed
+	if p.inflight.Load() > 0 {
+		select {
+		case <-p.idle:
+		case <-ctx.Done():
+		}
+	}
+
+	if p.dryRunSDK != nil {
+		// The dry-run SDK has no events to flush
+		_ = p.dryRunSDK.Close(ctx)
+	}
+	if err := p.sdk.Close(ctx); err != nil {
+		return &ShutdownError{Err: err}
+	}
+	return nil
+}
+
+// acquire registers an evaluation in progress.
+// It returns false if the provider has been shut down.
+func (p *Provider) acquire() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return false
+	}
+	p.inflight.Add(1)
+	return true
+}
+
+// release unregisters an evaluation registered with acquire,
+// and signals the shutdown waiting for it if it's the last evaluation in progress
+func (p *Provider) release() {
+	if p.inflight.Add(-1) > 0 {
+		return
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		select {
+		case p.idle <- struct{}{}:
+		default:
+		}
+	}
+}
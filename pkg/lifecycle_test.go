@@ -0,0 +1,241 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	mockProvider "github.com/bucketeer-io/openfeature-go-server-sdk/test/mock/provider"
+)
+
+func TestShutdownWithContext(t *testing.T) {
+	t.Parallel()
+	closeErr := errors.New("ctx is canceled")
+	tests := []struct {
+		desc          string
+		sdk           func(mockSDK *mockProvider.MockBucketeerSDK) BucketeerSDK
+		expectedError error
+	}{
+		{
+			desc: "successful shutdown",
+			sdk: func(mockSDK *mockProvider.MockBucketeerSDK) BucketeerSDK {
+				mockSDK.EXPECT().Close(gomock.Any()).Return(nil).Times(1)
+				return mockSDK
+			},
+			expectedError: nil,
+		},
+		{
+			desc: "events not flushed",
+			sdk: func(mockSDK *mockProvider.MockBucketeerSDK) BucketeerSDK {
+				mockSDK.EXPECT().Close(gomock.Any()).Return(closeErr).Times(1)
+				return mockSDK
+			},
+			expectedError: &ShutdownError{Err: closeErr},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			provider := newTestProvider(test.sdk(mockProvider.NewMockBucketeerSDK(ctrl)))
+
+			err := provider.ShutdownWithContext(context.Background())
+			assert.Equal(t, test.expectedError, err)
+			if test.expectedError != nil {
+				assert.ErrorIs(t, err, closeErr)
+			}
+		})
+	}
+}
+
+func TestShutdownWithContextIdempotent(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSDK := mockProvider.NewMockBucketeerSDK(ctrl)
+	mockSDK.EXPECT().Close(gomock.Any()).Return(nil).Times(1)
+	provider := newTestProvider(mockSDK)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, provider.ShutdownWithContext(context.Background()))
+		}()
+	}
+	wg.Wait()
+	provider.Shutdown()
+}
+
+func TestShutdownWithContextDeadline(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	release := make(chan struct{})
+	mockSDK := mockProvider.NewMockBucketeerSDK(ctrl)
+	mockSDK.EXPECT().Close(gomock.Any()).DoAndReturn(func(ctx context.Context) error {
+		<-release
+		return nil
+	}).Times(1)
+	provider := newTestProvider(mockSDK)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, provider.ShutdownWithContext(ctx), context.DeadlineExceeded)
+
+	close(release)
+	assert.NoError(t, provider.ShutdownWithContext(context.Background()))
+}
+
+func TestShutdownWithContextLongerDeadline(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	release := make(chan struct{})
+	mockSDK := mockProvider.NewMockBucketeerSDK(ctrl)
+	mockSDK.EXPECT().Close(gomock.Any()).DoAndReturn(func(ctx context.Context) error {
+		select {
+		case <-release:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}).Times(1)
+	provider := newTestProvider(mockSDK)
+
+	// The first caller gives up while the second one is still waiting
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() { first <- provider.ShutdownWithContext(ctx) }()
+	second := make(chan error, 1)
+	go func() { second <- provider.ShutdownWithContext(context.Background()) }()
+	require.Eventually(t, func() bool {
+		provider.mu.RLock()
+		defer provider.mu.RUnlock()
+		return provider.shutdownWaiters == 2
+	}, time.Second, time.Millisecond)
+	cancel()
+	assert.ErrorIs(t, <-first, context.Canceled)
+
+	// The shutdown is not canceled until the second caller gives up
+	close(release)
+	assert.NoError(t, <-second)
+}
+
+func TestShutdownWithContextCanceled(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	closed := make(chan error, 1)
+	mockSDK := mockProvider.NewMockBucketeerSDK(ctrl)
+	mockSDK.EXPECT().Close(gomock.Any()).DoAndReturn(func(ctx context.Context) error {
+		<-ctx.Done()
+		closed <- ctx.Err()
+		return ctx.Err()
+	}).Times(1)
+	provider := newTestProvider(mockSDK)
+	// An evaluation in progress delays the shutdown
+	require.True(t, provider.acquire())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, provider.ShutdownWithContext(ctx), context.DeadlineExceeded)
+
+	// The shutdown is canceled when no caller waits for it anymore
+	assert.ErrorIs(t, <-closed, context.Canceled)
+	provider.release()
+	var shutdownErr *ShutdownError
+	assert.ErrorAs(t, provider.ShutdownWithContext(context.Background()), &shutdownErr)
+}
+
+func TestShutdownWaitsForEvaluations(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSDK := mockProvider.NewMockBucketeerSDK(ctrl)
+	mockSDK.EXPECT().Close(gomock.Any()).Return(nil).Times(1)
+	provider := newTestProvider(mockSDK)
+	require.True(t, provider.acquire())
+
+	done := make(chan error, 1)
+	go func() { done <- provider.ShutdownWithContext(context.Background()) }()
+	require.Eventually(t, func() bool {
+		provider.mu.RLock()
+		defer provider.mu.RUnlock()
+		return provider.closed
+	}, time.Second, time.Millisecond)
+	select {
+	case <-done:
+		t.Fatal("the shutdown must wait for the evaluation in progress")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	provider.release()
+	assert.NoError(t, <-done)
+}
+
+func TestEvaluationAfterShutdown(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSDK := mockProvider.NewMockBucketeerSDK(ctrl)
+	mockSDK.EXPECT().Close(gomock.Any()).Return(nil).Times(1)
+	// No variation expectation set because the SDK must not be called after the shutdown
+	provider := newTestProvider(mockSDK)
+	require.NoError(t, provider.ShutdownWithContext(context.Background()))
+
+	evalCtx := openfeature.FlattenedContext{openfeature.TargetingKey: "test-user"}
+	result := provider.BooleanEvaluation(context.Background(), "bool-flag", true, evalCtx)
+
+	assert.True(t, result.Value)
+	assert.Equal(t, openfeature.ErrorReason, result.Reason)
+	assert.Equal(t, openfeature.NewProviderNotReadyResolutionError("provider has been shut down"), result.ResolutionError)
+	assert.Error(t, provider.Init(openfeature.EvaluationContext{}))
+}
+
+func TestInit(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	provider := newTestProvider(mockProvider.NewMockBucketeerSDK(ctrl))
+	assert.NoError(t, provider.Init(openfeature.EvaluationContext{}))
+	assert.NoError(t, provider.InitWithContext(context.Background(), openfeature.EvaluationContext{}))
+}
+
+func TestInitNilProvider(t *testing.T) {
+	t.Parallel()
+	// A nil provider, e.g. returned with an error by NewProvider, fails to initialize instead of panicking
+	var provider *Provider
+	assert.ErrorIs(t, provider.Init(openfeature.EvaluationContext{}), errNilProvider)
+	assert.ErrorIs(t, provider.InitWithContext(context.Background(), openfeature.EvaluationContext{}), errNilProvider)
+	assert.NoError(t, provider.ShutdownWithContext(context.Background()))
+	provider.Shutdown()
+}
+
+func TestSetNilProvider(t *testing.T) {
+	t.Parallel()
+	var provider *Provider
+	err := openfeature.SetNamedProviderWithContextAndWait(context.Background(), t.Name(), provider)
+	assert.ErrorIs(t, err, errNilProvider)
+	t.Cleanup(func() {
+		_ = openfeature.SetNamedProviderWithContextAndWait(context.Background(), t.Name(), openfeature.NoopProvider{})
+	})
+}
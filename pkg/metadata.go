@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/model"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// Flag metadata keys set by the provider on evaluation results.
+const (
+	// FlagMetadataFeatureVersion is the version of the feature flag that served the variation.
+	FlagMetadataFeatureVersion = "featureVersion"
+	// FlagMetadataVariationID is the ID of the served variation.
+	FlagMetadataVariationID = "variationId"
+	// FlagMetadataBucketeerReason is the Bucketeer evaluation reason, e.g. TARGET, RULE or PREREQUISITE,
+	// which is more specific than the OpenFeature reason.
+	FlagMetadataBucketeerReason = "bucketeerReason"
+	// FlagMetadataAnonymous is true if the user ID was not the targeting key
+	// but came from WithTargetingKeyFallbacks or WithAnonymousID.
+	FlagMetadataAnonymous = "anonymous"
+	// FlagMetadataUserIDSource is the fallback key used as user ID, or UserIDSourceAnonymous.
+	FlagMetadataUserIDSource = "userIdSource"
+	// FlagMetadataTypeCoercion is the coercion applied to the variation value with TypePolicyLenient,
+	// e.g. TypeCoercionFloatToInt.
+	FlagMetadataTypeCoercion = "typeCoercion"
+	// FlagMetadataDryRun is true if the flag was evaluated by the dry-run SDK, without reporting events,
+	// see WithoutEvents.
+	FlagMetadataDryRun = "dryRun"
+	// FlagMetadataFeatureID is the Bucketeer feature ID evaluated for the flag key,
+	// set if it differs from the flag key, see WithFlagAliases.
+	FlagMetadataFeatureID = "featureId"
+	// FlagMetadataOverride is OverrideKillSwitch if the value was served by an override, see Provider.Overrides.
+	FlagMetadataOverride = "override"
+)
+
+// newFlagMetadata returns the flag metadata for an evaluation.
+// The feature version and the variation ID are only set if a variation was served,
+// and the user ID source if the user ID was not the targeting key.
+func newFlagMetadata[T model.EvaluationValue](
+	evaluation model.BKTEvaluationDetails[T],
+	userIDSource string,
+) openfeature.FlagMetadata {
+	metadata := openfeature.FlagMetadata{
+		FlagMetadataBucketeerReason: string(evaluation.Reason),
+	}
+	if evaluation.VariationID != "" {
+		metadata[FlagMetadataFeatureVersion] = int64(evaluation.FeatureVersion)
+		metadata[FlagMetadataVariationID] = evaluation.VariationID
+	}
+	if userIDSource != "" {
+		metadata[FlagMetadataAnonymous] = true
+		metadata[FlagMetadataUserIDSource] = userIDSource
+	}
+	return metadata
+}
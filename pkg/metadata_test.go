@@ -0,0 +1,79 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/model"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFlagMetadata(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		desc         string
+		evaluation   model.BKTEvaluationDetails[string]
+		userIDSource string
+		expected     openfeature.FlagMetadata
+	}{
+		{
+			desc: "variation served",
+			evaluation: model.BKTEvaluationDetails[string]{
+				FeatureID:      "string-flag",
+				FeatureVersion: 3,
+				UserID:         "test-user",
+				VariationID:    "variation-1",
+				VariationName:  "variation-name",
+				VariationValue: "value",
+				Reason:         model.EvaluationReasonRule,
+			},
+			expected: openfeature.FlagMetadata{
+				FlagMetadataFeatureVersion:  int64(3),
+				FlagMetadataVariationID:     "variation-1",
+				FlagMetadataBucketeerReason: "RULE",
+			},
+		},
+		{
+			desc: "no variation served",
+			evaluation: model.BKTEvaluationDetails[string]{
+				FeatureID:      "string-flag",
+				UserID:         "test-user",
+				VariationValue: "default",
+				Reason:         model.EvaluationReasonErrorFlagNotFound,
+			},
+			expected: openfeature.FlagMetadata{
+				FlagMetadataBucketeerReason: "ERROR_FLAG_NOT_FOUND",
+			},
+		},
+		{
+			desc: "anonymous user",
+			evaluation: model.BKTEvaluationDetails[string]{
+				FeatureID:      "string-flag",
+				FeatureVersion: 3,
+				UserID:         "session-1",
+				VariationID:    "variation-1",
+				VariationValue: "value",
+				Reason:         model.EvaluationReasonDefault,
+			},
+			userIDSource: "sessionId",
+			expected: openfeature.FlagMetadata{
+				FlagMetadataFeatureVersion:  int64(3),
+				FlagMetadataVariationID:     "variation-1",
+				FlagMetadataBucketeerReason: "DEFAULT",
+				FlagMetadataAnonymous:       true,
+				FlagMetadataUserIDSource:    "sessionId",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			metadata := newFlagMetadata(test.evaluation, test.userIDSource)
+			assert.Equal(t, test.expected, metadata)
+			reason, err := metadata.GetString(FlagMetadataBucketeerReason)
+			assert.NoError(t, err)
+			assert.Equal(t, string(test.evaluation.Reason), reason)
+		})
+	}
+}
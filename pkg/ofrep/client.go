@@ -0,0 +1,73 @@
+package ofrep
+
+import (
+	"context"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// clientEvaluator evaluates the flags with an OpenFeature client
+type clientEvaluator struct {
+	client openfeature.IClient
+}
+
+// NewClientEvaluator returns an Evaluator evaluating the flags with an OpenFeature client,
+// so the hooks of the client and of the API, e.g. a debug.Recorder, run for every OFREP evaluation.
+// The Bucketeer provider itself doesn't run hooks.
+func NewClientEvaluator(client openfeature.IClient) Evaluator {
+	return &clientEvaluator{client: client}
+}
+
+// StringEvaluation implements Evaluator.
+func (e *clientEvaluator) StringEvaluation(
+	ctx context.Context,
+	flag string,
+	defaultValue string,
+	evalCtx openfeature.FlattenedContext,
+) openfeature.StringResolutionDetail {
+	targetingKey, _ := evalCtx[openfeature.TargetingKey].(string)
+	attributes := make(map[string]any, len(evalCtx))
+	for key, value := range evalCtx {
+		if key != openfeature.TargetingKey {
+			attributes[key] = value
+		}
+	}
+
+	// The error is reported in the details
+	details, _ := e.client.StringValueDetails(
+		ctx, flag, defaultValue, openfeature.NewEvaluationContext(targetingKey, attributes),
+	)
+	return openfeature.StringResolutionDetail{
+		Value: details.Value,
+		ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+			ResolutionError: resolutionError(details.ErrorCode, details.ErrorMessage),
+			Reason:          details.Reason,
+			Variant:         details.Variant,
+			FlagMetadata:    details.FlagMetadata,
+		},
+	}
+}
+
+// resolutionError converts the error of an evaluation back to a ResolutionError
+func resolutionError(code openfeature.ErrorCode, message string) openfeature.ResolutionError {
+	switch code {
+	case "":
+		return openfeature.ResolutionError{}
+	case openfeature.ProviderNotReadyCode:
+		return openfeature.NewProviderNotReadyResolutionError(message)
+	case openfeature.FlagNotFoundCode:
+		return openfeature.NewFlagNotFoundResolutionError(message)
+	case openfeature.ParseErrorCode:
+		return openfeature.NewParseErrorResolutionError(message)
+	case openfeature.TypeMismatchCode:
+		return openfeature.NewTypeMismatchResolutionError(message)
+	case openfeature.TargetingKeyMissingCode:
+		return openfeature.NewTargetingKeyMissingResolutionError(message)
+	case openfeature.InvalidContextCode:
+		return openfeature.NewInvalidContextResolutionError(message)
+	case openfeature.ProviderFatalCode:
+		return openfeature.NewProviderFatalResolutionError(message)
+	default:
+		return openfeature.NewGeneralResolutionError(message)
+	}
+}
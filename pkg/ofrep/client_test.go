@@ -0,0 +1,95 @@
+package ofrep
+
+import (
+	"context"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClient returns the details for any evaluation and records the evaluation context
+type fakeClient struct {
+	openfeature.IClient
+	details openfeature.StringEvaluationDetails
+	evalCtx openfeature.EvaluationContext
+}
+
+func (c *fakeClient) StringValueDetails(
+	ctx context.Context,
+	flag string,
+	defaultValue string,
+	evalCtx openfeature.EvaluationContext,
+	options ...openfeature.Option,
+) (openfeature.StringEvaluationDetails, error) {
+	c.evalCtx = evalCtx
+	return c.details, nil
+}
+
+func TestClientEvaluator(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		desc     string
+		details  openfeature.StringEvaluationDetails
+		expected openfeature.StringResolutionDetail
+	}{
+		{
+			desc: "evaluated",
+			details: openfeature.StringEvaluationDetails{
+				Value: "on",
+				EvaluationDetails: openfeature.EvaluationDetails{
+					FlagKey: "flag",
+					ResolutionDetail: openfeature.ResolutionDetail{
+						Variant:      "variation-on",
+						Reason:       openfeature.TargetingMatchReason,
+						FlagMetadata: openfeature.FlagMetadata{"bucketeerReason": "RULE"},
+					},
+				},
+			},
+			expected: openfeature.StringResolutionDetail{
+				Value: "on",
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Variant:      "variation-on",
+					Reason:       openfeature.TargetingMatchReason,
+					FlagMetadata: openfeature.FlagMetadata{"bucketeerReason": "RULE"},
+				},
+			},
+		},
+		{
+			desc: "error",
+			details: openfeature.StringEvaluationDetails{
+				Value: "default",
+				EvaluationDetails: openfeature.EvaluationDetails{
+					FlagKey: "flag",
+					ResolutionDetail: openfeature.ResolutionDetail{
+						Reason:       openfeature.ErrorReason,
+						ErrorCode:    openfeature.FlagNotFoundCode,
+						ErrorMessage: "flag not found",
+					},
+				},
+			},
+			expected: openfeature.StringResolutionDetail{
+				Value: "default",
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: openfeature.NewFlagNotFoundResolutionError("flag not found"),
+					Reason:          openfeature.ErrorReason,
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			client := &fakeClient{details: test.details}
+			detail := NewClientEvaluator(client).StringEvaluation(
+				context.Background(),
+				"flag",
+				"default",
+				openfeature.FlattenedContext{openfeature.TargetingKey: "user-1", "country": "jp"},
+			)
+			assert.Equal(t, test.expected, detail)
+			assert.Equal(t, openfeature.NewEvaluationContext("user-1", map[string]any{"country": "jp"}), client.evalCtx)
+		})
+	}
+}
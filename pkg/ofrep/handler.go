@@ -0,0 +1,328 @@
+// Package ofrep implements the OpenFeature Remote Evaluation Protocol (OFREP)
+// on top of the Bucketeer provider.
+//
+// See https://github.com/open-feature/protocol for the protocol specification.
+package ofrep
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+const (
+	// maxRequestBodySize limits the size of an evaluation request body.
+	maxRequestBodySize = 1 << 20
+
+	evaluateFlagPath  = "/ofrep/v1/evaluate/flags/{key}"
+	evaluateFlagsPath = "/ofrep/v1/evaluate/flags"
+)
+
+// Evaluator evaluates a flag and returns the variation as a string.
+// *provider.Provider implements this interface.
+type Evaluator interface {
+	StringEvaluation(
+		ctx context.Context,
+		flag string,
+		defaultValue string,
+		evalCtx openfeature.FlattenedContext,
+	) openfeature.StringResolutionDetail
+}
+
+// FlagType is the variation type of a flag in Bucketeer.
+type FlagType string
+
+// Flag types, see WithFlagType.
+const (
+	TypeBoolean FlagType = "boolean"
+	TypeString  FlagType = "string"
+	TypeNumber  FlagType = "number"
+	TypeJSON    FlagType = "json"
+)
+
+// ParseFlagType parses a flag type: boolean, string, number or json.
+func ParseFlagType(s string) (FlagType, error) {
+	switch t := FlagType(s); t {
+	case TypeBoolean, TypeString, TypeNumber, TypeJSON:
+		return t, nil
+	}
+	return "", fmt.Errorf("unknown flag type %q, expected boolean, string, number or json", s)
+}
+
+// Option configures a Handler.
+type Option func(*Handler)
+
+// WithFlags sets the flags evaluated by the bulk evaluation endpoint.
+// The Bucketeer server SDK cannot list the flags of a tag, so the bulk
+// evaluation endpoint fails unless the flags are configured.
+func WithFlags(flags ...string) Option {
+	return func(h *Handler) {
+		h.flags = append(h.flags, flags...)
+	}
+}
+
+// WithFlagType sets the variation type of a flag, so its values are returned as JSON booleans,
+// numbers or objects. The Bucketeer server SDK doesn't expose the variation type of the flags,
+// so the values of the flags without a type are returned as strings, e.g. "true" for a boolean flag.
+func WithFlagType(flag string, flagType FlagType) Option {
+	return func(h *Handler) {
+		h.types[flag] = flagType
+	}
+}
+
+// Handler serves the OFREP single and bulk evaluation endpoints.
+type Handler struct {
+	evaluator Evaluator
+	flags     []string
+	types     map[string]FlagType
+	mux       *http.ServeMux
+}
+
+// NewHandler creates a new Handler evaluating flags with the given Evaluator.
+func NewHandler(evaluator Evaluator, opts ...Option) *Handler {
+	h := &Handler{
+		evaluator: evaluator,
+		types:     make(map[string]FlagType),
+		mux:       http.NewServeMux(),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.mux.HandleFunc("POST "+evaluateFlagPath, h.evaluateFlag)
+	h.mux.HandleFunc("POST "+evaluateFlagsPath, h.evaluateFlags)
+	return h
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+type evaluationRequest struct {
+	Context map[string]any `json:"context"`
+}
+
+type evaluationSuccess struct {
+	Key      string         `json:"key"`
+	Value    any            `json:"value"`
+	Reason   string         `json:"reason,omitempty"`
+	Variant  string         `json:"variant,omitempty"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+type evaluationFailure struct {
+	Key          string `json:"key,omitempty"`
+	ErrorCode    string `json:"errorCode,omitempty"`
+	ErrorDetails string `json:"errorDetails,omitempty"`
+}
+
+type bulkEvaluationSuccess struct {
+	Flags []any `json:"flags"`
+}
+
+func (h *Handler) evaluateFlag(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+	evalCtx, err := decodeContext(w, r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, evaluationFailure{
+			Key:          key,
+			ErrorCode:    string(openfeature.ParseErrorCode),
+			ErrorDetails: err.Error(),
+		})
+		return
+	}
+
+	success, failure := h.evaluate(r.Context(), key, evalCtx)
+	if failure != nil {
+		status := statusCode(openfeature.ErrorCode(failure.ErrorCode))
+		if status == http.StatusInternalServerError {
+			writeJSON(w, status, evaluationFailure{ErrorDetails: failure.ErrorDetails})
+			return
+		}
+		writeJSON(w, status, failure)
+		return
+	}
+	writeJSON(w, http.StatusOK, success)
+}
+
+func (h *Handler) evaluateFlags(w http.ResponseWriter, r *http.Request) {
+	evalCtx, err := decodeContext(w, r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, evaluationFailure{
+			ErrorCode:    string(openfeature.ParseErrorCode),
+			ErrorDetails: err.Error(),
+		})
+		return
+	}
+	if len(h.flags) == 0 {
+		writeJSON(w, http.StatusInternalServerError, evaluationFailure{
+			ErrorDetails: "no flags to evaluate, the flags of the bulk evaluation must be set with ofrep.WithFlags",
+		})
+		return
+	}
+
+	res := bulkEvaluationSuccess{Flags: make([]any, 0, len(h.flags))}
+	for _, key := range h.flags {
+		success, failure := h.evaluate(r.Context(), key, evalCtx)
+		if failure != nil {
+			// The whole request is invalid if the context can't be used for any flag
+			switch openfeature.ErrorCode(failure.ErrorCode) {
+			case openfeature.TargetingKeyMissingCode, openfeature.InvalidContextCode:
+				failure.Key = ""
+				writeJSON(w, http.StatusBadRequest, failure)
+				return
+			}
+			res.Flags = append(res.Flags, failure)
+			continue
+		}
+		res.Flags = append(res.Flags, success)
+	}
+
+	body, err := json.Marshal(res)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, evaluationFailure{ErrorDetails: err.Error()})
+		return
+	}
+	etag := newETag(body)
+	if r.Header.Get("If-None-Match") == etag {
+		w.Header().Set("ETag", etag)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("ETag", etag)
+	writeBody(w, http.StatusOK, body)
+}
+
+// evaluate evaluates the flag and converts the variation to the JSON type of the flag, see WithFlagType.
+func (h *Handler) evaluate(
+	ctx context.Context,
+	key string,
+	evalCtx openfeature.FlattenedContext,
+) (*evaluationSuccess, *evaluationFailure) {
+	detail := h.evaluator.StringEvaluation(ctx, key, "", evalCtx)
+	resolution := detail.ResolutionDetail()
+	if resolution.ErrorCode != "" {
+		return nil, &evaluationFailure{
+			Key:          key,
+			ErrorCode:    string(resolution.ErrorCode),
+			ErrorDetails: resolution.ErrorMessage,
+		}
+	}
+	value, err := decodeValue(detail.Value, h.types[key])
+	if err != nil {
+		return nil, &evaluationFailure{
+			Key:          key,
+			ErrorCode:    string(openfeature.TypeMismatchCode),
+			ErrorDetails: err.Error(),
+		}
+	}
+	return &evaluationSuccess{
+		Key:      key,
+		Value:    value,
+		Reason:   string(resolution.Reason),
+		Variant:  resolution.Variant,
+		Metadata: resolution.FlagMetadata,
+	}, nil
+}
+
+// decodeValue converts a Bucketeer variation to the JSON type of the flag.
+// The variations of the flags without a type are returned unchanged as strings.
+func decodeValue(variation string, flagType FlagType) (any, error) {
+	switch flagType {
+	case TypeBoolean:
+		b, err := strconv.ParseBool(variation)
+		if err != nil {
+			return nil, fmt.Errorf("variation %q is not a boolean", variation)
+		}
+		return b, nil
+	case TypeNumber:
+		// json.Number keeps the precision of large integers
+		if n, err := decodeJSON(variation); err == nil {
+			if _, ok := n.(json.Number); ok {
+				return n, nil
+			}
+		}
+		return nil, fmt.Errorf("variation %q is not a number", variation)
+	case TypeJSON:
+		value, err := decodeJSON(variation)
+		if err != nil {
+			return nil, fmt.Errorf("variation is not JSON: %w", err)
+		}
+		return value, nil
+	}
+	return variation, nil
+}
+
+// decodeJSON decodes a JSON value, with the numbers as json.Number
+func decodeJSON(s string) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(s)))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("trailing data")
+	}
+	return value, nil
+}
+
+func decodeContext(w http.ResponseWriter, r *http.Request) (openfeature.FlattenedContext, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBodySize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return openfeature.FlattenedContext{}, nil
+	}
+	var req evaluationRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, fmt.Errorf("failed to parse request body: %w", err)
+	}
+	return openfeature.FlattenedContext(req.Context), nil
+}
+
+// statusCode returns the HTTP status code for an OpenFeature error code.
+func statusCode(code openfeature.ErrorCode) int {
+	switch code {
+	case openfeature.FlagNotFoundCode:
+		return http.StatusNotFound
+	case openfeature.ParseErrorCode,
+		openfeature.TargetingKeyMissingCode,
+		openfeature.InvalidContextCode,
+		openfeature.TypeMismatchCode,
+		openfeature.GeneralCode:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func newETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeBody(w, status, body)
+}
+
+func writeBody(w http.ResponseWriter, status int, body []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,346 @@
+package ofrep
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeEvaluator struct {
+	details map[string]openfeature.StringResolutionDetail
+	evalCtx openfeature.FlattenedContext
+}
+
+func (f *fakeEvaluator) StringEvaluation(
+	ctx context.Context,
+	flag string,
+	defaultValue string,
+	evalCtx openfeature.FlattenedContext,
+) openfeature.StringResolutionDetail {
+	f.evalCtx = evalCtx
+	if _, ok := evalCtx[openfeature.TargetingKey]; !ok {
+		return openfeature.StringResolutionDetail{
+			Value: defaultValue,
+			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+				ResolutionError: openfeature.NewTargetingKeyMissingResolutionError("targeting key is missing"),
+				Reason:          openfeature.ErrorReason,
+			},
+		}
+	}
+	detail, ok := f.details[flag]
+	if !ok {
+		return openfeature.StringResolutionDetail{
+			Value: defaultValue,
+			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+				ResolutionError: openfeature.NewFlagNotFoundResolutionError("ERROR_FLAG_NOT_FOUND"),
+				Reason:          openfeature.ErrorReason,
+			},
+		}
+	}
+	return detail
+}
+
+func newFakeEvaluator() *fakeEvaluator {
+	return &fakeEvaluator{
+		details: map[string]openfeature.StringResolutionDetail{
+			"bool-flag": {
+				Value: "true",
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:       openfeature.TargetingMatchReason,
+					Variant:      "on",
+					FlagMetadata: openfeature.FlagMetadata{"featureVersion": int64(2)},
+				},
+			},
+			"true-string-flag": {
+				Value: "true",
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason: openfeature.DefaultReason,
+				},
+			},
+			"string-flag": {
+				Value: "value-1",
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason:  openfeature.DefaultReason,
+					Variant: "variation-1",
+				},
+			},
+			"int-flag": {
+				Value: "3000000000",
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason: openfeature.DefaultReason,
+				},
+			},
+			"object-flag": {
+				Value: `{"key":"value"}`,
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					Reason: openfeature.DefaultReason,
+				},
+			},
+			"exception-flag": {
+				ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+					ResolutionError: openfeature.NewProviderNotReadyResolutionError("provider has been shut down"),
+					Reason:          openfeature.ErrorReason,
+				},
+			},
+		},
+	}
+}
+
+// typeOptions declare the types of the flags of the fake evaluator
+func typeOptions() []Option {
+	return []Option{
+		WithFlagType("bool-flag", TypeBoolean),
+		WithFlagType("string-flag", TypeString),
+		WithFlagType("int-flag", TypeNumber),
+		WithFlagType("object-flag", TypeJSON),
+		WithFlagType("mismatch-flag", TypeNumber),
+	}
+}
+
+func TestEvaluateFlag(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		desc           string
+		flag           string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			desc:           "boolean flag",
+			flag:           "bool-flag",
+			body:           `{"context":{"targetingKey":"user-1"}}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"key":"bool-flag","value":true,"reason":"TARGETING_MATCH","variant":"on","metadata":{"featureVersion":2}}`,
+		},
+		{
+			desc:           "string flag",
+			flag:           "string-flag",
+			body:           `{"context":{"targetingKey":"user-1"}}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"key":"string-flag","value":"value-1","reason":"DEFAULT","variant":"variation-1"}`,
+		},
+		{
+			desc:           "int flag",
+			flag:           "int-flag",
+			body:           `{"context":{"targetingKey":"user-1"}}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"key":"int-flag","value":3000000000,"reason":"DEFAULT"}`,
+		},
+		{
+			desc:           "object flag",
+			flag:           "object-flag",
+			body:           `{"context":{"targetingKey":"user-1"}}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"key":"object-flag","value":{"key":"value"},"reason":"DEFAULT"}`,
+		},
+		{
+			desc:           "flag without type",
+			flag:           "true-string-flag",
+			body:           `{"context":{"targetingKey":"user-1"}}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"key":"true-string-flag","value":"true","reason":"DEFAULT"}`,
+		},
+		{
+			desc:           "type mismatch",
+			flag:           "mismatch-flag",
+			body:           `{"context":{"targetingKey":"user-1"}}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"key":"mismatch-flag","errorCode":"TYPE_MISMATCH","errorDetails":"variation \"value-1\" is not a number"}`,
+		},
+		{
+			desc:           "flag not found",
+			flag:           "missing-flag",
+			body:           `{"context":{"targetingKey":"user-1"}}`,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"key":"missing-flag","errorCode":"FLAG_NOT_FOUND","errorDetails":"ERROR_FLAG_NOT_FOUND"}`,
+		},
+		{
+			desc:           "targeting key missing",
+			flag:           "bool-flag",
+			body:           `{"context":{}}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"key":"bool-flag","errorCode":"TARGETING_KEY_MISSING","errorDetails":"targeting key is missing"}`,
+		},
+		{
+			desc:           "invalid body",
+			flag:           "bool-flag",
+			body:           `{"context":`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"key":"bool-flag","errorCode":"PARSE_ERROR","errorDetails":"failed to parse request body: unexpected end of JSON input"}`,
+		},
+		{
+			desc:           "provider not ready",
+			flag:           "exception-flag",
+			body:           `{"context":{"targetingKey":"user-1"}}`,
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"errorDetails":"provider has been shut down"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			evaluator := newFakeEvaluator()
+			evaluator.details["mismatch-flag"] = evaluator.details["string-flag"]
+			handler := NewHandler(evaluator, typeOptions()...)
+			req := httptest.NewRequest(
+				http.MethodPost,
+				"/ofrep/v1/evaluate/flags/"+test.flag,
+				strings.NewReader(test.body),
+			)
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.expectedStatus, rec.Code)
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+			assert.JSONEq(t, test.expectedBody, rec.Body.String())
+		})
+	}
+}
+
+func TestEvaluateFlagContext(t *testing.T) {
+	t.Parallel()
+	evaluator := newFakeEvaluator()
+	handler := NewHandler(evaluator)
+	req := httptest.NewRequest(
+		http.MethodPost,
+		"/ofrep/v1/evaluate/flags/bool-flag",
+		strings.NewReader(`{"context":{"targetingKey":"user-1","plan":"pro","age":30}}`),
+	)
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, openfeature.FlattenedContext{
+		openfeature.TargetingKey: "user-1",
+		"plan":                   "pro",
+		"age":                    float64(30),
+	}, evaluator.evalCtx)
+}
+
+func TestEvaluateFlags(t *testing.T) {
+	t.Parallel()
+	handler := NewHandler(
+		newFakeEvaluator(),
+		append(typeOptions(), WithFlags("bool-flag", "string-flag", "missing-flag"))...,
+	)
+
+	req := httptest.NewRequest(
+		http.MethodPost,
+		"/ofrep/v1/evaluate/flags",
+		strings.NewReader(`{"context":{"targetingKey":"user-1"}}`),
+	)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"flags":[
+		{"key":"bool-flag","value":true,"reason":"TARGETING_MATCH","variant":"on","metadata":{"featureVersion":2}},
+		{"key":"string-flag","value":"value-1","reason":"DEFAULT","variant":"variation-1"},
+		{"key":"missing-flag","errorCode":"FLAG_NOT_FOUND","errorDetails":"ERROR_FLAG_NOT_FOUND"}
+	]}`, rec.Body.String())
+	etag := rec.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	// The same evaluation with the ETag is not modified
+	req = httptest.NewRequest(
+		http.MethodPost,
+		"/ofrep/v1/evaluate/flags",
+		strings.NewReader(`{"context":{"targetingKey":"user-1"}}`),
+	)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.String())
+}
+
+func TestEvaluateFlagsInvalidContext(t *testing.T) {
+	t.Parallel()
+	handler := NewHandler(newFakeEvaluator(), WithFlags("bool-flag"))
+	req := httptest.NewRequest(
+		http.MethodPost,
+		"/ofrep/v1/evaluate/flags",
+		strings.NewReader(`{"context":{}}`),
+	)
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var res evaluationFailure
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.Equal(t, string(openfeature.TargetingKeyMissingCode), res.ErrorCode)
+}
+
+func TestEvaluateFlagsWithoutFlags(t *testing.T) {
+	t.Parallel()
+	handler := NewHandler(newFakeEvaluator())
+	req := httptest.NewRequest(
+		http.MethodPost,
+		"/ofrep/v1/evaluate/flags",
+		strings.NewReader(`{"context":{"targetingKey":"user-1"}}`),
+	)
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Contains(t, rec.Body.String(), "ofrep.WithFlags")
+}
+
+func TestDecodeValue(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		desc      string
+		variation string
+		flagType  FlagType
+		expected  any
+		err       string
+	}{
+		{desc: "boolean", variation: "false", flagType: TypeBoolean, expected: false},
+		{desc: "integer", variation: "-3000000000", flagType: TypeNumber, expected: json.Number("-3000000000")},
+		{desc: "float", variation: "2.1", flagType: TypeNumber, expected: json.Number("2.1")},
+		{desc: "object", variation: `{"a":1}`, flagType: TypeJSON, expected: map[string]any{"a": json.Number("1")}},
+		{desc: "array", variation: `[1,2]`, flagType: TypeJSON, expected: []any{json.Number("1"), json.Number("2")}},
+		{desc: "string", variation: "42", flagType: TypeString, expected: "42"},
+		{desc: "without type", variation: "true", expected: "true"},
+		{desc: "invalid boolean", variation: "yes", flagType: TypeBoolean, err: `variation "yes" is not a boolean`},
+		{desc: "invalid number", variation: "NaN", flagType: TypeNumber, err: `variation "NaN" is not a number`},
+		{desc: "quoted number", variation: `"1"`, flagType: TypeNumber, err: `is not a number`},
+		{desc: "trailing data", variation: "{} {}", flagType: TypeJSON, err: "variation is not JSON: trailing data"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			value, err := decodeValue(test.variation, test.flagType)
+			if test.err != "" {
+				assert.ErrorContains(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, value)
+		})
+	}
+}
+
+func TestParseFlagType(t *testing.T) {
+	t.Parallel()
+	flagType, err := ParseFlagType("number")
+	require.NoError(t, err)
+	assert.Equal(t, TypeNumber, flagType)
+	_, err = ParseFlagType("int")
+	assert.ErrorContains(t, err, `unknown flag type "int"`)
+}
@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/model"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// Option configures the Provider, as opposed to ProviderOptions which configure the Bucketeer SDK.
+type Option func(*Provider)
+
+// newProvider creates a Provider evaluating flags with the SDK
+func newProvider(sdk BucketeerSDK, opts ...Option) *Provider {
+	p := &Provider{
+		sdk:       sdk,
+		events:    make(chan openfeature.Event, eventBufferSize),
+		overrides: NewOverrides(),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.usage != nil {
+		p.usage.featureID = p.flagKeys.featureID
+	}
+	return p
+}
+
+// WithReasonMapping overrides the OpenFeature reasons returned for Bucketeer evaluation reasons.
+// Reasons missing from the mapping are converted as usual.
+//
+// For example, if the default strategy of your flags is a percentage rollout,
+// DEFAULT can be mapped to openfeature.SplitReason.
+func WithReasonMapping(mapping map[model.EvaluationReason]openfeature.Reason) Option {
+	return func(p *Provider) {
+		if p.reasonMapping == nil {
+			p.reasonMapping = make(map[model.EvaluationReason]openfeature.Reason, len(mapping))
+		}
+		for k, v := range mapping {
+			p.reasonMapping[k] = v
+		}
+	}
+}
+
+// WithErrorMapping overrides the OpenFeature error codes returned for Bucketeer evaluation reasons.
+// Reasons missing from the mapping are converted as usual.
+// An empty error code means the evaluation doesn't fail.
+func WithErrorMapping(mapping map[model.EvaluationReason]openfeature.ErrorCode) Option {
+	return func(p *Provider) {
+		if p.errorMapping == nil {
+			p.errorMapping = make(map[model.EvaluationReason]openfeature.ErrorCode, len(mapping))
+		}
+		for k, v := range mapping {
+			p.errorMapping[k] = v
+		}
+	}
+}
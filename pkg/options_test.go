@@ -0,0 +1,130 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/model"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	mockProvider "github.com/bucketeer-io/openfeature-go-server-sdk/test/mock/provider"
+)
+
+func TestWithReasonAndErrorMapping(t *testing.T) {
+	t.Parallel()
+	opts := []Option{
+		WithReasonMapping(map[model.EvaluationReason]openfeature.Reason{
+			model.EvaluationReasonDefault: openfeature.SplitReason,
+		}),
+		WithReasonMapping(map[model.EvaluationReason]openfeature.Reason{
+			model.EvaluationReasonOffVariation: openfeature.StaticReason,
+		}),
+		WithErrorMapping(map[model.EvaluationReason]openfeature.ErrorCode{
+			model.EvaluationReasonErrorNoEvaluations: openfeature.FlagNotFoundCode,
+			model.EvaluationReasonErrorWrongType:     "",
+		}),
+	}
+	tests := []struct {
+		desc                    string
+		bucketeerReason         model.EvaluationReason
+		expectedReason          openfeature.Reason
+		expectedResolutionError openfeature.ResolutionError
+	}{
+		{
+			desc:                    "mapped reason",
+			bucketeerReason:         model.EvaluationReasonDefault,
+			expectedReason:          openfeature.SplitReason,
+			expectedResolutionError: openfeature.ResolutionError{},
+		},
+		{
+			desc:                    "mapped reason from another option",
+			bucketeerReason:         model.EvaluationReasonOffVariation,
+			expectedReason:          openfeature.StaticReason,
+			expectedResolutionError: openfeature.ResolutionError{},
+		},
+		{
+			desc:                    "unmapped reason",
+			bucketeerReason:         model.EvaluationReasonRule,
+			expectedReason:          openfeature.TargetingMatchReason,
+			expectedResolutionError: openfeature.ResolutionError{},
+		},
+		{
+			desc:            "mapped error",
+			bucketeerReason: model.EvaluationReasonErrorNoEvaluations,
+			expectedReason:  openfeature.ErrorReason,
+			expectedResolutionError: openfeature.NewFlagNotFoundResolutionError(
+				string(model.EvaluationReasonErrorNoEvaluations),
+			),
+		},
+		{
+			desc:                    "error mapped to no error",
+			bucketeerReason:         model.EvaluationReasonErrorWrongType,
+			expectedReason:          openfeature.ErrorReason,
+			expectedResolutionError: openfeature.ResolutionError{},
+		},
+		{
+			desc:            "unmapped error",
+			bucketeerReason: model.EvaluationReasonErrorException,
+			expectedReason:  openfeature.ErrorReason,
+			expectedResolutionError: openfeature.NewGeneralResolutionError(
+				string(model.EvaluationReasonErrorException),
+			),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSDK := mockProvider.NewMockBucketeerSDK(ctrl)
+			mockSDK.EXPECT().
+				StringVariationDetails(gomock.Any(), gomock.Any(), "string-flag", "default").
+				Return(model.BKTEvaluationDetails[string]{
+					FeatureID:      "string-flag",
+					UserID:         "test-user",
+					VariationValue: "default",
+					Reason:         test.bucketeerReason,
+				}).
+				Times(1)
+			provider := newTestProvider(mockSDK, opts...)
+
+			result := provider.StringEvaluation(
+				context.Background(),
+				"string-flag",
+				"default",
+				openfeature.FlattenedContext{openfeature.TargetingKey: "test-user"},
+			)
+
+			assert.Equal(t, test.expectedReason, result.Reason)
+			assert.Equal(t, test.expectedResolutionError, result.ResolutionError)
+			assert.Equal(t, string(test.bucketeerReason), result.FlagMetadata[FlagMetadataBucketeerReason])
+		})
+	}
+}
+
+func TestNewResolutionError(t *testing.T) {
+	t.Parallel()
+	codes := []openfeature.ErrorCode{
+		openfeature.ProviderNotReadyCode,
+		openfeature.ProviderFatalCode,
+		openfeature.FlagNotFoundCode,
+		openfeature.ParseErrorCode,
+		openfeature.TypeMismatchCode,
+		openfeature.TargetingKeyMissingCode,
+		openfeature.InvalidContextCode,
+		openfeature.GeneralCode,
+	}
+	for _, code := range codes {
+		t.Run(string(code), func(t *testing.T) {
+			t.Parallel()
+			detail := openfeature.ProviderResolutionDetail{ResolutionError: newResolutionError(code, "message")}
+			assert.Equal(t, code, detail.ResolutionDetail().ErrorCode)
+			assert.Equal(t, "message", detail.ResolutionDetail().ErrorMessage)
+		})
+	}
+	assert.Equal(t, openfeature.ResolutionError{}, newResolutionError("", "message"))
+}
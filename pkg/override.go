@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"fmt"
+	"maps"
+	"sync"
+	"sync/atomic"
+
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/model"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// OverrideKillSwitch is the FlagMetadataOverride value of an evaluation served by an override
+const OverrideKillSwitch = "killswitch"
+
+// Overrides are flag values served instead of evaluating the flags, e.g. to force a flag to its safe value
+// during an incident, even if Bucketeer is unavailable.
+//
+// The values are written like Bucketeer variation values: "true" or "false" for booleans,
+// a number for integers and floats, any string for strings, and JSON for objects.
+// The flags are the flag keys evaluated by the code, before WithFlagAliases is applied.
+//
+// Overrides are safe for concurrent use. Reading them doesn't lock, so they don't slow the evaluations down.
+type Overrides struct {
+	// mu serializes the writes, which copy the values
+	mu     sync.Mutex
+	values atomic.Pointer[map[string]string]
+}
+
+// NewOverrides creates an empty override registry
+func NewOverrides() *Overrides {
+	o := &Overrides{}
+	o.values.Store(&map[string]string{})
+	return o
+}
+
+// Set overrides the flag with the value
+func (o *Overrides) Set(flag, value string) {
+	o.update(func(values map[string]string) {
+		values[flag] = value
+	})
+}
+
+// Delete removes the override of the flag, so the flag is evaluated again
+func (o *Overrides) Delete(flag string) {
+	o.update(func(values map[string]string) {
+		delete(values, flag)
+	})
+}
+
+// Replace replaces all the overrides with the values
+func (o *Overrides) Replace(values map[string]string) {
+	o.update(func(current map[string]string) {
+		clear(current)
+		maps.Copy(current, values)
+	})
+}
+
+// Get returns the value of the flag, and false if the flag is not overridden
+func (o *Overrides) Get(flag string) (string, bool) {
+	value, ok := (*o.values.Load())[flag]
+	return value, ok
+}
+
+// All returns a copy of the overrides
+func (o *Overrides) All() map[string]string {
+	return maps.Clone(*o.values.Load())
+}
+
+// update applies fn to a copy of the values, and stores the copy
+func (o *Overrides) update(fn func(values map[string]string)) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	values := maps.Clone(*o.values.Load())
+	fn(values)
+	o.values.Store(&values)
+}
+
+// WithOverrides sets the override registry of the provider, e.g. to share it between providers.
+// By default, each provider has its own registry, see Provider.Overrides.
+func WithOverrides(overrides *Overrides) Option {
+	return func(p *Provider) {
+		if overrides != nil {
+			p.overrides = overrides
+		}
+	}
+}
+
+// Overrides returns the override registry of the provider.
+//
+// An overridden flag is not evaluated: the override value is returned with the STATIC reason
+// and the FlagMetadataOverride flag metadata set to OverrideKillSwitch, and no event is reported to Bucketeer.
+// If the value can't be converted to the flag type, the default value is returned with a TYPE_MISMATCH error.
+func (p *Provider) Overrides() *Overrides {
+	return p.overrides
+}
+
+// overrideDetail returns the resolution detail of an overridden flag
+func overrideDetail[T model.EvaluationValue](
+	value string,
+	defaultValue T,
+	convert converter[T],
+) openfeature.GenericResolutionDetail[T] {
+	var v T
+	if convert == nil {
+		// Any value is a valid string
+		v, _ = any(value).(T)
+	} else {
+		var ok bool
+		if v, _, ok = convert(value, TypePolicySDK); !ok {
+			return errorDetail(defaultValue, openfeature.NewTypeMismatchResolutionError(
+				fmt.Sprintf("override value %q can't be converted to the flag type", value),
+			))
+		}
+	}
+	return openfeature.GenericResolutionDetail[T]{
+		Value: v,
+		ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+			Reason:       openfeature.StaticReason,
+			FlagMetadata: openfeature.FlagMetadata{FlagMetadataOverride: OverrideKillSwitch},
+		},
+	}
+}
@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	mockProvider "github.com/bucketeer-io/openfeature-go-server-sdk/test/mock/provider"
+)
+
+func TestOverrides(t *testing.T) {
+	t.Parallel()
+	o := NewOverrides()
+	_, ok := o.Get("flag")
+	assert.False(t, ok)
+
+	o.Set("flag", "false")
+	o.Set("other", "on")
+	value, ok := o.Get("flag")
+	assert.True(t, ok)
+	assert.Equal(t, "false", value)
+
+	all := o.All()
+	all["flag"] = "true"
+	assert.Equal(t, map[string]string{"flag": "false", "other": "on"}, o.All())
+
+	o.Delete("other")
+	assert.Equal(t, map[string]string{"flag": "false"}, o.All())
+
+	values := map[string]string{"new": "1"}
+	o.Replace(values)
+	values["new"] = "2"
+	assert.Equal(t, map[string]string{"new": "1"}, o.All())
+}
+
+func TestOverriddenEvaluation(t *testing.T) {
+	t.Parallel()
+	overridden := openfeature.FlagMetadata{FlagMetadataOverride: OverrideKillSwitch}
+	evalCtx := openfeature.FlattenedContext{openfeature.TargetingKey: "test-user"}
+
+	// The SDK must not be called
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	provider := newTestProvider(mockProvider.NewMockBucketeerSDK(ctrl))
+	provider.Overrides().Replace(map[string]string{
+		"bool":   "false",
+		"string": "maintenance",
+		"int":    "3",
+		"object": `{"limit":10}`,
+		"typo":   "flase",
+	})
+
+	t.Run("bool", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, openfeature.BoolResolutionDetail{
+			Value: false,
+			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+				Reason:       openfeature.StaticReason,
+				FlagMetadata: overridden,
+			},
+		}, provider.BooleanEvaluation(context.Background(), "bool", true, evalCtx))
+	})
+	t.Run("string", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, openfeature.StringResolutionDetail{
+			Value: "maintenance",
+			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+				Reason:       openfeature.StaticReason,
+				FlagMetadata: overridden,
+			},
+		}, provider.StringEvaluation(context.Background(), "string", "default", evalCtx))
+	})
+	t.Run("int", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, openfeature.IntResolutionDetail{
+			Value: 3,
+			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+				Reason:       openfeature.StaticReason,
+				FlagMetadata: overridden,
+			},
+		}, provider.IntEvaluation(context.Background(), "int", 1, evalCtx))
+	})
+	t.Run("object", func(t *testing.T) {
+		t.Parallel()
+		result := provider.ObjectEvaluation(context.Background(), "object", nil, evalCtx)
+		assert.Equal(t, map[string]interface{}{"limit": float64(10)}, result.Value)
+		assert.Equal(t, openfeature.StaticReason, result.Reason)
+		assert.Equal(t, overridden, result.FlagMetadata)
+	})
+	t.Run("type mismatch", func(t *testing.T) {
+		t.Parallel()
+		result := provider.BooleanEvaluation(context.Background(), "typo", true, evalCtx)
+		assert.True(t, result.Value)
+		assert.Equal(t, openfeature.ErrorReason, result.Reason)
+		assert.Equal(t, openfeature.NewTypeMismatchResolutionError(
+			`override value "flase" can't be converted to the flag type`,
+		), result.ResolutionError)
+	})
+}
+
+func TestWithOverrides(t *testing.T) {
+	t.Parallel()
+	overrides := NewOverrides()
+	p1 := newTestProvider(nil, WithOverrides(overrides))
+	p2 := newTestProvider(nil, WithOverrides(overrides))
+	p3 := newTestProvider(nil, WithOverrides(nil))
+
+	overrides.Set("flag", "true")
+	assert.Same(t, overrides, p1.Overrides())
+	assert.Same(t, overrides, p2.Overrides())
+	assert.NotNil(t, p3.Overrides())
+	assert.Empty(t, p3.Overrides().All())
+
+	result := p2.BooleanEvaluation(
+		context.Background(),
+		"flag",
+		false,
+		openfeature.FlattenedContext{openfeature.TargetingKey: "test-user"},
+	)
+	assert.True(t, result.Value)
+}
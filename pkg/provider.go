@@ -3,8 +3,11 @@ package provider
 //go:generate mockgen -source=$GOFILE -package=$GOPACKAGE -destination=../test/mock/$GOPACKAGE/$GOFILE
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
 
 	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer"
 	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/model"
@@ -51,34 +54,100 @@ type BucketeerSDK interface {
 	Close(ctx context.Context) error
 }
 
+// ExtendedSDK is the Bucketeer SDK with the functions not covered by OpenFeature, such as goal tracking.
+// bucketeer.SDK implements this interface, see Provider.Unwrap.
+type ExtendedSDK interface {
+	BucketeerSDK
+	Track(ctx context.Context, user *user.User, GoalID string)
+	TrackValue(ctx context.Context, user *user.User, GoalID string, value float64)
+}
+
 type ProviderOptions []bucketeer.Option
 
+var errNilSDK = errors.New("sdk must not be nil")
+
 // NewProvider creates a new Provider
 func NewProvider(
 	opts ProviderOptions,
+	providerOpts ...Option,
 ) (*Provider, error) {
-	return NewProviderWithContext(context.Background(), opts)
+	return NewProviderWithContext(context.Background(), opts, providerOpts...)
 }
 
 // NewProviderWithContext creates a new Provider with a context
 func NewProviderWithContext(
 	ctx context.Context,
 	opts ProviderOptions,
+	providerOpts ...Option,
 ) (*Provider, error) {
+	// The options are checked before creating the SDK, which would have to be closed
+	p := newProvider(nil, providerOpts...)
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
 	opts = append(opts, bucketeer.WithWrapperSDKVersion(version.SDKVersion))
 	opts = append(opts, bucketeer.WithWrapperSourceID(sourceIDOpenFeatureGo.Int32()))
 	sdk, err := bucketeer.NewSDK(ctx, opts...)
 	if err != nil {
 		return nil, err
 	}
-	return &Provider{
-		sdk: sdk,
-	}, nil
+	p.sdk = sdk
+	return p, nil
+}
+
+// NewProviderFromSDK creates a new Provider evaluating flags with an existing SDK,
+// e.g. a bucketeer.SDK shared with other code, a decorator adding caching or metrics, or a fake for tests.
+// The SDK is closed when the provider is shut down.
+// It returns an error if the SDK is nil, including a nil pointer such as a nil *dryrun.SDK.
+func NewProviderFromSDK(sdk BucketeerSDK, providerOpts ...Option) (*Provider, error) {
+	if isNil(sdk) {
+		return nil, errNilSDK
+	}
+	p := newProvider(sdk, providerOpts...)
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// isNil returns true if the SDK is nil or a nil pointer, which a nil check of the interface misses
+func isNil(sdk BucketeerSDK) bool {
+	if sdk == nil {
+		return true
+	}
+	v := reflect.ValueOf(sdk)
+	return v.Kind() == reflect.Ptr && v.IsNil()
 }
 
 // Provider implements the FeatureProvider interface and provides functions for evaluating flags
 type Provider struct {
-	sdk BucketeerSDK
+	sdk           BucketeerSDK
+	reasonMapping map[model.EvaluationReason]openfeature.Reason
+	errorMapping  map[model.EvaluationReason]openfeature.ErrorCode
+	contextConfig contextConfig
+	flagKeys      flagKeyConfig
+	typePolicy    TypePolicy
+	breaker       *circuitBreaker
+	cache         *evaluationCache
+	overrides     *Overrides
+	usage         *UsageTracker
+	events        chan openfeature.Event
+
+	// dryRunSDK evaluates the flags without reporting events, see WithoutEvents
+	dryRunSDK      BucketeerSDK
+	eventsDisabled bool
+
+	// mu guards closed, so that no evaluation starts after the shutdown begins, and the shutdown state
+	mu       sync.RWMutex
+	closed   bool
+	inflight atomic.Int64
+	// idle is signaled when the last evaluation in progress ends after the shutdown begins
+	idle            chan struct{}
+	shutdownOnce    sync.Once
+	shutdownDone    chan struct{}
+	shutdownErr     error
+	shutdownWaiters int
+	cancelShutdown  context.CancelFunc
 }
 
 // Metadata returns the metadata of the provider
@@ -103,30 +172,87 @@ func convertReason(reason model.EvaluationReason) openfeature.Reason {
 		model.EvaluationReasonErrorException,
 		model.EvaluationReasonErrorFlagNotFound,
 		model.EvaluationReasonErrorWrongType,
-		model.EvaluationReasonErrorUserIDNotSpecified:
+		model.EvaluationReasonErrorUserIDNotSpecified,
+		model.EvaluationReasonClient:
 		return openfeature.ErrorReason
 	default:
-		return openfeature.Reason(reason)
+		return openfeature.UnknownReason
 	}
 }
 
 // getEvaluationError returns a resolution error if the evaluation reason is an error
 func getEvaluationError(reason model.EvaluationReason) openfeature.ResolutionError {
+	return newResolutionError(getEvaluationErrorCode(reason), string(reason))
+}
+
+// getEvaluationErrorCode returns the error code of the evaluation reason,
+// or an empty code if the evaluation reason is not an error
+func getEvaluationErrorCode(reason model.EvaluationReason) openfeature.ErrorCode {
 	switch reason {
-	case model.EvaluationReasonErrorFlagNotFound:
-		return openfeature.NewFlagNotFoundResolutionError(string(reason))
+	case model.EvaluationReasonErrorFlagNotFound,
+		model.EvaluationReasonErrorFeatureFlagIDNotSpecified:
+		return openfeature.FlagNotFoundCode
 	case model.EvaluationReasonErrorWrongType:
-		return openfeature.NewTypeMismatchResolutionError(string(reason))
+		return openfeature.TypeMismatchCode
 	case model.EvaluationReasonErrorUserIDNotSpecified:
-		return openfeature.NewTargetingKeyMissingResolutionError(string(reason))
-	case model.EvaluationReasonErrorFeatureFlagIDNotSpecified,
-		model.EvaluationReasonErrorNoEvaluations,
-		model.EvaluationReasonErrorCacheNotFound,
-		model.EvaluationReasonErrorException:
-		return openfeature.NewGeneralResolutionError(string(reason))
+		return openfeature.TargetingKeyMissingCode
+	case model.EvaluationReasonErrorCacheNotFound:
+		return openfeature.ProviderNotReadyCode
+	case model.EvaluationReasonErrorNoEvaluations,
+		model.EvaluationReasonErrorException,
+		model.EvaluationReasonClient:
+		return openfeature.GeneralCode
 	default:
+		return ""
+	}
+}
+
+// newResolutionError returns a resolution error with the error code,
+// or an empty resolution error if the code is empty
+func newResolutionError(code openfeature.ErrorCode, msg string) openfeature.ResolutionError {
+	switch code {
+	case "":
 		return openfeature.ResolutionError{}
+	case openfeature.ProviderNotReadyCode:
+		return openfeature.NewProviderNotReadyResolutionError(msg)
+	case openfeature.ProviderFatalCode:
+		return openfeature.NewProviderFatalResolutionError(msg)
+	case openfeature.FlagNotFoundCode:
+		return openfeature.NewFlagNotFoundResolutionError(msg)
+	case openfeature.ParseErrorCode:
+		return openfeature.NewParseErrorResolutionError(msg)
+	case openfeature.TypeMismatchCode:
+		return openfeature.NewTypeMismatchResolutionError(msg)
+	case openfeature.TargetingKeyMissingCode:
+		return openfeature.NewTargetingKeyMissingResolutionError(msg)
+	case openfeature.InvalidContextCode:
+		return openfeature.NewInvalidContextResolutionError(msg)
+	default:
+		return openfeature.NewGeneralResolutionError(msg)
+	}
+}
+
+// convertReason converts the evaluation reason using the reason mapping of the provider
+func (p *Provider) convertReason(reason model.EvaluationReason) openfeature.Reason {
+	if r, ok := p.reasonMapping[reason]; ok {
+		return r
 	}
+	return convertReason(reason)
+}
+
+// getEvaluationError returns the resolution error of the evaluation reason
+// using the error mapping of the provider
+func (p *Provider) getEvaluationError(reason model.EvaluationReason) openfeature.ResolutionError {
+	return newResolutionError(p.getEvaluationErrorCode(reason), string(reason))
+}
+
+// getEvaluationErrorCode returns the error code of the evaluation reason using the error mapping of the provider,
+// or an empty code if the evaluation doesn't fail
+func (p *Provider) getEvaluationErrorCode(reason model.EvaluationReason) openfeature.ErrorCode {
+	if code, ok := p.errorMapping[reason]; ok {
+		return code
+	}
+	return getEvaluationErrorCode(reason)
 }
 
 // BooleanEvaluation returns a boolean flag evaluation result.
@@ -137,26 +263,7 @@ func (p *Provider) BooleanEvaluation(
 	defaultValue bool,
 	evalCtx openfeature.FlattenedContext,
 ) openfeature.BoolResolutionDetail {
-	bucketeerUser, err := toBucketeerUser(evalCtx)
-	if err != nil {
-		return openfeature.BoolResolutionDetail{
-			Value: defaultValue,
-			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-				ResolutionError: *err,
-				Reason:          openfeature.ErrorReason,
-			},
-		}
-	}
-
-	evaluation := p.sdk.BoolVariationDetails(ctx, ToPtr(bucketeerUser), flag, defaultValue)
-	return openfeature.BoolResolutionDetail{
-		Value: evaluation.VariationValue,
-		ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-			Reason:          convertReason(evaluation.Reason),
-			Variant:         evaluation.VariationName,
-			ResolutionError: getEvaluationError(evaluation.Reason),
-		},
-	}
+	return evaluate(ctx, p, flag, defaultValue, evalCtx, BucketeerSDK.BoolVariationDetails, convertBool)
 }
 
 // StringEvaluation returns a string flag evaluation result.
@@ -167,26 +274,7 @@ func (p *Provider) StringEvaluation(
 	defaultValue string,
 	evalCtx openfeature.FlattenedContext,
 ) openfeature.StringResolutionDetail {
-	bucketeerUser, err := toBucketeerUser(evalCtx)
-	if err != nil {
-		return openfeature.StringResolutionDetail{
-			Value: defaultValue,
-			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-				ResolutionError: *err,
-				Reason:          openfeature.ErrorReason,
-			},
-		}
-	}
-
-	evaluation := p.sdk.StringVariationDetails(ctx, ToPtr(bucketeerUser), flag, defaultValue)
-	return openfeature.StringResolutionDetail{
-		Value: evaluation.VariationValue,
-		ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-			Reason:          convertReason(evaluation.Reason),
-			Variant:         evaluation.VariationName,
-			ResolutionError: getEvaluationError(evaluation.Reason),
-		},
-	}
+	return evaluate(ctx, p, flag, defaultValue, evalCtx, BucketeerSDK.StringVariationDetails, nil)
 }
 
 // FloatEvaluation returns a float flag evaluation result.
@@ -197,26 +285,7 @@ func (p *Provider) FloatEvaluation(
 	defaultValue float64,
 	evalCtx openfeature.FlattenedContext,
 ) openfeature.FloatResolutionDetail {
-	bucketeerUser, err := toBucketeerUser(evalCtx)
-	if err != nil {
-		return openfeature.FloatResolutionDetail{
-			Value: defaultValue,
-			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-				ResolutionError: *err,
-				Reason:          openfeature.ErrorReason,
-			},
-		}
-	}
-
-	evaluation := p.sdk.Float64VariationDetails(ctx, ToPtr(bucketeerUser), flag, defaultValue)
-	return openfeature.FloatResolutionDetail{
-		Value: evaluation.VariationValue,
-		ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-			Reason:          convertReason(evaluation.Reason),
-			Variant:         evaluation.VariationName,
-			ResolutionError: getEvaluationError(evaluation.Reason),
-		},
-	}
+	return evaluate(ctx, p, flag, defaultValue, evalCtx, BucketeerSDK.Float64VariationDetails, convertFloat)
 }
 
 // IntEvaluation returns an int flag evaluation result.
@@ -227,26 +296,7 @@ func (p *Provider) IntEvaluation(
 	defaultValue int64,
 	evalCtx openfeature.FlattenedContext,
 ) openfeature.IntResolutionDetail {
-	bucketeerUser, err := toBucketeerUser(evalCtx)
-	if err != nil {
-		return openfeature.IntResolutionDetail{
-			Value: defaultValue,
-			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-				ResolutionError: *err,
-				Reason:          openfeature.ErrorReason,
-			},
-		}
-	}
-
-	evaluation := p.sdk.Int64VariationDetails(ctx, ToPtr(bucketeerUser), flag, defaultValue)
-	return openfeature.IntResolutionDetail{
-		Value: evaluation.VariationValue,
-		ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-			Reason:          convertReason(evaluation.Reason),
-			Variant:         evaluation.VariationName,
-			ResolutionError: getEvaluationError(evaluation.Reason),
-		},
-	}
+	return evaluate(ctx, p, flag, defaultValue, evalCtx, BucketeerSDK.Int64VariationDetails, convertInt)
 }
 
 // ObjectEvaluation returns an object flag evaluation result.
@@ -257,24 +307,127 @@ func (p *Provider) ObjectEvaluation(
 	defaultValue interface{},
 	evalCtx openfeature.FlattenedContext,
 ) openfeature.InterfaceResolutionDetail {
-	bucketeerUser, err := toBucketeerUser(evalCtx)
+	return evaluate(ctx, p, flag, defaultValue, evalCtx, BucketeerSDK.ObjectVariationDetails, convertObject)
+}
+
+// variationFunc evaluates a flag with the Bucketeer SDK
+type variationFunc[T model.EvaluationValue] func(
+	sdk BucketeerSDK,
+	ctx context.Context,
+	user *user.User,
+	featureID string,
+	defaultValue T,
+) model.BKTEvaluationDetails[T]
+
+// evaluate evaluates a flag using the variation function of the flag type,
+// or the converter if the type policy is not TypePolicySDK and the converter is not nil.
+// The SDK evaluates the Bucketeer feature ID the flag key is mapped to, see WithFlagAliases.
+// It returns defaultValue if an error occurs, or without calling the SDK if the circuit breaker is open.
+// With WithEvaluationCache, the cached evaluation is returned without calling the SDK.
+// An overridden flag is not evaluated, see Provider.Overrides.
+// With WithUsageTracking, the evaluations are counted by flag.
+// The flag is evaluated by the dry-run SDK if the events of the call must not be reported,
+// and these evaluations don't count for the circuit breaker, which protects the calls to Bucketeer.
+func evaluate[T model.EvaluationValue](
+	ctx context.Context,
+	p *Provider,
+	flag string,
+	defaultValue T,
+	evalCtx openfeature.FlattenedContext,
+	variation variationFunc[T],
+	convert converter[T],
+) (detail openfeature.GenericResolutionDetail[T]) {
+	if p.usage != nil {
+		defer func() { p.usage.record(flag, detail.ProviderResolutionDetail) }()
+	}
+	if !p.acquire() {
+		return errorDetail(defaultValue, openfeature.NewProviderNotReadyResolutionError(errProviderShutdown.Error()))
+	}
+	defer p.release()
+
+	if value, ok := p.overrides.Get(flag); ok {
+		return overrideDetail(value, defaultValue, convert)
+	}
+
+	bucketeerUser, userIDSource, err := toBucketeerUser(evalCtx, p.contextConfig)
 	if err != nil {
-		return openfeature.InterfaceResolutionDetail{
-			Value: defaultValue,
-			ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-				ResolutionError: *err,
-				Reason:          openfeature.ErrorReason,
-			},
+		return errorDetail(defaultValue, *err)
+	}
+
+	featureID := p.flagKeys.featureID(flag)
+	sdk, dryRun, sdkErr := p.sdkFor(ctx)
+	if sdkErr != nil {
+		return errorDetail(defaultValue, openfeature.NewGeneralResolutionError(sdkErr.Error()))
+	}
+	breaker := p.breaker
+	if dryRun {
+		breaker = nil
+	}
+	u := ToPtr(bucketeerUser)
+	call := func(ctx context.Context) (model.BKTEvaluationDetails[T], string, error) {
+		var trial bool
+		if breaker != nil {
+			var ok bool
+			if trial, ok = breaker.allow(); !ok {
+				return model.BKTEvaluationDetails[T]{}, "", errCircuitOpen
+			}
 		}
+		var (
+			evaluation model.BKTEvaluationDetails[T]
+			coercion   string
+		)
+		if convert != nil && p.typePolicy != TypePolicySDK {
+			evaluation, coercion = convertedVariation(ctx, sdk, u, featureID, defaultValue, p.typePolicy, convert)
+		} else {
+			evaluation = variation(sdk, ctx, u, featureID, defaultValue)
+		}
+		if breaker != nil {
+			breaker.record(trial, isBreakerFailure(evaluation.Reason))
+		}
+		return evaluation, coercion, nil
 	}
 
-	evaluation := p.sdk.ObjectVariationDetails(ctx, ToPtr(bucketeerUser), flag, defaultValue)
-	return openfeature.InterfaceResolutionDetail{
+	var (
+		evaluation model.BKTEvaluationDetails[T]
+		coercion   string
+		callErr    error
+	)
+	if p.cache != nil && !dryRun {
+		evaluation, coercion, callErr = cachedCall(ctx, p, featureID, u, defaultValue, call)
+	} else {
+		evaluation, coercion, callErr = call(ctx)
+	}
+	if callErr != nil {
+		return errorDetail(defaultValue, openfeature.NewGeneralResolutionError(callErr.Error()))
+	}
+	metadata := newFlagMetadata(evaluation, userIDSource)
+	if coercion != "" {
+		metadata[FlagMetadataTypeCoercion] = coercion
+	}
+	if dryRun {
+		metadata[FlagMetadataDryRun] = true
+	}
+	if featureID != flag {
+		metadata[FlagMetadataFeatureID] = featureID
+	}
+	return openfeature.GenericResolutionDetail[T]{
 		Value: evaluation.VariationValue,
 		ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
-			Reason:          convertReason(evaluation.Reason),
+			Reason:          p.convertReason(evaluation.Reason),
 			Variant:         evaluation.VariationName,
-			ResolutionError: getEvaluationError(evaluation.Reason),
+			ResolutionError: p.getEvaluationError(evaluation.Reason),
+			FlagMetadata:    metadata,
+		},
+	}
+}
+
+// errorDetail returns a resolution detail with the default value for the resolution error
+func errorDetail[T any](defaultValue T, err openfeature.ResolutionError) openfeature.GenericResolutionDetail[T] {
+	return openfeature.GenericResolutionDetail[T]{
+		Value: defaultValue,
+		ProviderResolutionDetail: openfeature.ProviderResolutionDetail{
+			ResolutionError: err,
+			Reason:          openfeature.ErrorReason,
 		},
 	}
 }
@@ -284,53 +437,55 @@ func (p *Provider) Hooks() []openfeature.Hook {
 	return []openfeature.Hook{}
 }
 
-func toBucketeerUser(evalCtx openfeature.FlattenedContext) (user.User, *openfeature.ResolutionError) {
+// toBucketeerUser converts the evaluation context to a Bucketeer user.
+// The user ID is the targeting key, or the attribute set with WithUserIDAttribute.
+// If it's missing, the fallback keys and the anonymous ID are tried,
+// and the returned source tells which one was used. The source is empty otherwise.
+// The other attributes are converted to strings, JSON-encoded if they aren't strings,
+// or flattened if nested objects are flattened with WithNestedContextFlattening.
+func toBucketeerUser(
+	evalCtx openfeature.FlattenedContext,
+	config contextConfig,
+) (user.User, string, *openfeature.ResolutionError) {
 	if len(evalCtx) == 0 {
-		return user.User{}, ToPtr(openfeature.NewTargetingKeyMissingResolutionError("evalCtx is empty"))
+		return user.User{}, "", ToPtr(openfeature.NewTargetingKeyMissingResolutionError("evalCtx is empty"))
 	}
 
-	_, exists := evalCtx[openfeature.TargetingKey]
-	if !exists {
-		return user.User{}, ToPtr(openfeature.NewTargetingKeyMissingResolutionError("targeting key is missing"))
+	idKey := openfeature.TargetingKey
+	if config.userIDAttribute != "" {
+		idKey = config.userIDAttribute
+	} else if val, exists := evalCtx[openfeature.TargetingKey]; exists {
+		if _, ok := val.(string); !ok {
+			return user.User{}, "",
+				ToPtr(openfeature.NewTargetingKeyMissingResolutionError(
+					fmt.Sprintf("key %q, value %v can not be converted to string", openfeature.TargetingKey, val),
+				),
+				)
+		}
 	}
 
-	bucketeerUser := user.User{
-		Data: make(map[string]string),
+	data, err := config.toUserData(evalCtx)
+	if err != nil {
+		return user.User{}, "", err
 	}
-	for key, val := range evalCtx {
-		switch key {
-		case openfeature.TargetingKey:
-			valStr, ok := val.(string)
-			if !ok {
-				return user.User{},
-					ToPtr(openfeature.NewTargetingKeyMissingResolutionError(
-						fmt.Sprintf("key %q, value %v can not be converted to string", key, val),
-					),
-					)
-			}
-			bucketeerUser.ID = valStr
-		default:
-			switch v := val.(type) {
-			case string:
-				bucketeerUser.Data[key] = v
-			default:
-				jsonBytes, err := json.Marshal(v)
-				if err != nil {
-					return user.User{}, ToPtr(openfeature.NewParseErrorResolutionError(
-						fmt.Sprintf("key %q, value %v cannot be converted to JSON string: %v", key, val, err),
-					))
-				}
-				bucketeerUser.Data[key] = string(jsonBytes)
-			}
+
+	id, source := config.resolveUserID(data, idKey)
+	if id == "" {
+		if idKey == openfeature.TargetingKey {
+			return user.User{}, "", ToPtr(openfeature.NewTargetingKeyMissingResolutionError("targeting key is missing"))
 		}
+		return user.User{}, "", ToPtr(openfeature.NewTargetingKeyMissingResolutionError(
+			fmt.Sprintf("user ID attribute %q is missing", idKey),
+		))
 	}
-
-	return bucketeerUser, nil
-}
-
-// Shutdown closes the SDK
-func (p *Provider) Shutdown() {
-	p.sdk.Close(context.Background())
+	// The targeting key is the ID, while a user ID attribute is kept in the data so flags can also target it
+	delete(data, openfeature.TargetingKey)
+	if idKey != openfeature.TargetingKey {
+		if targetingKey, ok := evalCtx[openfeature.TargetingKey].(string); ok {
+			data[openfeature.TargetingKey] = targetingKey
+		}
+	}
+	return user.User{ID: id, Data: data}, source, nil
 }
 
 func ToPtr[T any](v T) *T {
@@ -16,10 +16,8 @@ import (
 )
 
 // newTestProvider is a helper function that creates a Provider with a mock SDK for testing
-func newTestProvider(mockSDK BucketeerSDK) *Provider {
-	return &Provider{
-		sdk: mockSDK,
-	}
+func newTestProvider(mockSDK BucketeerSDK, opts ...Option) *Provider {
+	return newProvider(mockSDK, opts...)
 }
 
 func TestBooleanEvaluation(t *testing.T) {
@@ -659,7 +657,7 @@ func TestToBucketeerUser(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.desc, func(t *testing.T) {
 			t.Parallel()
-			bucketeerUser, err := toBucketeerUser(test.evalCtx)
+			bucketeerUser, _, err := toBucketeerUser(test.evalCtx, contextConfig{})
 			if test.expectedErr != nil {
 				assert.NotNil(t, err)
 				assert.Error(t, test.expectedErr, err)
@@ -763,10 +761,15 @@ func TestConvertReason(t *testing.T) {
 			bucketeerReason: model.EvaluationReasonErrorCacheNotFound,
 			expectedReason:  openfeature.ErrorReason,
 		},
+		{
+			desc:            "deprecated client reason",
+			bucketeerReason: model.EvaluationReasonClient,
+			expectedReason:  openfeature.ErrorReason,
+		},
 		{
 			desc:            "unknown reason",
-			bucketeerReason: model.EvaluationReason("UNKNOWN"),
-			expectedReason:  openfeature.Reason("UNKNOWN"),
+			bucketeerReason: model.EvaluationReason("NEW_REASON"),
+			expectedReason:  openfeature.UnknownReason,
 		},
 	}
 
@@ -819,7 +822,7 @@ func TestGetEvaluationError(t *testing.T) {
 		{
 			desc:                    "feature flag id not specified error",
 			evaluationReason:        model.EvaluationReasonErrorFeatureFlagIDNotSpecified,
-			expectedResolutionError: openfeature.NewGeneralResolutionError(string(model.EvaluationReasonErrorFeatureFlagIDNotSpecified)),
+			expectedResolutionError: openfeature.NewFlagNotFoundResolutionError(string(model.EvaluationReasonErrorFeatureFlagIDNotSpecified)),
 		},
 		{
 			desc:                    "no evaluations error",
@@ -829,13 +832,23 @@ func TestGetEvaluationError(t *testing.T) {
 		{
 			desc:                    "cache not found error",
 			evaluationReason:        model.EvaluationReasonErrorCacheNotFound,
-			expectedResolutionError: openfeature.NewGeneralResolutionError(string(model.EvaluationReasonErrorCacheNotFound)),
+			expectedResolutionError: openfeature.NewProviderNotReadyResolutionError(string(model.EvaluationReasonErrorCacheNotFound)),
 		},
 		{
 			desc:                    "exception error",
 			evaluationReason:        model.EvaluationReasonErrorException,
 			expectedResolutionError: openfeature.NewGeneralResolutionError(string(model.EvaluationReasonErrorException)),
 		},
+		{
+			desc:                    "deprecated client error",
+			evaluationReason:        model.EvaluationReasonClient,
+			expectedResolutionError: openfeature.NewGeneralResolutionError(string(model.EvaluationReasonClient)),
+		},
+		{
+			desc:                    "no error for unknown reason",
+			evaluationReason:        model.EvaluationReason("NEW_REASON"),
+			expectedResolutionError: openfeature.ResolutionError{},
+		},
 	}
 
 	for _, test := range tests {
@@ -856,8 +869,9 @@ func TestShutdown(t *testing.T) {
 		{
 			desc: "successful shutdown",
 			setupMock: func(mockSDK *mockProvider.MockBucketeerSDK) {
+				// The SDK is closed with a context canceled when no Shutdown call waits for it
 				mockSDK.EXPECT().
-					Close(context.Background()).
+					Close(gomock.Any()).
 					Return(nil).
 					Times(1)
 			},
@@ -865,8 +879,9 @@ func TestShutdown(t *testing.T) {
 		{
 			desc: "shutdown with error from SDK",
 			setupMock: func(mockSDK *mockProvider.MockBucketeerSDK) {
+				// The SDK is closed with a context canceled when no Shutdown call waits for it
 				mockSDK.EXPECT().
-					Close(context.Background()).
+					Close(gomock.Any()).
 					Return(errors.New("close error")).
 					Times(1)
 			},
@@ -890,3 +905,42 @@ func TestShutdown(t *testing.T) {
 		})
 	}
 }
+
+func TestNewProviderFromSDK(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	_, err := NewProviderFromSDK(nil)
+	assert.EqualError(t, err, "sdk must not be nil")
+	var nilSDK *mockProvider.MockBucketeerSDK
+	_, err = NewProviderFromSDK(nilSDK)
+	assert.EqualError(t, err, "sdk must not be nil")
+
+	mockSDK := mockProvider.NewMockBucketeerSDK(ctrl)
+	mockSDK.EXPECT().
+		BoolVariationDetails(gomock.Any(), gomock.Any(), "bool-flag", false).
+		Return(model.BKTEvaluationDetails[bool]{
+			FeatureID:      "bool-flag",
+			UserID:         "test-user",
+			VariationID:    "variation-1",
+			VariationValue: true,
+			Reason:         model.EvaluationReasonDefault,
+		}).
+		Times(1)
+	mockSDK.EXPECT().Close(gomock.Any()).Return(nil).Times(1)
+	provider, err := NewProviderFromSDK(mockSDK, WithReasonMapping(map[model.EvaluationReason]openfeature.Reason{
+		model.EvaluationReasonDefault: openfeature.SplitReason,
+	}))
+	assert.NoError(t, err)
+
+	result := provider.BooleanEvaluation(
+		context.Background(),
+		"bool-flag",
+		false,
+		openfeature.FlattenedContext{openfeature.TargetingKey: "test-user"},
+	)
+	assert.True(t, result.Value)
+	assert.Equal(t, openfeature.SplitReason, result.Reason)
+	assert.NoError(t, provider.ShutdownWithContext(context.Background()))
+}
@@ -0,0 +1,250 @@
+// Package replay records the evaluations of a Bucketeer SDK and replays them,
+// to reproduce in tests what users saw in production.
+//
+// A Recorder wraps the SDK used in production and writes the inputs and results of the evaluations
+// as JSON lines. The recording is read with ReadEntries, and an SDK created with NewSDK
+// serves the recorded results to a provider created with provider.NewProviderFromSDK.
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/model"
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/user"
+
+	provider "github.com/bucketeer-io/openfeature-go-server-sdk/pkg"
+)
+
+var _ provider.ExtendedSDK = (*Recorder)(nil)
+
+// Types of the recorded evaluations
+const (
+	TypeBool   = "bool"
+	TypeString = "string"
+	TypeInt    = "int"
+	TypeFloat  = "float"
+	TypeObject = "object"
+)
+
+// Entry is a recorded evaluation.
+// The values are kept as JSON, and decoded to the type of the evaluation when replayed.
+type Entry struct {
+	Timestamp    time.Time         `json:"timestamp"`
+	Type         string            `json:"type"`
+	FeatureID    string            `json:"featureId"`
+	UserID       string            `json:"userId"`
+	Attributes   map[string]string `json:"attributes,omitempty"`
+	DefaultValue json.RawMessage   `json:"defaultValue"`
+	Result       Result            `json:"result"`
+}
+
+// Result is the result of a recorded evaluation
+type Result struct {
+	FeatureVersion int32                  `json:"featureVersion,omitempty"`
+	VariationID    string                 `json:"variationId,omitempty"`
+	VariationName  string                 `json:"variationName,omitempty"`
+	Value          json.RawMessage        `json:"value"`
+	Reason         model.EvaluationReason `json:"reason"`
+}
+
+// Option configures a Recorder.
+type Option func(*Recorder)
+
+// WithFlags restricts the recording to the given flags.
+// All flags are recorded if no flag is set.
+func WithFlags(flags ...string) Option {
+	return func(r *Recorder) {
+		if r.flags == nil {
+			r.flags = make(map[string]struct{}, len(flags))
+		}
+		for _, f := range flags {
+			r.flags[f] = struct{}{}
+		}
+	}
+}
+
+// WithErrorHandler sets a function called when an entry can't be written.
+func WithErrorHandler(handler func(error)) Option {
+	return func(r *Recorder) {
+		r.errorHandler = handler
+	}
+}
+
+// Recorder is a BucketeerSDK recording the evaluations of another SDK.
+// The goal events are sent by the recorded SDK if it supports them, see provider.ExtendedSDK,
+// so a provider keeps tracking them while the evaluations are recorded.
+//
+// Entries are written synchronously, so recording slows the evaluations down;
+// restrict it to the flags being investigated with WithFlags.
+// The user attributes are recorded as is, so the recording must be handled like the users' data.
+type Recorder struct {
+	sdk          provider.BucketeerSDK
+	flags        map[string]struct{}
+	errorHandler func(error)
+	now          func() time.Time
+
+	mu      sync.Mutex
+	encoder *json.Encoder
+	closer  io.Closer
+}
+
+// NewRecorder creates a Recorder evaluating flags with sdk and writing the entries to w as JSON lines.
+// w is closed with the recorder if it implements io.Closer.
+func NewRecorder(sdk provider.BucketeerSDK, w io.Writer, opts ...Option) *Recorder {
+	r := &Recorder{
+		sdk:     sdk,
+		now:     time.Now,
+		encoder: json.NewEncoder(w),
+	}
+	if c, ok := w.(io.Closer); ok {
+		r.closer = c
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// BoolVariationDetails evaluates a boolean flag and records the evaluation
+func (r *Recorder) BoolVariationDetails(
+	ctx context.Context,
+	user *user.User,
+	featureID string,
+	defaultValue bool,
+) model.BKTEvaluationDetails[bool] {
+	return record(ctx, r, TypeBool, user, featureID, defaultValue, r.sdk.BoolVariationDetails)
+}
+
+// StringVariationDetails evaluates a string flag and records the evaluation
+func (r *Recorder) StringVariationDetails(
+	ctx context.Context,
+	user *user.User,
+	featureID string,
+	defaultValue string,
+) model.BKTEvaluationDetails[string] {
+	return record(ctx, r, TypeString, user, featureID, defaultValue, r.sdk.StringVariationDetails)
+}
+
+// Int64VariationDetails evaluates an integer flag and records the evaluation
+func (r *Recorder) Int64VariationDetails(
+	ctx context.Context,
+	user *user.User,
+	featureID string,
+	defaultValue int64,
+) model.BKTEvaluationDetails[int64] {
+	return record(ctx, r, TypeInt, user, featureID, defaultValue, r.sdk.Int64VariationDetails)
+}
+
+// Float64VariationDetails evaluates a float flag and records the evaluation
+func (r *Recorder) Float64VariationDetails(
+	ctx context.Context,
+	user *user.User,
+	featureID string,
+	defaultValue float64,
+) model.BKTEvaluationDetails[float64] {
+	return record(ctx, r, TypeFloat, user, featureID, defaultValue, r.sdk.Float64VariationDetails)
+}
+
+// ObjectVariationDetails evaluates a JSON flag and records the evaluation
+func (r *Recorder) ObjectVariationDetails(
+	ctx context.Context,
+	user *user.User,
+	featureID string,
+	defaultValue interface{},
+) model.BKTEvaluationDetails[interface{}] {
+	return record(ctx, r, TypeObject, user, featureID, defaultValue, r.sdk.ObjectVariationDetails)
+}
+
+// Track reports a goal event with the recorded SDK. It does nothing if the SDK doesn't support goal events.
+func (r *Recorder) Track(ctx context.Context, user *user.User, goalID string) {
+	if sdk, ok := r.sdk.(provider.ExtendedSDK); ok {
+		sdk.Track(ctx, user, goalID)
+	}
+}
+
+// TrackValue reports a goal event with a value with the recorded SDK.
+// It does nothing if the SDK doesn't support goal events.
+func (r *Recorder) TrackValue(ctx context.Context, user *user.User, goalID string, value float64) {
+	if sdk, ok := r.sdk.(provider.ExtendedSDK); ok {
+		sdk.TrackValue(ctx, user, goalID, value)
+	}
+}
+
+// Close closes the SDK, then the writer if it implements io.Closer
+func (r *Recorder) Close(ctx context.Context) error {
+	err := r.sdk.Close(ctx)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closer != nil {
+		if cerr := r.closer.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// record evaluates the flag with the variation function and writes the entry
+func record[T model.EvaluationValue](
+	ctx context.Context,
+	r *Recorder,
+	typ string,
+	u *user.User,
+	featureID string,
+	defaultValue T,
+	variation func(ctx context.Context, user *user.User, featureID string, defaultValue T) model.BKTEvaluationDetails[T],
+) model.BKTEvaluationDetails[T] {
+	evaluation := variation(ctx, u, featureID, defaultValue)
+	if r.flags != nil {
+		if _, ok := r.flags[featureID]; !ok {
+			return evaluation
+		}
+	}
+
+	entry := Entry{
+		Timestamp: r.now(),
+		Type:      typ,
+		FeatureID: featureID,
+		Result: Result{
+			FeatureVersion: evaluation.FeatureVersion,
+			VariationID:    evaluation.VariationID,
+			VariationName:  evaluation.VariationName,
+			Reason:         evaluation.Reason,
+		},
+	}
+	if u != nil {
+		entry.UserID = u.ID
+		entry.Attributes = u.Data
+	}
+	var err error
+	if entry.DefaultValue, err = json.Marshal(defaultValue); err == nil {
+		entry.Result.Value, err = json.Marshal(evaluation.VariationValue)
+	}
+	if err == nil {
+		r.mu.Lock()
+		err = r.encoder.Encode(entry)
+		r.mu.Unlock()
+	}
+	if err != nil && r.errorHandler != nil {
+		r.errorHandler(err)
+	}
+	return evaluation
+}
+
+// ReadEntries reads the entries written by a Recorder
+func ReadEntries(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	decoder := json.NewDecoder(r)
+	for {
+		var entry Entry
+		if err := decoder.Decode(&entry); err == io.EOF {
+			return entries, nil
+		} else if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+}
@@ -0,0 +1,194 @@
+package replay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/model"
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/user"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	provider "github.com/bucketeer-io/openfeature-go-server-sdk/pkg"
+	"github.com/bucketeer-io/openfeature-go-server-sdk/pkg/inmemory"
+	mockProvider "github.com/bucketeer-io/openfeature-go-server-sdk/test/mock/provider"
+)
+
+var timestamp = time.Date(2024, 1, 2, 10, 3, 0, 0, time.UTC)
+
+func newTestInMemorySDK() *inmemory.SDK {
+	return inmemory.New(map[string]inmemory.Flag{
+		"checkout": {
+			Version: 4,
+			Variations: []inmemory.Variation{
+				{ID: "variation-new", Name: "new", Value: "true"},
+				{ID: "variation-old", Name: "old", Value: "false"},
+			},
+			DefaultVariation: "variation-old",
+			Rules: []inmemory.Rule{
+				{Attribute: "plan", Values: []string{"pro"}, Variation: "variation-new"},
+			},
+		},
+		"limits": {
+			Version: 1,
+			Variations: []inmemory.Variation{
+				{ID: "variation-limits", Name: "limits", Value: `{"requests":100}`},
+			},
+			DefaultVariation: "variation-limits",
+		},
+	})
+}
+
+// closeBuffer is a buffer recording whether it has been closed
+type closeBuffer struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (b *closeBuffer) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+	sdk := newTestInMemorySDK()
+	var buf closeBuffer
+	r := NewRecorder(sdk, &buf)
+	r.now = func() time.Time { return timestamp }
+
+	evaluation := r.BoolVariationDetails(
+		context.Background(),
+		user.NewUser("user-x", map[string]string{"plan": "pro"}),
+		"checkout",
+		false,
+	)
+	assert.True(t, evaluation.VariationValue)
+	r.ObjectVariationDetails(context.Background(), user.NewUser("user-y", nil), "limits", nil)
+	r.Int64VariationDetails(context.Background(), user.NewUser("user-y", nil), "missing", 10)
+	require.NoError(t, r.Close(context.Background()))
+	assert.True(t, sdk.Closed())
+	assert.True(t, buf.closed)
+
+	entries, err := ReadEntries(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, []Entry{
+		{
+			Timestamp:    timestamp,
+			Type:         TypeBool,
+			FeatureID:    "checkout",
+			UserID:       "user-x",
+			Attributes:   map[string]string{"plan": "pro"},
+			DefaultValue: json.RawMessage(`false`),
+			Result: Result{
+				FeatureVersion: 4,
+				VariationID:    "variation-new",
+				VariationName:  "new",
+				Value:          json.RawMessage(`true`),
+				Reason:         model.EvaluationReasonRule,
+			},
+		},
+		{
+			Timestamp:    timestamp,
+			Type:         TypeObject,
+			FeatureID:    "limits",
+			UserID:       "user-y",
+			DefaultValue: json.RawMessage(`null`),
+			Result: Result{
+				FeatureVersion: 1,
+				VariationID:    "variation-limits",
+				VariationName:  "limits",
+				Value:          json.RawMessage(`{"requests":100}`),
+				Reason:         model.EvaluationReasonDefault,
+			},
+		},
+		{
+			Timestamp:    timestamp,
+			Type:         TypeInt,
+			FeatureID:    "missing",
+			UserID:       "user-y",
+			DefaultValue: json.RawMessage(`10`),
+			Result: Result{
+				Value:  json.RawMessage(`10`),
+				Reason: model.EvaluationReasonErrorFlagNotFound,
+			},
+		},
+	}, entries)
+}
+
+func TestRecorderTrack(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	sdk := mockProvider.NewMockExtendedSDK(ctrl)
+	sdk.EXPECT().
+		TrackValue(gomock.Any(), &user.User{ID: "user-x", Data: map[string]string{}}, "purchase", 9.99).
+		Times(1)
+	sdk.EXPECT().Track(gomock.Any(), gomock.Any(), "signup").Times(1)
+
+	// The goal events of the provider still reach the recorded SDK
+	p, err := provider.NewProviderFromSDK(NewRecorder(sdk, &bytes.Buffer{}))
+	require.NoError(t, err)
+	p.Track(
+		context.Background(),
+		"purchase",
+		openfeature.NewEvaluationContext("user-x", nil),
+		openfeature.NewTrackingEventDetails(9.99),
+	)
+	unwrapped, ok := p.Unwrap()
+	require.True(t, ok)
+	unwrapped.Track(context.Background(), user.NewUser("user-x", nil), "signup")
+
+	// An SDK without goal events is recorded, and its goal events are ignored
+	NewRecorder(newTestInMemorySDK(), &bytes.Buffer{}).Track(context.Background(), user.NewUser("user-x", nil), "signup")
+}
+
+func TestRecorderWithFlags(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	r := NewRecorder(newTestInMemorySDK(), &buf, WithFlags("limits"))
+
+	r.BoolVariationDetails(context.Background(), user.NewUser("user-x", nil), "checkout", false)
+	r.ObjectVariationDetails(context.Background(), user.NewUser("user-x", nil), "limits", nil)
+
+	entries, err := ReadEntries(&buf)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "limits", entries[0].FeatureID)
+}
+
+// failingWriter fails every write
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestRecorderWithErrorHandler(t *testing.T) {
+	t.Parallel()
+	var errs []error
+	r := NewRecorder(newTestInMemorySDK(), failingWriter{}, WithErrorHandler(func(err error) {
+		errs = append(errs, err)
+	}))
+
+	// The evaluation succeeds even if it can't be recorded
+	evaluation := r.BoolVariationDetails(
+		context.Background(),
+		user.NewUser("user-x", map[string]string{"plan": "pro"}),
+		"checkout",
+		false,
+	)
+	assert.True(t, evaluation.VariationValue)
+	assert.EqualError(t, errors.Join(errs...), "disk full")
+}
+
+func TestReadEntriesInvalid(t *testing.T) {
+	t.Parallel()
+	_, err := ReadEntries(bytes.NewBufferString("{\"type\":\"bool\"}\nnot json\n"))
+	assert.Error(t, err)
+}
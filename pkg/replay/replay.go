@@ -0,0 +1,220 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"reflect"
+	"sync"
+
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/model"
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/user"
+
+	provider "github.com/bucketeer-io/openfeature-go-server-sdk/pkg"
+)
+
+var _ provider.BucketeerSDK = (*SDK)(nil)
+
+// Matching is how evaluations are matched with the recorded entries
+type Matching int
+
+const (
+	// MatchStrict serves an entry if the type, the flag, the user ID, the attributes and the default value
+	// are the same as recorded. This is the default.
+	MatchStrict Matching = iota
+	// MatchLenient serves an entry if the type and the flag are the same as recorded,
+	// preferring the entries of the same user ID.
+	// It reproduces the results when the attributes or the code calling the SDK have changed since the recording.
+	MatchLenient
+)
+
+// Miss is an evaluation that didn't match any recorded entry
+type Miss struct {
+	Type      string
+	FeatureID string
+	UserID    string
+}
+
+// SDK is a BucketeerSDK serving recorded results. It's safe for concurrent use.
+//
+// When several entries match an evaluation, they are served in the recorded order,
+// and the last one is served again once they have all been served.
+// Evaluations matching no entry return the default value with the ERROR_EXCEPTION reason,
+// and are reported by Misses.
+type SDK struct {
+	entries  []Entry
+	matching Matching
+
+	mu     sync.Mutex
+	served map[int]bool
+	misses []Miss
+}
+
+// NewSDK creates an SDK serving the entries, e.g. read with ReadEntries
+func NewSDK(entries []Entry, matching Matching) *SDK {
+	return &SDK{
+		entries:  entries,
+		matching: matching,
+		served:   make(map[int]bool),
+	}
+}
+
+// Misses returns the evaluations that didn't match any recorded entry,
+// so tests can check that the recording covers them.
+func (s *SDK) Misses() []Miss {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Miss(nil), s.misses...)
+}
+
+// BoolVariationDetails returns the recorded result of a boolean flag
+func (s *SDK) BoolVariationDetails(
+	ctx context.Context,
+	user *user.User,
+	featureID string,
+	defaultValue bool,
+) model.BKTEvaluationDetails[bool] {
+	return replay(s, TypeBool, user, featureID, defaultValue)
+}
+
+// StringVariationDetails returns the recorded result of a string flag
+func (s *SDK) StringVariationDetails(
+	ctx context.Context,
+	user *user.User,
+	featureID string,
+	defaultValue string,
+) model.BKTEvaluationDetails[string] {
+	return replay(s, TypeString, user, featureID, defaultValue)
+}
+
+// Int64VariationDetails returns the recorded result of an integer flag
+func (s *SDK) Int64VariationDetails(
+	ctx context.Context,
+	user *user.User,
+	featureID string,
+	defaultValue int64,
+) model.BKTEvaluationDetails[int64] {
+	return replay(s, TypeInt, user, featureID, defaultValue)
+}
+
+// Float64VariationDetails returns the recorded result of a float flag
+func (s *SDK) Float64VariationDetails(
+	ctx context.Context,
+	user *user.User,
+	featureID string,
+	defaultValue float64,
+) model.BKTEvaluationDetails[float64] {
+	return replay(s, TypeFloat, user, featureID, defaultValue)
+}
+
+// ObjectVariationDetails returns the recorded result of a JSON flag
+func (s *SDK) ObjectVariationDetails(
+	ctx context.Context,
+	user *user.User,
+	featureID string,
+	defaultValue interface{},
+) model.BKTEvaluationDetails[interface{}] {
+	return replay(s, TypeObject, user, featureID, defaultValue)
+}
+
+// Close does nothing, since nothing is sent to Bucketeer
+func (s *SDK) Close(ctx context.Context) error {
+	return nil
+}
+
+// replay returns the result of the entry matching the evaluation, decoded to the evaluation type
+func replay[T model.EvaluationValue](
+	s *SDK,
+	typ string,
+	u *user.User,
+	featureID string,
+	defaultValue T,
+) model.BKTEvaluationDetails[T] {
+	userID := ""
+	var attributes map[string]string
+	if u != nil {
+		userID = u.ID
+		attributes = u.Data
+	}
+	evaluation := model.BKTEvaluationDetails[T]{
+		FeatureID:      featureID,
+		UserID:         userID,
+		VariationValue: defaultValue,
+		Reason:         model.EvaluationReasonErrorException,
+	}
+
+	entry, ok := s.match(typ, featureID, userID, attributes, defaultValue)
+	if !ok {
+		return evaluation
+	}
+	var value T
+	if err := json.Unmarshal(entry.Result.Value, &value); err != nil {
+		evaluation.Reason = model.EvaluationReasonErrorWrongType
+		return evaluation
+	}
+	return model.BKTEvaluationDetails[T]{
+		FeatureID:      featureID,
+		FeatureVersion: entry.Result.FeatureVersion,
+		UserID:         userID,
+		VariationID:    entry.Result.VariationID,
+		VariationName:  entry.Result.VariationName,
+		VariationValue: value,
+		Reason:         entry.Result.Reason,
+	}
+}
+
+// match returns the first entry matching the evaluation not served yet, or the last matching entry.
+// With MatchLenient, the entries of the same user are preferred to the entries of other users.
+func (s *SDK) match(
+	typ, featureID, userID string,
+	attributes map[string]string,
+	defaultValue any,
+) (Entry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	candidates := make([]int, 0)
+	others := make([]int, 0)
+	for i, entry := range s.entries {
+		if entry.Type != typ || entry.FeatureID != featureID {
+			continue
+		}
+		if entry.UserID != userID {
+			others = append(others, i)
+			continue
+		}
+		if s.matching == MatchStrict &&
+			(!maps.Equal(entry.Attributes, attributes) || !sameValue(entry.DefaultValue, defaultValue)) {
+			continue
+		}
+		candidates = append(candidates, i)
+	}
+	if len(candidates) == 0 && s.matching == MatchLenient {
+		candidates = others
+	}
+	if len(candidates) == 0 {
+		s.misses = append(s.misses, Miss{Type: typ, FeatureID: featureID, UserID: userID})
+		return Entry{}, false
+	}
+
+	for _, i := range candidates {
+		if !s.served[i] {
+			s.served[i] = true
+			return s.entries[i], true
+		}
+	}
+	return s.entries[candidates[len(candidates)-1]], true
+}
+
+// sameValue returns true if the recorded JSON value is the value
+func sameValue(recorded json.RawMessage, value any) bool {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+	var a, b any
+	if json.Unmarshal(recorded, &a) != nil || json.Unmarshal(encoded, &b) != nil {
+		return false
+	}
+	return reflect.DeepEqual(a, b)
+}
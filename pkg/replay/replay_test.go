@@ -0,0 +1,177 @@
+package replay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/model"
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/user"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	provider "github.com/bucketeer-io/openfeature-go-server-sdk/pkg"
+)
+
+func newEntry(userID string, attributes map[string]string, variationID, value string) Entry {
+	return Entry{
+		Type:         TypeBool,
+		FeatureID:    "checkout",
+		UserID:       userID,
+		Attributes:   attributes,
+		DefaultValue: json.RawMessage(`false`),
+		Result: Result{
+			FeatureVersion: 4,
+			VariationID:    variationID,
+			VariationName:  variationID,
+			Value:          json.RawMessage(value),
+			Reason:         model.EvaluationReasonRule,
+		},
+	}
+}
+
+func TestReplay(t *testing.T) {
+	t.Parallel()
+	entries := []Entry{
+		newEntry("user-x", map[string]string{"plan": "pro"}, "new", `true`),
+		newEntry("user-x", map[string]string{"plan": "pro"}, "old", `false`),
+		newEntry("user-z", nil, "new", `true`),
+	}
+	tests := []struct {
+		desc                string
+		matching            Matching
+		user                *user.User
+		defaultValue        bool
+		expectedVariations  []string
+		expectedReason      model.EvaluationReason
+		expectedMissesCount int
+	}{
+		{
+			desc:               "strict match served in order, then the last one again",
+			matching:           MatchStrict,
+			user:               user.NewUser("user-x", map[string]string{"plan": "pro"}),
+			expectedVariations: []string{"new", "old", "old"},
+			expectedReason:     model.EvaluationReasonRule,
+		},
+		{
+			desc:                "strict with other attributes",
+			matching:            MatchStrict,
+			user:                user.NewUser("user-x", map[string]string{"plan": "free"}),
+			expectedVariations:  []string{""},
+			expectedReason:      model.EvaluationReasonErrorException,
+			expectedMissesCount: 1,
+		},
+		{
+			desc:                "strict with other default value",
+			matching:            MatchStrict,
+			user:                user.NewUser("user-x", map[string]string{"plan": "pro"}),
+			defaultValue:        true,
+			expectedVariations:  []string{""},
+			expectedReason:      model.EvaluationReasonErrorException,
+			expectedMissesCount: 1,
+		},
+		{
+			desc:                "strict with other user",
+			matching:            MatchStrict,
+			user:                user.NewUser("user-y", nil),
+			expectedVariations:  []string{""},
+			expectedReason:      model.EvaluationReasonErrorException,
+			expectedMissesCount: 1,
+		},
+		{
+			desc:               "lenient with other attributes",
+			matching:           MatchLenient,
+			user:               user.NewUser("user-x", map[string]string{"plan": "free"}),
+			defaultValue:       true,
+			expectedVariations: []string{"new", "old"},
+			expectedReason:     model.EvaluationReasonRule,
+		},
+		{
+			desc:               "lenient with other user",
+			matching:           MatchLenient,
+			user:               user.NewUser("user-y", nil),
+			expectedVariations: []string{"new", "old", "new", "new"},
+			expectedReason:     model.EvaluationReasonRule,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			sdk := NewSDK(entries, test.matching)
+			for _, expected := range test.expectedVariations {
+				evaluation := sdk.BoolVariationDetails(context.Background(), test.user, "checkout", test.defaultValue)
+				assert.Equal(t, expected, evaluation.VariationID)
+				assert.Equal(t, test.expectedReason, evaluation.Reason)
+				assert.Equal(t, expected == "new" || (expected == "" && test.defaultValue), evaluation.VariationValue)
+			}
+			assert.Len(t, sdk.Misses(), test.expectedMissesCount)
+		})
+	}
+}
+
+func TestReplayTypes(t *testing.T) {
+	t.Parallel()
+	sdk := NewSDK([]Entry{
+		{
+			Type:         TypeInt,
+			FeatureID:    "limit",
+			UserID:       "user-x",
+			DefaultValue: json.RawMessage(`0`),
+			Result:       Result{VariationID: "v", Value: json.RawMessage(`9007199254740993`), Reason: "DEFAULT"},
+		},
+		{
+			Type:         TypeObject,
+			FeatureID:    "limits",
+			UserID:       "user-x",
+			DefaultValue: json.RawMessage(`null`),
+			Result:       Result{VariationID: "v", Value: json.RawMessage(`{"requests":100}`), Reason: "DEFAULT"},
+		},
+		{
+			Type:         TypeFloat,
+			FeatureID:    "ratio",
+			UserID:       "user-x",
+			DefaultValue: json.RawMessage(`0`),
+			Result:       Result{VariationID: "v", Value: json.RawMessage(`"text"`), Reason: "DEFAULT"},
+		},
+	}, MatchStrict)
+	u := user.NewUser("user-x", nil)
+
+	// Integers are decoded without losing precision
+	assert.Equal(t, int64(9007199254740993), sdk.Int64VariationDetails(context.Background(), u, "limit", 0).VariationValue)
+	assert.Equal(t,
+		map[string]interface{}{"requests": float64(100)},
+		sdk.ObjectVariationDetails(context.Background(), u, "limits", nil).VariationValue,
+	)
+	evaluation := sdk.Float64VariationDetails(context.Background(), u, "ratio", 0)
+	assert.Equal(t, model.EvaluationReasonErrorWrongType, evaluation.Reason)
+	// The entry of an integer flag doesn't match a string evaluation
+	assert.Equal(t,
+		model.EvaluationReasonErrorException,
+		sdk.StringVariationDetails(context.Background(), u, "limit", "").Reason,
+	)
+	assert.Equal(t, []Miss{{Type: TypeString, FeatureID: "limit", UserID: "user-x"}}, sdk.Misses())
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	t.Parallel()
+	evalCtx := openfeature.FlattenedContext{openfeature.TargetingKey: "user-x", "plan": "pro"}
+
+	// Record the evaluation in "production"
+	var buf bytes.Buffer
+	recorded, err := provider.NewProviderFromSDK(NewRecorder(newTestInMemorySDK(), &buf))
+	require.NoError(t, err)
+	expected := recorded.BooleanEvaluation(context.Background(), "checkout", false, evalCtx)
+
+	// Replay it in a test
+	entries, err := ReadEntries(&buf)
+	require.NoError(t, err)
+	sdk := NewSDK(entries, MatchStrict)
+	replayed, err := provider.NewProviderFromSDK(sdk)
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, replayed.BooleanEvaluation(context.Background(), "checkout", false, evalCtx))
+	assert.Empty(t, sdk.Misses())
+}
@@ -0,0 +1,502 @@
+// Package shadow compares the evaluations of two OpenFeature providers,
+// to migrate flags from another vendor to Bucketeer with evidence that both return the same values.
+//
+// A Provider returns the evaluations of the primary provider, and evaluates the same flags
+// with the shadow provider in the background. The mismatches are passed to a handler, e.g. LogMismatch,
+// and counted in Stats, so flags can be migrated one by one once they have no mismatch.
+package shadow
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+var (
+	_ openfeature.FeatureProvider          = (*Provider)(nil)
+	_ openfeature.ContextAwareStateHandler = (*Provider)(nil)
+	_ openfeature.EventHandler             = (*Provider)(nil)
+	_ openfeature.Tracker                  = (*Provider)(nil)
+)
+
+const defaultMaxConcurrency = 100
+
+// Result is the evaluation of a flag by one of the providers
+type Result struct {
+	Value     any    `json:"value"`
+	Variant   string `json:"variant,omitempty"`
+	Reason    string `json:"reason"`
+	ErrorCode string `json:"errorCode,omitempty"`
+}
+
+// Mismatch is an evaluation for which the providers returned different values,
+// or for which only one of the providers failed.
+type Mismatch struct {
+	Timestamp time.Time `json:"timestamp"`
+	Flag      string    `json:"flag"`
+	// ContextHash is the SHA-256 hash of the evaluation context as JSON, or its HMAC-SHA256 if a salt is set,
+	// to find the evaluations of the same context without logging the users' data.
+	ContextHash string `json:"contextHash"`
+	Primary     Result `json:"primary"`
+	Shadow      Result `json:"shadow"`
+}
+
+// Stats are the counters of the comparisons, e.g. to export as metrics
+type Stats struct {
+	// Compared is the number of evaluations compared
+	Compared uint64
+	// Mismatches is the number of compared evaluations with a mismatch
+	Mismatches uint64
+	// Dropped is the number of sampled evaluations not compared
+	// because WithMaxConcurrency shadow evaluations were already in progress
+	Dropped uint64
+}
+
+// Option configures a Provider.
+type Option func(*Provider)
+
+// WithSampleRate sets the fraction of the evaluations compared, from 0 to 1. Defaults to 1.
+func WithSampleRate(rate float64) Option {
+	return func(p *Provider) {
+		p.sampleRate = rate
+	}
+}
+
+// WithFlags restricts the comparison to the given flags, e.g. the flags being migrated.
+// All flags are compared if no flag is set.
+func WithFlags(flags ...string) Option {
+	return func(p *Provider) {
+		if p.flags == nil {
+			p.flags = make(map[string]struct{}, len(flags))
+		}
+		for _, f := range flags {
+			p.flags[f] = struct{}{}
+		}
+	}
+}
+
+// WithMismatchHandler sets a function called with each mismatch, from the goroutine of the shadow evaluation.
+func WithMismatchHandler(handler func(Mismatch)) Option {
+	return func(p *Provider) {
+		p.mismatchHandler = handler
+	}
+}
+
+// WithContextHashSalt hashes the evaluation contexts with HMAC-SHA256 using the salt,
+// so the hashes can't be reversed by hashing known contexts.
+func WithContextHashSalt(salt []byte) Option {
+	return func(p *Provider) {
+		p.salt = salt
+	}
+}
+
+// WithMaxConcurrency sets the maximum number of shadow evaluations in progress. Defaults to 100,
+// which is kept if n isn't positive.
+// Sampled evaluations are dropped when the limit is reached, so a slow shadow provider never slows the primary down.
+func WithMaxConcurrency(n int) Option {
+	return func(p *Provider) {
+		if n <= 0 {
+			n = defaultMaxConcurrency
+		}
+		p.maxConcurrency = n
+	}
+}
+
+// LogMismatch returns a mismatch handler logging the mismatches as warnings
+func LogMismatch(logger *slog.Logger) func(Mismatch) {
+	return func(m Mismatch) {
+		logger.Warn("shadow evaluation mismatch",
+			slog.String("flag", m.Flag),
+			slog.String("contextHash", m.ContextHash),
+			slog.Any("primaryValue", m.Primary.Value),
+			slog.String("primaryReason", m.Primary.Reason),
+			slog.String("primaryErrorCode", m.Primary.ErrorCode),
+			slog.Any("shadowValue", m.Shadow.Value),
+			slog.String("shadowReason", m.Shadow.Reason),
+			slog.String("shadowErrorCode", m.Shadow.ErrorCode),
+		)
+	}
+}
+
+// Provider is an OpenFeature provider returning the evaluations of the primary provider,
+// and comparing them with the evaluations of the shadow provider.
+//
+// The shadow evaluations use the context of the call without its cancellation, so they can outlive the call.
+// To keep the shadow evaluations out of the experiment analytics, create the Bucketeer provider
+// with provider.WithEventsDisabled and provider.WithDryRunSDK.
+type Provider struct {
+	primary         openfeature.FeatureProvider
+	shadow          openfeature.FeatureProvider
+	sampleRate      float64
+	flags           map[string]struct{}
+	mismatchHandler func(Mismatch)
+	salt            []byte
+	maxConcurrency  int
+	now             func() time.Time
+	sample          func() float64
+
+	// mu guards closed, so that no shadow evaluation starts after the shutdown begins,
+	// and the shadow evaluations in progress
+	mu       sync.Mutex
+	closed   bool
+	inflight int
+	// idle is closed when the last shadow evaluation in progress ends
+	idle chan struct{}
+
+	slots      chan struct{}
+	compared   atomic.Uint64
+	mismatches atomic.Uint64
+	dropped    atomic.Uint64
+}
+
+// NewProvider creates a Provider evaluating the flags with primary, and comparing them with shadow
+func NewProvider(primary, shadow openfeature.FeatureProvider, opts ...Option) *Provider {
+	p := &Provider{
+		primary:        primary,
+		shadow:         shadow,
+		sampleRate:     1,
+		maxConcurrency: defaultMaxConcurrency,
+		now:            time.Now,
+		//nolint:gosec // Sampling doesn't need a secure random number.
+		sample: rand.Float64,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	p.slots = make(chan struct{}, p.maxConcurrency)
+	return p
+}
+
+// Metadata returns the metadata of the primary provider
+func (p *Provider) Metadata() openfeature.Metadata {
+	return p.primary.Metadata()
+}
+
+// Hooks returns the hooks of the primary provider
+func (p *Provider) Hooks() []openfeature.Hook {
+	return p.primary.Hooks()
+}
+
+// BooleanEvaluation returns the evaluation of the primary provider
+func (p *Provider) BooleanEvaluation(
+	ctx context.Context,
+	flag string,
+	defaultValue bool,
+	evalCtx openfeature.FlattenedContext,
+) openfeature.BoolResolutionDetail {
+	detail := p.primary.BooleanEvaluation(ctx, flag, defaultValue, evalCtx)
+	p.compare(ctx, flag, evalCtx, detail.Value, detail.ProviderResolutionDetail,
+		func(ctx context.Context) (any, openfeature.ProviderResolutionDetail) {
+			d := p.shadow.BooleanEvaluation(ctx, flag, defaultValue, evalCtx)
+			return d.Value, d.ProviderResolutionDetail
+		},
+	)
+	return detail
+}
+
+// StringEvaluation returns the evaluation of the primary provider
+func (p *Provider) StringEvaluation(
+	ctx context.Context,
+	flag string,
+	defaultValue string,
+	evalCtx openfeature.FlattenedContext,
+) openfeature.StringResolutionDetail {
+	detail := p.primary.StringEvaluation(ctx, flag, defaultValue, evalCtx)
+	p.compare(ctx, flag, evalCtx, detail.Value, detail.ProviderResolutionDetail,
+		func(ctx context.Context) (any, openfeature.ProviderResolutionDetail) {
+			d := p.shadow.StringEvaluation(ctx, flag, defaultValue, evalCtx)
+			return d.Value, d.ProviderResolutionDetail
+		},
+	)
+	return detail
+}
+
+// FloatEvaluation returns the evaluation of the primary provider
+func (p *Provider) FloatEvaluation(
+	ctx context.Context,
+	flag string,
+	defaultValue float64,
+	evalCtx openfeature.FlattenedContext,
+) openfeature.FloatResolutionDetail {
+	detail := p.primary.FloatEvaluation(ctx, flag, defaultValue, evalCtx)
+	p.compare(ctx, flag, evalCtx, detail.Value, detail.ProviderResolutionDetail,
+		func(ctx context.Context) (any, openfeature.ProviderResolutionDetail) {
+			d := p.shadow.FloatEvaluation(ctx, flag, defaultValue, evalCtx)
+			return d.Value, d.ProviderResolutionDetail
+		},
+	)
+	return detail
+}
+
+// IntEvaluation returns the evaluation of the primary provider
+func (p *Provider) IntEvaluation(
+	ctx context.Context,
+	flag string,
+	defaultValue int64,
+	evalCtx openfeature.FlattenedContext,
+) openfeature.IntResolutionDetail {
+	detail := p.primary.IntEvaluation(ctx, flag, defaultValue, evalCtx)
+	p.compare(ctx, flag, evalCtx, detail.Value, detail.ProviderResolutionDetail,
+		func(ctx context.Context) (any, openfeature.ProviderResolutionDetail) {
+			d := p.shadow.IntEvaluation(ctx, flag, defaultValue, evalCtx)
+			return d.Value, d.ProviderResolutionDetail
+		},
+	)
+	return detail
+}
+
+// ObjectEvaluation returns the evaluation of the primary provider
+func (p *Provider) ObjectEvaluation(
+	ctx context.Context,
+	flag string,
+	defaultValue any,
+	evalCtx openfeature.FlattenedContext,
+) openfeature.InterfaceResolutionDetail {
+	detail := p.primary.ObjectEvaluation(ctx, flag, defaultValue, evalCtx)
+	p.compare(ctx, flag, evalCtx, detail.Value, detail.ProviderResolutionDetail,
+		func(ctx context.Context) (any, openfeature.ProviderResolutionDetail) {
+			d := p.shadow.ObjectEvaluation(ctx, flag, defaultValue, evalCtx)
+			return d.Value, d.ProviderResolutionDetail
+		},
+	)
+	return detail
+}
+
+// Track reports the tracking event to the primary provider, if it supports tracking
+func (p *Provider) Track(
+	ctx context.Context,
+	trackingEventName string,
+	evalCtx openfeature.EvaluationContext,
+	details openfeature.TrackingEventDetails,
+) {
+	if tracker, ok := p.primary.(openfeature.Tracker); ok {
+		tracker.Track(ctx, trackingEventName, evalCtx, details)
+	}
+}
+
+// EventChannel returns the events of the primary provider, e.g. when the Bucketeer provider's circuit breaker opens,
+// or nil if it doesn't emit events. The shadow provider's events are ignored since it doesn't serve the evaluations.
+func (p *Provider) EventChannel() <-chan openfeature.Event {
+	if handler, ok := p.primary.(openfeature.EventHandler); ok {
+		return handler.EventChannel()
+	}
+	return nil
+}
+
+// Init initializes both providers.
+// Only the error of the primary provider is returned, since the shadow provider doesn't serve the evaluations.
+func (p *Provider) Init(evalCtx openfeature.EvaluationContext) error {
+	return p.InitWithContext(context.Background(), evalCtx)
+}
+
+// InitWithContext initializes both providers with the context, if they support it.
+// Only the error of the primary provider is returned, since the shadow provider doesn't serve the evaluations.
+func (p *Provider) InitWithContext(ctx context.Context, evalCtx openfeature.EvaluationContext) error {
+	_ = initProvider(ctx, p.shadow, evalCtx)
+	return initProvider(ctx, p.primary, evalCtx)
+}
+
+// Shutdown waits for the shadow evaluations in progress, then shuts down both providers
+func (p *Provider) Shutdown() {
+	_ = p.ShutdownWithContext(context.Background())
+}
+
+// ShutdownWithContext stops the shadow evaluations and waits for the ones in progress until ctx is done,
+// then shuts down both providers, so their pending events are flushed even if the wait failed.
+// It returns the errors of the wait and of both providers, joined.
+func (p *Provider) ShutdownWithContext(ctx context.Context) error {
+	p.mu.Lock()
+	p.closed = true
+	p.mu.Unlock()
+	waitErr := p.Wait(ctx)
+	shadowErr := shutdownProvider(ctx, p.shadow)
+	primaryErr := shutdownProvider(ctx, p.primary)
+	return errors.Join(waitErr, primaryErr, shadowErr)
+}
+
+// Wait waits for the shadow evaluations in progress, or until ctx is done
+func (p *Provider) Wait(ctx context.Context) error {
+	p.mu.Lock()
+	idle := p.idle
+	p.mu.Unlock()
+	if idle == nil {
+		return nil
+	}
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// initProvider initializes the provider with the context if it supports it
+func initProvider(ctx context.Context, fp openfeature.FeatureProvider, evalCtx openfeature.EvaluationContext) error {
+	switch handler := fp.(type) {
+	case openfeature.ContextAwareStateHandler:
+		return handler.InitWithContext(ctx, evalCtx)
+	case openfeature.StateHandler:
+		return handler.Init(evalCtx)
+	}
+	return nil
+}
+
+// shutdownProvider shuts down the provider with the context if it supports it
+func shutdownProvider(ctx context.Context, fp openfeature.FeatureProvider) error {
+	switch handler := fp.(type) {
+	case openfeature.ContextAwareStateHandler:
+		return handler.ShutdownWithContext(ctx)
+	case openfeature.StateHandler:
+		handler.Shutdown()
+	}
+	return nil
+}
+
+// begin registers a shadow evaluation, and returns false if the provider is shut down
+func (p *Provider) begin() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return false
+	}
+	if p.inflight == 0 {
+		p.idle = make(chan struct{})
+	}
+	p.inflight++
+	return true
+}
+
+// end unregisters a shadow evaluation
+func (p *Provider) end() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.inflight--
+	if p.inflight == 0 {
+		close(p.idle)
+		p.idle = nil
+	}
+}
+
+// Stats returns the counters of the comparisons
+func (p *Provider) Stats() Stats {
+	return Stats{
+		Compared:   p.compared.Load(),
+		Mismatches: p.mismatches.Load(),
+		Dropped:    p.dropped.Load(),
+	}
+}
+
+// compare evaluates the flag with the shadow provider in the background, if the evaluation is sampled,
+// and compares the result with the primary result
+func (p *Provider) compare(
+	ctx context.Context,
+	flag string,
+	evalCtx openfeature.FlattenedContext,
+	value any,
+	detail openfeature.ProviderResolutionDetail,
+	evaluateShadow func(ctx context.Context) (any, openfeature.ProviderResolutionDetail),
+) {
+	if !p.sampled(flag) {
+		return
+	}
+	select {
+	case p.slots <- struct{}{}:
+	default:
+		p.dropped.Add(1)
+		return
+	}
+	if !p.begin() {
+		<-p.slots
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		defer p.end()
+		defer func() { <-p.slots }()
+		shadowValue, shadowDetail := evaluateShadow(ctx)
+		p.compared.Add(1)
+		primary, shadow := newResult(value, detail), newResult(shadowValue, shadowDetail)
+		if equal(primary, shadow) {
+			return
+		}
+		p.mismatches.Add(1)
+		if p.mismatchHandler != nil {
+			p.mismatchHandler(Mismatch{
+				Timestamp:   p.now(),
+				Flag:        flag,
+				ContextHash: p.hashContext(evalCtx),
+				Primary:     primary,
+				Shadow:      shadow,
+			})
+		}
+	}()
+}
+
+// sampled returns true if the evaluation of the flag must be compared
+func (p *Provider) sampled(flag string) bool {
+	if len(p.flags) > 0 {
+		if _, ok := p.flags[flag]; !ok {
+			return false
+		}
+	}
+	return p.sampleRate >= 1 || p.sample() < p.sampleRate
+}
+
+func newResult(value any, detail openfeature.ProviderResolutionDetail) Result {
+	resolution := detail.ResolutionDetail()
+	return Result{
+		Value:     value,
+		Variant:   resolution.Variant,
+		Reason:    string(resolution.Reason),
+		ErrorCode: string(resolution.ErrorCode),
+	}
+}
+
+// equal returns true if the values are the same and both or none of the evaluations failed.
+// The variants and reasons are not compared, since they are named differently by each vendor.
+// Objects are compared as JSON, since the providers may decode the same JSON to different types,
+// e.g. int and float64.
+func equal(primary, shadow Result) bool {
+	if (primary.ErrorCode == "") != (shadow.ErrorCode == "") {
+		return false
+	}
+	if reflect.DeepEqual(primary.Value, shadow.Value) {
+		return true
+	}
+	a, errA := json.Marshal(primary.Value)
+	b, errB := json.Marshal(shadow.Value)
+	if errA != nil || errB != nil {
+		return false
+	}
+	var va, vb any
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// hashContext returns the hash of the evaluation context as JSON, which has sorted keys
+func (p *Provider) hashContext(evalCtx openfeature.FlattenedContext) string {
+	b, err := json.Marshal(evalCtx)
+	if err != nil {
+		return ""
+	}
+	if len(p.salt) == 0 {
+		sum := sha256.Sum256(b)
+		return hex.EncodeToString(sum[:])
+	}
+	mac := hmac.New(sha256.New, p.salt)
+	mac.Write(b)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,298 @@
+package shadow
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProvider serves a fixed value for each flag, or the default value with FLAG_NOT_FOUND
+type fakeProvider struct {
+	openfeature.NoopProvider
+	values map[string]any
+	block  chan struct{}
+}
+
+func (p *fakeProvider) resolve(flag string, defaultValue any) (any, openfeature.ProviderResolutionDetail) {
+	if p.block != nil {
+		<-p.block
+	}
+	value, ok := p.values[flag]
+	if !ok {
+		return defaultValue, openfeature.ProviderResolutionDetail{
+			ResolutionError: openfeature.NewFlagNotFoundResolutionError("flag not found"),
+			Reason:          openfeature.ErrorReason,
+		}
+	}
+	return value, openfeature.ProviderResolutionDetail{Reason: openfeature.StaticReason, Variant: "variant"}
+}
+
+func (p *fakeProvider) BooleanEvaluation(
+	ctx context.Context,
+	flag string,
+	defaultValue bool,
+	evalCtx openfeature.FlattenedContext,
+) openfeature.BoolResolutionDetail {
+	value, detail := p.resolve(flag, defaultValue)
+	return openfeature.BoolResolutionDetail{Value: value.(bool), ProviderResolutionDetail: detail}
+}
+
+func (p *fakeProvider) ObjectEvaluation(
+	ctx context.Context,
+	flag string,
+	defaultValue any,
+	evalCtx openfeature.FlattenedContext,
+) openfeature.InterfaceResolutionDetail {
+	value, detail := p.resolve(flag, defaultValue)
+	return openfeature.InterfaceResolutionDetail{Value: value, ProviderResolutionDetail: detail}
+}
+
+// mismatchRecorder collects the mismatches passed to the handler
+type mismatchRecorder struct {
+	mu         sync.Mutex
+	mismatches []Mismatch
+}
+
+func (r *mismatchRecorder) handle(m Mismatch) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mismatches = append(r.mismatches, m)
+}
+
+func TestProviderCompare(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	evalCtx := openfeature.FlattenedContext{openfeature.TargetingKey: "user-1", "plan": "pro"}
+	primary := &fakeProvider{values: map[string]any{
+		"same":           true,
+		"different":      true,
+		"shadow-missing": true,
+		"object":         map[string]any{"limit": 10},
+	}}
+	shadow := &fakeProvider{values: map[string]any{
+		"same":      true,
+		"different": false,
+		"object":    map[string]any{"limit": float64(10)},
+	}}
+	tests := []struct {
+		desc     string
+		flag     string
+		object   bool
+		expected []Mismatch
+	}{
+		{desc: "same value", flag: "same"},
+		{desc: "same object decoded to other types", flag: "object", object: true},
+		{
+			desc: "different value",
+			flag: "different",
+			expected: []Mismatch{{
+				Timestamp:   now,
+				Flag:        "different",
+				ContextHash: "a5eb97e27238784ea200b4a14f48ac4ade821862ab2cc9b3aca378d181708438",
+				Primary:     Result{Value: true, Variant: "variant", Reason: "STATIC"},
+				Shadow:      Result{Value: false, Variant: "variant", Reason: "STATIC"},
+			}},
+		},
+		{
+			desc: "flag missing from the shadow provider",
+			flag: "shadow-missing",
+			expected: []Mismatch{{
+				Timestamp:   now,
+				Flag:        "shadow-missing",
+				ContextHash: "a5eb97e27238784ea200b4a14f48ac4ade821862ab2cc9b3aca378d181708438",
+				Primary:     Result{Value: true, Variant: "variant", Reason: "STATIC"},
+				Shadow:      Result{Value: false, Reason: "ERROR", ErrorCode: "FLAG_NOT_FOUND"},
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			recorder := &mismatchRecorder{}
+			p := NewProvider(primary, shadow, WithMismatchHandler(recorder.handle))
+			p.now = func() time.Time { return now }
+
+			if test.object {
+				detail := p.ObjectEvaluation(context.Background(), test.flag, nil, evalCtx)
+				assert.Equal(t, primary.values[test.flag], detail.Value)
+			} else {
+				detail := p.BooleanEvaluation(context.Background(), test.flag, false, evalCtx)
+				assert.Equal(t, primary.values[test.flag], detail.Value)
+			}
+			require.NoError(t, p.Wait(context.Background()))
+
+			assert.Equal(t, test.expected, recorder.mismatches)
+			assert.Equal(t, Stats{Compared: 1, Mismatches: uint64(len(test.expected))}, p.Stats())
+		})
+	}
+}
+
+func TestProviderSampling(t *testing.T) {
+	t.Parallel()
+	primary := &fakeProvider{values: map[string]any{"flag-1": true, "flag-2": true}}
+	shadow := &fakeProvider{values: map[string]any{"flag-1": false, "flag-2": false}}
+	evalCtx := openfeature.FlattenedContext{openfeature.TargetingKey: "user-1"}
+
+	p := NewProvider(primary, shadow, WithSampleRate(0.5), WithFlags("flag-1"))
+	samples := []float64{0.2, 0.7}
+	p.sample = func() float64 {
+		s := samples[0]
+		samples = samples[1:]
+		return s
+	}
+	p.BooleanEvaluation(context.Background(), "flag-1", false, evalCtx)
+	p.BooleanEvaluation(context.Background(), "flag-1", false, evalCtx)
+	p.BooleanEvaluation(context.Background(), "flag-2", false, evalCtx)
+	require.NoError(t, p.Wait(context.Background()))
+
+	assert.Equal(t, Stats{Compared: 1, Mismatches: 1}, p.Stats())
+}
+
+func TestProviderMaxConcurrency(t *testing.T) {
+	t.Parallel()
+	primary := &fakeProvider{values: map[string]any{"flag": true}}
+	shadow := &fakeProvider{values: map[string]any{"flag": true}, block: make(chan struct{})}
+	evalCtx := openfeature.FlattenedContext{openfeature.TargetingKey: "user-1"}
+
+	p := NewProvider(primary, shadow, WithMaxConcurrency(1))
+	// The primary evaluations don't wait for the blocked shadow evaluation
+	assert.True(t, p.BooleanEvaluation(context.Background(), "flag", false, evalCtx).Value)
+	assert.True(t, p.BooleanEvaluation(context.Background(), "flag", false, evalCtx).Value)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, p.Wait(ctx), context.DeadlineExceeded)
+
+	close(shadow.block)
+	require.NoError(t, p.Wait(context.Background()))
+	assert.Equal(t, Stats{Compared: 1, Dropped: 1}, p.Stats())
+}
+
+func TestProviderShutdown(t *testing.T) {
+	t.Parallel()
+	primary := &fakeProvider{values: map[string]any{"flag": true}}
+	shadow := &fakeProvider{values: map[string]any{"flag": false}}
+	evalCtx := openfeature.FlattenedContext{openfeature.TargetingKey: "user-1"}
+
+	p := NewProvider(primary, shadow)
+	// The evaluations racing with the shutdown are either compared before it ends, or not compared
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.BooleanEvaluation(context.Background(), "flag", false, evalCtx)
+		}()
+	}
+	require.NoError(t, p.ShutdownWithContext(context.Background()))
+	compared := p.Stats().Compared
+	wg.Wait()
+
+	assert.True(t, p.BooleanEvaluation(context.Background(), "flag", false, evalCtx).Value)
+	require.NoError(t, p.Wait(context.Background()))
+	assert.Equal(t, compared, p.Stats().Compared)
+}
+
+// lifecycleProvider records the calls of the context-aware lifecycle and emits events
+type lifecycleProvider struct {
+	fakeProvider
+	events      chan openfeature.Event
+	initCtx     context.Context
+	shutdownCtx context.Context
+	err         error
+}
+
+func (p *lifecycleProvider) InitWithContext(ctx context.Context, evalCtx openfeature.EvaluationContext) error {
+	p.initCtx = ctx
+	return p.err
+}
+
+func (p *lifecycleProvider) Init(evalCtx openfeature.EvaluationContext) error {
+	return p.InitWithContext(context.Background(), evalCtx)
+}
+
+func (p *lifecycleProvider) ShutdownWithContext(ctx context.Context) error {
+	p.shutdownCtx = ctx
+	return p.err
+}
+
+func (p *lifecycleProvider) Shutdown() {
+	_ = p.ShutdownWithContext(context.Background())
+}
+
+func (p *lifecycleProvider) EventChannel() <-chan openfeature.Event {
+	return p.events
+}
+
+type contextKey struct{}
+
+func TestProviderLifecycle(t *testing.T) {
+	t.Parallel()
+	primary := &lifecycleProvider{events: make(chan openfeature.Event, 1), err: assert.AnError}
+	shadow := &lifecycleProvider{events: make(chan openfeature.Event, 1)}
+	ctx := context.WithValue(context.Background(), contextKey{}, "value")
+
+	p := NewProvider(primary, shadow)
+	assert.ErrorIs(t, p.InitWithContext(ctx, openfeature.EvaluationContext{}), assert.AnError)
+	assert.Equal(t, ctx, primary.initCtx)
+	assert.Equal(t, ctx, shadow.initCtx)
+
+	// Only the events of the primary provider are forwarded
+	assert.Equal(t, (<-chan openfeature.Event)(primary.events), p.EventChannel())
+	assert.Nil(t, NewProvider(&fakeProvider{}, shadow).EventChannel())
+
+	assert.ErrorIs(t, p.ShutdownWithContext(ctx), assert.AnError)
+	assert.Equal(t, ctx, primary.shutdownCtx)
+	assert.Equal(t, ctx, shadow.shutdownCtx)
+}
+
+func TestProviderShutdownWaitTimeout(t *testing.T) {
+	t.Parallel()
+	primary := &lifecycleProvider{fakeProvider: fakeProvider{values: map[string]any{"flag": true}}}
+	shadow := &lifecycleProvider{
+		fakeProvider: fakeProvider{values: map[string]any{"flag": true}, block: make(chan struct{})},
+		err:          assert.AnError,
+	}
+	defer close(shadow.block)
+	evalCtx := openfeature.FlattenedContext{openfeature.TargetingKey: "user-1"}
+
+	p := NewProvider(primary, shadow)
+	p.BooleanEvaluation(context.Background(), "flag", false, evalCtx)
+
+	// Both providers are shut down even if the shadow evaluation is still in progress
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := p.ShutdownWithContext(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.NotNil(t, primary.shutdownCtx)
+	assert.NotNil(t, shadow.shutdownCtx)
+}
+
+func TestWithMaxConcurrency(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, 2, cap(NewProvider(&fakeProvider{}, &fakeProvider{}, WithMaxConcurrency(2)).slots))
+	assert.Equal(t, defaultMaxConcurrency, cap(NewProvider(&fakeProvider{}, &fakeProvider{}, WithMaxConcurrency(-1)).slots))
+	assert.Equal(t, defaultMaxConcurrency, cap(NewProvider(&fakeProvider{}, &fakeProvider{}, WithMaxConcurrency(0)).slots))
+}
+
+func TestHashContext(t *testing.T) {
+	t.Parallel()
+	evalCtx := openfeature.FlattenedContext{openfeature.TargetingKey: "user-1", "plan": "pro"}
+
+	p := NewProvider(&fakeProvider{}, &fakeProvider{})
+	assert.Equal(t, p.hashContext(evalCtx), p.hashContext(openfeature.FlattenedContext{
+		"plan":                   "pro",
+		openfeature.TargetingKey: "user-1",
+	}))
+
+	salted := NewProvider(&fakeProvider{}, &fakeProvider{}, WithContextHashSalt([]byte("salt")))
+	assert.NotEqual(t, p.hashContext(evalCtx), salted.hashContext(evalCtx))
+	assert.Len(t, salted.hashContext(evalCtx), 64)
+}
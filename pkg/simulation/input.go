@@ -0,0 +1,84 @@
+package simulation
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// Formats of the evaluation contexts read by ReadContexts
+const (
+	// FormatCSV is a CSV file with a header row naming the attributes, e.g. targetingKey,plan,age.
+	// Empty cells are omitted from the context.
+	FormatCSV = "csv"
+	// FormatJSONL is a JSON object per line, e.g. {"targetingKey":"user-1","plan":"pro","age":30}.
+	FormatJSONL = "jsonl"
+)
+
+// maxLineSize is the maximum size of a JSON line
+const maxLineSize = 1024 * 1024
+
+// ReadContexts reads evaluation contexts in the format
+func ReadContexts(r io.Reader, format string) ([]openfeature.FlattenedContext, error) {
+	switch format {
+	case FormatCSV:
+		return readCSV(r)
+	case FormatJSONL:
+		return readJSONL(r)
+	default:
+		return nil, fmt.Errorf("unknown format %q, expected %s or %s", format, FormatCSV, FormatJSONL)
+	}
+}
+
+func readCSV(r io.Reader) ([]openfeature.FlattenedContext, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the header: %w", err)
+	}
+
+	var contexts []openfeature.FlattenedContext
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return contexts, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		evalCtx := make(openfeature.FlattenedContext, len(row))
+		for i, value := range row {
+			if value != "" {
+				evalCtx[header[i]] = value
+			}
+		}
+		contexts = append(contexts, evalCtx)
+	}
+}
+
+func readJSONL(r io.Reader) ([]openfeature.FlattenedContext, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineSize)
+	var contexts []openfeature.FlattenedContext
+	for line := 1; scanner.Scan(); line++ {
+		b := bytes.TrimSpace(scanner.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+		var evalCtx openfeature.FlattenedContext
+		if err := json.Unmarshal(b, &evalCtx); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		contexts = append(contexts, evalCtx)
+	}
+	return contexts, scanner.Err()
+}
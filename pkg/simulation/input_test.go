@@ -0,0 +1,75 @@
+package simulation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadContexts(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		desc          string
+		format        string
+		input         string
+		expected      []openfeature.FlattenedContext
+		expectedError bool
+	}{
+		{
+			desc:   "csv",
+			format: FormatCSV,
+			input:  "targetingKey,plan,age\nuser-1,pro,30\nuser-2,,41\n",
+			expected: []openfeature.FlattenedContext{
+				{openfeature.TargetingKey: "user-1", "plan": "pro", "age": "30"},
+				{openfeature.TargetingKey: "user-2", "age": "41"},
+			},
+		},
+		{
+			desc:   "csv without rows",
+			format: FormatCSV,
+			input:  "",
+		},
+		{
+			desc:          "csv with missing cells",
+			format:        FormatCSV,
+			input:         "targetingKey,plan\nuser-1\n",
+			expectedError: true,
+		},
+		{
+			desc:   "jsonl",
+			format: FormatJSONL,
+			input:  "{\"targetingKey\":\"user-1\",\"plan\":\"pro\",\"age\":30}\n\n{\"targetingKey\":\"user-2\"}",
+			expected: []openfeature.FlattenedContext{
+				{openfeature.TargetingKey: "user-1", "plan": "pro", "age": float64(30)},
+				{openfeature.TargetingKey: "user-2"},
+			},
+		},
+		{
+			desc:          "invalid jsonl",
+			format:        FormatJSONL,
+			input:         "{\"targetingKey\":\"user-1\"}\n[1,2]\n",
+			expectedError: true,
+		},
+		{
+			desc:          "unknown format",
+			format:        "xml",
+			input:         "<user/>",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			contexts, err := ReadContexts(strings.NewReader(test.input), test.format)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, contexts)
+		})
+	}
+}
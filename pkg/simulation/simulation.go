@@ -0,0 +1,156 @@
+// Package simulation evaluates a flag for a list of evaluation contexts, e.g. a sample of real users,
+// and reports the distribution of the variants, to validate the targeting of a flag before widening a rollout.
+//
+// The provider should evaluate the flags with the dryrun SDK, so the simulated evaluations
+// are not reported to Bucketeer and don't affect the experiment analytics.
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/open-feature/go-sdk/openfeature"
+
+	provider "github.com/bucketeer-io/openfeature-go-server-sdk/pkg"
+)
+
+// DefaultVariant is the variant counted when the default value is returned, e.g. because the flag was not found
+const DefaultVariant = "(default)"
+
+// MissingValue is the bucket of the contexts without the bucket attribute
+const MissingValue = "(missing)"
+
+// Bucket groups the evaluations by the value of an attribute.
+// If Width is set, numeric values are grouped in ranges of Width, e.g. [20,30) for ages.
+type Bucket struct {
+	Attribute string
+	Width     float64
+}
+
+// ParseBucket parses a bucket written as attribute or attribute:width
+func ParseBucket(s string) (Bucket, error) {
+	attribute, width, ok := strings.Cut(s, ":")
+	if attribute == "" {
+		return Bucket{}, fmt.Errorf("invalid bucket %q, expected attribute or attribute:width", s)
+	}
+	if !ok {
+		return Bucket{Attribute: attribute}, nil
+	}
+	w, err := strconv.ParseFloat(width, 64)
+	if err != nil || w <= 0 || math.IsInf(w, 0) {
+		return Bucket{}, fmt.Errorf("invalid bucket width %q, expected a positive number", width)
+	}
+	return Bucket{Attribute: attribute, Width: w}, nil
+}
+
+// Counts is the number of evaluations by variant
+type Counts map[string]int
+
+// Total returns the number of evaluations
+func (c Counts) Total() int {
+	total := 0
+	for _, n := range c {
+		total += n
+	}
+	return total
+}
+
+// Report is the distribution of the variants of a flag
+type Report struct {
+	Flag     string `json:"flag"`
+	Total    int    `json:"total"`
+	Variants Counts `json:"variants"`
+	// Reasons are the variants by evaluation reason: the Bucketeer reason if available, e.g. RULE,
+	// or the error code if the provider failed to evaluate the flag, e.g. TARGETING_KEY_MISSING.
+	Reasons map[string]Counts `json:"reasons"`
+	// Buckets are the variants by value of each bucket attribute
+	Buckets map[string]map[string]Counts `json:"buckets,omitempty"`
+}
+
+// Run evaluates the flag for each context and returns the distribution of the variants.
+//
+// The flag is evaluated as a string, since any variation value is a valid string,
+// so the variants are counted whatever the type of the flag.
+func Run(
+	ctx context.Context,
+	p openfeature.FeatureProvider,
+	flag string,
+	contexts []openfeature.FlattenedContext,
+	buckets ...Bucket,
+) *Report {
+	report := &Report{
+		Flag:     flag,
+		Variants: Counts{},
+		Reasons:  map[string]Counts{},
+	}
+	if len(buckets) > 0 {
+		report.Buckets = make(map[string]map[string]Counts, len(buckets))
+		for _, b := range buckets {
+			report.Buckets[b.Attribute] = map[string]Counts{}
+		}
+	}
+
+	for _, evalCtx := range contexts {
+		detail := p.StringEvaluation(ctx, flag, "", evalCtx).ProviderResolutionDetail
+		variant := variantOf(detail)
+		reason := reasonOf(detail)
+
+		report.Total++
+		report.Variants[variant]++
+		if report.Reasons[reason] == nil {
+			report.Reasons[reason] = Counts{}
+		}
+		report.Reasons[reason][variant]++
+		for _, b := range buckets {
+			value := b.valueOf(evalCtx)
+			if report.Buckets[b.Attribute][value] == nil {
+				report.Buckets[b.Attribute][value] = Counts{}
+			}
+			report.Buckets[b.Attribute][value][variant]++
+		}
+	}
+	return report
+}
+
+func variantOf(detail openfeature.ProviderResolutionDetail) string {
+	if detail.Variant == "" {
+		return DefaultVariant
+	}
+	return detail.Variant
+}
+
+func reasonOf(detail openfeature.ProviderResolutionDetail) string {
+	resolution := detail.ResolutionDetail()
+	if reason, err := resolution.FlagMetadata.GetString(provider.FlagMetadataBucketeerReason); err == nil {
+		return reason
+	}
+	if resolution.ErrorCode != "" {
+		return string(resolution.ErrorCode)
+	}
+	return string(resolution.Reason)
+}
+
+// valueOf returns the bucket of the context
+func (b Bucket) valueOf(evalCtx openfeature.FlattenedContext) string {
+	v, ok := evalCtx[b.Attribute]
+	if !ok || v == nil {
+		return MissingValue
+	}
+	s := fmt.Sprint(v)
+	if b.Width <= 0 {
+		return s
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return s
+	}
+	low := math.Floor(f/b.Width) * b.Width
+	return fmt.Sprintf("[%s,%s)", formatNumber(low), formatNumber(low+b.Width))
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,109 @@
+package simulation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	provider "github.com/bucketeer-io/openfeature-go-server-sdk/pkg"
+	"github.com/bucketeer-io/openfeature-go-server-sdk/pkg/inmemory"
+)
+
+func newTestProvider(t *testing.T) *provider.Provider {
+	t.Helper()
+	p, err := provider.NewProviderFromSDK(inmemory.New(map[string]inmemory.Flag{
+		"new-checkout": {
+			Variations: []inmemory.Variation{
+				{ID: "variation-new", Name: "new", Value: "true"},
+				{ID: "variation-old", Name: "old", Value: "false"},
+			},
+			DefaultVariation: "variation-old",
+			Targets:          map[string]string{"beta-user": "variation-new"},
+			Rules: []inmemory.Rule{
+				{Attribute: "plan", Values: []string{"pro"}, Variation: "variation-new"},
+			},
+		},
+	}))
+	require.NoError(t, err)
+	return p
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+	contexts := []openfeature.FlattenedContext{
+		{openfeature.TargetingKey: "beta-user", "plan": "free", "age": "25"},
+		{openfeature.TargetingKey: "user-1", "plan": "pro", "age": 31},
+		{openfeature.TargetingKey: "user-2", "plan": "pro", "age": "38"},
+		{openfeature.TargetingKey: "user-3", "plan": "free", "age": "42"},
+		{openfeature.TargetingKey: "user-4"},
+		{"plan": "pro"},
+	}
+
+	report := Run(context.Background(), newTestProvider(t), "new-checkout", contexts,
+		Bucket{Attribute: "plan"},
+		Bucket{Attribute: "age", Width: 10},
+	)
+
+	assert.Equal(t, &Report{
+		Flag:  "new-checkout",
+		Total: 6,
+		Variants: Counts{
+			"new":          3,
+			"old":          2,
+			DefaultVariant: 1,
+		},
+		Reasons: map[string]Counts{
+			"TARGET":                {"new": 1},
+			"RULE":                  {"new": 2},
+			"DEFAULT":               {"old": 2},
+			"TARGETING_KEY_MISSING": {DefaultVariant: 1},
+		},
+		Buckets: map[string]map[string]Counts{
+			"plan": {
+				"free":       {"new": 1, "old": 1},
+				"pro":        {"new": 2, DefaultVariant: 1},
+				MissingValue: {"old": 1},
+			},
+			"age": {
+				"[20,30)":    {"new": 1},
+				"[30,40)":    {"new": 2},
+				"[40,50)":    {"old": 1},
+				MissingValue: {"old": 1, DefaultVariant: 1},
+			},
+		},
+	}, report)
+	assert.Equal(t, 6, report.Variants.Total())
+}
+
+func TestParseBucket(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		desc          string
+		input         string
+		expected      Bucket
+		expectedError bool
+	}{
+		{desc: "attribute", input: "plan", expected: Bucket{Attribute: "plan"}},
+		{desc: "attribute and width", input: "age:10", expected: Bucket{Attribute: "age", Width: 10}},
+		{desc: "decimal width", input: "score:0.5", expected: Bucket{Attribute: "score", Width: 0.5}},
+		{desc: "missing attribute", input: ":10", expectedError: true},
+		{desc: "invalid width", input: "age:ten", expectedError: true},
+		{desc: "negative width", input: "age:-1", expectedError: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			bucket, err := ParseBucket(test.input)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, bucket)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+var _ openfeature.EventHandler = (*Provider)(nil)
+
+// eventBufferSize is the number of events kept until the OpenFeature SDK reads them
+const eventBufferSize = 10
+
+// Status returns the state of the provider: NOT_READY after the shutdown,
+// ERROR while the circuit breaker is open, READY otherwise.
+func (p *Provider) Status() openfeature.State {
+	if p == nil {
+		return openfeature.NotReadyState
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return openfeature.NotReadyState
+	}
+	if p.breaker != nil && !p.breaker.isClosed() {
+		return openfeature.ErrorState
+	}
+	return openfeature.ReadyState
+}
+
+// EventChannel returns the channel of the events emitted by the provider,
+// read by the OpenFeature SDK when the provider is set.
+// It returns nil for a nil provider, e.g. when NewProvider failed, so no event is ever read.
+func (p *Provider) EventChannel() <-chan openfeature.Event {
+	if p == nil {
+		return nil
+	}
+	return p.events
+}
+
+// emit sends an event without blocking, dropping it if nobody reads the channel
+func (p *Provider) emit(eventType openfeature.EventType, message string) {
+	event := openfeature.Event{
+		ProviderName:         p.Metadata().Name,
+		EventType:            eventType,
+		ProviderEventDetails: openfeature.ProviderEventDetails{Message: message},
+	}
+	if eventType == openfeature.ProviderError {
+		event.ErrorCode = openfeature.GeneralCode
+	}
+	select {
+	case p.events <- event:
+	default:
+	}
+}
@@ -0,0 +1,35 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	mockProvider "github.com/bucketeer-io/openfeature-go-server-sdk/test/mock/provider"
+)
+
+func TestStatus(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSDK := mockProvider.NewMockBucketeerSDK(ctrl)
+	mockSDK.EXPECT().Close(gomock.Any()).Return(nil).Times(1)
+	provider := newTestProvider(mockSDK)
+
+	assert.Equal(t, openfeature.ReadyState, provider.Status())
+	require.NoError(t, provider.ShutdownWithContext(context.Background()))
+	assert.Equal(t, openfeature.NotReadyState, provider.Status())
+}
+
+func TestNilProvider(t *testing.T) {
+	t.Parallel()
+	// A nil provider, e.g. returned with an error by NewProvider, must not panic when it's set
+	var provider *Provider
+	assert.Nil(t, provider.EventChannel())
+	assert.Equal(t, openfeature.NotReadyState, provider.Status())
+}
@@ -0,0 +1,41 @@
+package provider
+
+import (
+	"context"
+	"maps"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+var _ openfeature.Tracker = (*Provider)(nil)
+
+// Track reports a goal event to Bucketeer, using the tracking event name as the goal ID.
+// It does nothing if the evaluation context can't be converted to a Bucketeer user,
+// if the SDK doesn't support goal events, or if the events are disabled, see WithoutEvents.
+func (p *Provider) Track(
+	ctx context.Context,
+	trackingEventName string,
+	evalCtx openfeature.EvaluationContext,
+	details openfeature.TrackingEventDetails,
+) {
+	sdk, ok := p.Unwrap()
+	if !ok || !p.reportsEvents(ctx) {
+		return
+	}
+	bucketeerUser, _, err := toBucketeerUser(flattenContext(evalCtx), p.contextConfig)
+	if err != nil {
+		return
+	}
+	sdk.TrackValue(ctx, ToPtr(bucketeerUser), trackingEventName, details.Value())
+}
+
+// flattenContext converts an EvaluationContext to a FlattenedContext
+// the same way the OpenFeature SDK does before calling the provider.
+func flattenContext(evalCtx openfeature.EvaluationContext) openfeature.FlattenedContext {
+	flatCtx := openfeature.FlattenedContext{}
+	maps.Copy(flatCtx, evalCtx.Attributes())
+	if evalCtx.TargetingKey() != "" {
+		flatCtx[openfeature.TargetingKey] = evalCtx.TargetingKey()
+	}
+	return flatCtx
+}
@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/user"
+	"github.com/open-feature/go-sdk/openfeature"
+	"go.uber.org/mock/gomock"
+
+	mockProvider "github.com/bucketeer-io/openfeature-go-server-sdk/test/mock/provider"
+)
+
+func TestTrack(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		desc    string
+		evalCtx openfeature.EvaluationContext
+		details openfeature.TrackingEventDetails
+		setup   func(mockSDK *mockProvider.MockExtendedSDK)
+	}{
+		{
+			desc: "goal tracked with value",
+			evalCtx: openfeature.NewEvaluationContext("test-user", map[string]any{
+				"plan": "pro",
+			}),
+			details: openfeature.NewTrackingEventDetails(9.99),
+			setup: func(mockSDK *mockProvider.MockExtendedSDK) {
+				mockSDK.EXPECT().
+					TrackValue(gomock.Any(), &user.User{
+						ID:   "test-user",
+						Data: map[string]string{"plan": "pro"},
+					}, "purchase", 9.99).
+					Times(1)
+			},
+		},
+		{
+			desc:    "missing targeting key is not tracked",
+			evalCtx: openfeature.NewTargetlessEvaluationContext(map[string]any{"plan": "pro"}),
+			details: openfeature.NewTrackingEventDetails(1),
+			setup:   func(mockSDK *mockProvider.MockExtendedSDK) {},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSDK := mockProvider.NewMockExtendedSDK(ctrl)
+			test.setup(mockSDK)
+			provider := newTestProvider(mockSDK)
+
+			provider.Track(context.Background(), "purchase", test.evalCtx, test.details)
+		})
+	}
+}
+
+func TestTrackUnsupportedSDK(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// The mock SDK doesn't support goal events, so nothing is called
+	provider := newTestProvider(mockProvider.NewMockBucketeerSDK(ctrl))
+	provider.Track(
+		context.Background(),
+		"purchase",
+		openfeature.NewEvaluationContext("test-user", nil),
+		openfeature.NewTrackingEventDetails(1),
+	)
+}
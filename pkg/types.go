@@ -0,0 +1,182 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/model"
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/user"
+)
+
+// TypePolicy is the policy applied when the variation value doesn't match the requested flag type.
+type TypePolicy int
+
+const (
+	// TypePolicySDK lets the Bucketeer SDK parse the variation value. This is the default.
+	// The SDK accepts e.g. "1" and "t" as booleans, and truncates floats requested as integers.
+	TypePolicySDK TypePolicy = iota
+	// TypePolicyStrict fails the evaluation with TYPE_MISMATCH unless the variation value
+	// is exactly of the requested type: "true" or "false" for booleans, an integer for integers,
+	// a number for floats, and a JSON object or array for objects.
+	// Any variation value is a valid string, so string evaluations are not checked.
+	TypePolicyStrict
+	// TypePolicyLenient coerces the variation value to the requested type when possible,
+	// and records the coercion in the FlagMetadataTypeCoercion flag metadata.
+	// For example, "yes" is coerced to a boolean, 2.0 to an integer, and " 42 " or "\"42\"" to a number.
+	TypePolicyLenient
+)
+
+// Type coercions recorded in the FlagMetadataTypeCoercion flag metadata by TypePolicyLenient.
+const (
+	// TypeCoercionStringToBool is a boolean coerced from a string other than "true" or "false",
+	// e.g. "TRUE", "yes", "on" or "1".
+	TypeCoercionStringToBool = "string-to-bool"
+	// TypeCoercionStringToNumber is a number parsed from a JSON-quoted or space-padded string.
+	TypeCoercionStringToNumber = "string-to-number"
+	// TypeCoercionFloatToInt is an integer coerced from an integral float, e.g. 2.0.
+	TypeCoercionFloatToInt = "float-to-int"
+	// TypeCoercionStringToObject is a variation value that is not JSON, returned as a string for an object.
+	TypeCoercionStringToObject = "string-to-object"
+)
+
+// WithTypePolicy sets the policy applied when the variation value doesn't match the requested flag type,
+// e.g. to migrate the type of a flag without breaking services still evaluating the old type.
+//
+// With TypePolicyStrict and TypePolicyLenient, the variation is evaluated as a string and converted by the provider,
+// so the evaluation is reported to Bucketeer even if the conversion fails.
+func WithTypePolicy(policy TypePolicy) Option {
+	return func(p *Provider) {
+		p.typePolicy = policy
+	}
+}
+
+// convertedVariation evaluates the flag as a string and converts the variation value according to the type policy.
+// It returns the coercion applied, if any.
+// If the value can't be converted, the evaluation returns defaultValue with the ERROR_WRONG_TYPE reason.
+func convertedVariation[T model.EvaluationValue](
+	ctx context.Context,
+	sdk BucketeerSDK,
+	user *user.User,
+	flag string,
+	defaultValue T,
+	policy TypePolicy,
+	convert converter[T],
+) (model.BKTEvaluationDetails[T], string) {
+	raw := sdk.StringVariationDetails(ctx, user, flag, "")
+	evaluation := model.BKTEvaluationDetails[T]{
+		FeatureID:      raw.FeatureID,
+		FeatureVersion: raw.FeatureVersion,
+		UserID:         raw.UserID,
+		VariationID:    raw.VariationID,
+		VariationName:  raw.VariationName,
+		VariationValue: defaultValue,
+		Reason:         raw.Reason,
+	}
+	// No variation was served, e.g. the flag was not found
+	if raw.VariationID == "" {
+		return evaluation, ""
+	}
+	value, coercion, ok := convert(raw.VariationValue, policy)
+	if !ok {
+		return model.BKTEvaluationDetails[T]{
+			FeatureID:      raw.FeatureID,
+			UserID:         raw.UserID,
+			VariationValue: defaultValue,
+			Reason:         model.EvaluationReasonErrorWrongType,
+		}, ""
+	}
+	evaluation.VariationValue = value
+	return evaluation, coercion
+}
+
+// converter converts a variation value to the flag type according to the type policy.
+// It returns the coercion applied if any, and false if the value can't be converted.
+type converter[T any] func(value string, policy TypePolicy) (T, string, bool)
+
+func convertBool(value string, policy TypePolicy) (bool, string, bool) {
+	switch value {
+	case "true":
+		return true, "", true
+	case "false":
+		return false, "", true
+	}
+	if policy != TypePolicyLenient {
+		return false, "", false
+	}
+	switch strings.ToLower(strings.TrimSpace(unquote(value))) {
+	case "true", "t", "yes", "y", "on", "1":
+		return true, TypeCoercionStringToBool, true
+	case "false", "f", "no", "n", "off", "0":
+		return false, TypeCoercionStringToBool, true
+	}
+	return false, "", false
+}
+
+func convertInt(value string, policy TypePolicy) (int64, string, bool) {
+	if v, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return v, "", true
+	}
+	if policy != TypePolicyLenient {
+		return 0, "", false
+	}
+	trimmed := strings.TrimSpace(unquote(value))
+	if v, err := strconv.ParseInt(trimmed, 10, 64); err == nil {
+		return v, TypeCoercionStringToNumber, true
+	}
+	f, err := strconv.ParseFloat(trimmed, 64)
+	// float64(math.MaxInt64) rounds up to 2^63, which doesn't fit in an int64
+	if err != nil || f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, "", false
+	}
+	return int64(f), TypeCoercionFloatToInt, true
+}
+
+func convertFloat(value string, policy TypePolicy) (float64, string, bool) {
+	if v, err := strconv.ParseFloat(value, 64); err == nil {
+		// ParseFloat accepts "NaN" and "Inf", which are not numbers for flags
+		if policy == TypePolicyStrict && (math.IsNaN(v) || math.IsInf(v, 0)) {
+			return 0, "", false
+		}
+		return v, "", true
+	}
+	if policy != TypePolicyLenient {
+		return 0, "", false
+	}
+	if v, err := strconv.ParseFloat(strings.TrimSpace(unquote(value)), 64); err == nil {
+		return v, TypeCoercionStringToNumber, true
+	}
+	return 0, "", false
+}
+
+func convertObject(value string, policy TypePolicy) (interface{}, string, bool) {
+	var v interface{}
+	if err := json.Unmarshal([]byte(value), &v); err != nil {
+		if policy != TypePolicyLenient {
+			return nil, "", false
+		}
+		return value, TypeCoercionStringToObject, true
+	}
+	if policy == TypePolicyStrict {
+		switch v.(type) {
+		case map[string]interface{}, []interface{}:
+		default:
+			return nil, "", false
+		}
+	}
+	return v, "", true
+}
+
+// unquote returns the string if the value is a JSON string, or the value itself otherwise
+func unquote(value string) string {
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return value
+	}
+	var s string
+	if err := json.Unmarshal([]byte(value), &s); err != nil {
+		return value
+	}
+	return s
+}
@@ -0,0 +1,224 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/model"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	mockProvider "github.com/bucketeer-io/openfeature-go-server-sdk/test/mock/provider"
+)
+
+type conversionTest struct {
+	value            string
+	policy           TypePolicy
+	expected         any
+	expectedCoercion string
+	expectedOK       bool
+}
+
+func assertConversions[T any](t *testing.T, convert converter[T], tests []conversionTest) {
+	t.Helper()
+	for _, test := range tests {
+		value, coercion, ok := convert(test.value, test.policy)
+		assert.Equal(t, test.expectedOK, ok, "%q with policy %d", test.value, test.policy)
+		if test.expectedOK {
+			assert.Equal(t, test.expected, value, "%q with policy %d", test.value, test.policy)
+			assert.Equal(t, test.expectedCoercion, coercion, "%q with policy %d", test.value, test.policy)
+		}
+	}
+}
+
+func TestConvertBool(t *testing.T) {
+	t.Parallel()
+	assertConversions(t, convertBool, []conversionTest{
+		{value: "true", policy: TypePolicyStrict, expected: true, expectedOK: true},
+		{value: "false", policy: TypePolicyStrict, expected: false, expectedOK: true},
+		{value: "TRUE", policy: TypePolicyStrict, expectedOK: false},
+		{value: "1", policy: TypePolicyStrict, expectedOK: false},
+		{value: "true", policy: TypePolicyLenient, expected: true, expectedOK: true},
+		{value: "TRUE", policy: TypePolicyLenient, expected: true, expectedCoercion: TypeCoercionStringToBool, expectedOK: true},
+		{value: " yes ", policy: TypePolicyLenient, expected: true, expectedCoercion: TypeCoercionStringToBool, expectedOK: true},
+		{value: `"on"`, policy: TypePolicyLenient, expected: true, expectedCoercion: TypeCoercionStringToBool, expectedOK: true},
+		{value: "0", policy: TypePolicyLenient, expected: false, expectedCoercion: TypeCoercionStringToBool, expectedOK: true},
+		{value: "off", policy: TypePolicyLenient, expected: false, expectedCoercion: TypeCoercionStringToBool, expectedOK: true},
+		{value: "maybe", policy: TypePolicyLenient, expectedOK: false},
+	})
+}
+
+func TestConvertInt(t *testing.T) {
+	t.Parallel()
+	assertConversions(t, convertInt, []conversionTest{
+		{value: "42", policy: TypePolicyStrict, expected: int64(42), expectedOK: true},
+		{value: "-1", policy: TypePolicyStrict, expected: int64(-1), expectedOK: true},
+		{value: "2.0", policy: TypePolicyStrict, expectedOK: false},
+		{value: " 42", policy: TypePolicyStrict, expectedOK: false},
+		{value: "42", policy: TypePolicyLenient, expected: int64(42), expectedOK: true},
+		{value: " 42 ", policy: TypePolicyLenient, expected: int64(42), expectedCoercion: TypeCoercionStringToNumber, expectedOK: true},
+		{value: `"42"`, policy: TypePolicyLenient, expected: int64(42), expectedCoercion: TypeCoercionStringToNumber, expectedOK: true},
+		{value: "2.0", policy: TypePolicyLenient, expected: int64(2), expectedCoercion: TypeCoercionFloatToInt, expectedOK: true},
+		{value: "1e3", policy: TypePolicyLenient, expected: int64(1000), expectedCoercion: TypeCoercionFloatToInt, expectedOK: true},
+		{value: "2.5", policy: TypePolicyLenient, expectedOK: false},
+		{value: "1e30", policy: TypePolicyLenient, expectedOK: false},
+		{value: "abc", policy: TypePolicyLenient, expectedOK: false},
+	})
+}
+
+func TestConvertFloat(t *testing.T) {
+	t.Parallel()
+	assertConversions(t, convertFloat, []conversionTest{
+		{value: "1.5", policy: TypePolicyStrict, expected: 1.5, expectedOK: true},
+		{value: "2", policy: TypePolicyStrict, expected: float64(2), expectedOK: true},
+		{value: "NaN", policy: TypePolicyStrict, expectedOK: false},
+		{value: `"1.5"`, policy: TypePolicyStrict, expectedOK: false},
+		{value: `"1.5"`, policy: TypePolicyLenient, expected: 1.5, expectedCoercion: TypeCoercionStringToNumber, expectedOK: true},
+		{value: " 1.5\n", policy: TypePolicyLenient, expected: 1.5, expectedCoercion: TypeCoercionStringToNumber, expectedOK: true},
+		{value: "abc", policy: TypePolicyLenient, expectedOK: false},
+	})
+}
+
+func TestConvertObject(t *testing.T) {
+	t.Parallel()
+	assertConversions(t, convertObject, []conversionTest{
+		{value: `{"a":1}`, policy: TypePolicyStrict, expected: map[string]any{"a": float64(1)}, expectedOK: true},
+		{value: `[1,2]`, policy: TypePolicyStrict, expected: []any{float64(1), float64(2)}, expectedOK: true},
+		{value: `true`, policy: TypePolicyStrict, expectedOK: false},
+		{value: `text`, policy: TypePolicyStrict, expectedOK: false},
+		{value: `true`, policy: TypePolicyLenient, expected: true, expectedOK: true},
+		{value: `text`, policy: TypePolicyLenient, expected: "text", expectedCoercion: TypeCoercionStringToObject, expectedOK: true},
+	})
+}
+
+func TestEvaluationWithTypePolicy(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		desc             string
+		policy           TypePolicy
+		variation        model.BKTEvaluationDetails[string]
+		expectedValue    int64
+		expectedReason   openfeature.Reason
+		expectedError    openfeature.ResolutionError
+		expectedCoercion string
+	}{
+		{
+			desc:   "strict with integer",
+			policy: TypePolicyStrict,
+			variation: model.BKTEvaluationDetails[string]{
+				FeatureID:      "int-flag",
+				FeatureVersion: 2,
+				UserID:         "test-user",
+				VariationID:    "variation-1",
+				VariationName:  "variation-name",
+				VariationValue: "42",
+				Reason:         model.EvaluationReasonRule,
+			},
+			expectedValue:  42,
+			expectedReason: openfeature.TargetingMatchReason,
+		},
+		{
+			desc:   "strict with float",
+			policy: TypePolicyStrict,
+			variation: model.BKTEvaluationDetails[string]{
+				FeatureID:      "int-flag",
+				FeatureVersion: 2,
+				UserID:         "test-user",
+				VariationID:    "variation-1",
+				VariationName:  "variation-name",
+				VariationValue: "2.5",
+				Reason:         model.EvaluationReasonRule,
+			},
+			expectedValue:  100,
+			expectedReason: openfeature.ErrorReason,
+			expectedError:  openfeature.NewTypeMismatchResolutionError(string(model.EvaluationReasonErrorWrongType)),
+		},
+		{
+			desc:   "lenient with integral float",
+			policy: TypePolicyLenient,
+			variation: model.BKTEvaluationDetails[string]{
+				FeatureID:      "int-flag",
+				FeatureVersion: 2,
+				UserID:         "test-user",
+				VariationID:    "variation-1",
+				VariationName:  "variation-name",
+				VariationValue: "2.0",
+				Reason:         model.EvaluationReasonDefault,
+			},
+			expectedValue:    2,
+			expectedReason:   openfeature.DefaultReason,
+			expectedCoercion: TypeCoercionFloatToInt,
+		},
+		{
+			desc:   "no variation served",
+			policy: TypePolicyLenient,
+			variation: model.BKTEvaluationDetails[string]{
+				FeatureID: "int-flag",
+				UserID:    "test-user",
+				Reason:    model.EvaluationReasonErrorFlagNotFound,
+			},
+			expectedValue:  100,
+			expectedReason: openfeature.ErrorReason,
+			expectedError:  openfeature.NewFlagNotFoundResolutionError(string(model.EvaluationReasonErrorFlagNotFound)),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSDK := mockProvider.NewMockBucketeerSDK(ctrl)
+			mockSDK.EXPECT().
+				StringVariationDetails(gomock.Any(), gomock.Any(), "int-flag", "").
+				Return(test.variation).
+				Times(1)
+			provider := newTestProvider(mockSDK, WithTypePolicy(test.policy))
+
+			result := provider.IntEvaluation(
+				context.Background(),
+				"int-flag",
+				100,
+				openfeature.FlattenedContext{openfeature.TargetingKey: "test-user"},
+			)
+
+			assert.Equal(t, test.expectedValue, result.Value)
+			assert.Equal(t, test.expectedReason, result.Reason)
+			assert.Equal(t, test.expectedError, result.ResolutionError)
+			coercion, _ := result.FlagMetadata.GetString(FlagMetadataTypeCoercion)
+			assert.Equal(t, test.expectedCoercion, coercion)
+		})
+	}
+}
+
+func TestStringEvaluationWithTypePolicy(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// String evaluations are not converted, so the SDK is called once with the default value
+	mockSDK := mockProvider.NewMockBucketeerSDK(ctrl)
+	mockSDK.EXPECT().
+		StringVariationDetails(gomock.Any(), gomock.Any(), "string-flag", "default").
+		Return(model.BKTEvaluationDetails[string]{
+			FeatureID:      "string-flag",
+			UserID:         "test-user",
+			VariationID:    "variation-1",
+			VariationValue: "true",
+			Reason:         model.EvaluationReasonRule,
+		}).
+		Times(1)
+	provider := newTestProvider(mockSDK, WithTypePolicy(TypePolicyStrict))
+
+	result := provider.StringEvaluation(
+		context.Background(),
+		"string-flag",
+		"default",
+		openfeature.FlattenedContext{openfeature.TargetingKey: "test-user"},
+	)
+
+	assert.Equal(t, "true", result.Value)
+	assert.Equal(t, openfeature.TargetingMatchReason, result.Reason)
+}
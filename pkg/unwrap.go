@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"context"
+	"sync"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// Unwrap returns the Bucketeer SDK used by the provider, to call the functions not covered by OpenFeature
+// without creating a second SDK instance, which would poll and send events separately.
+// It returns false if the SDK doesn't implement ExtendedSDK, e.g. a test double.
+//
+// The SDK is closed with the provider, so it must not be closed directly.
+func (p *Provider) Unwrap() (ExtendedSDK, bool) {
+	sdk, ok := p.sdk.(ExtendedSDK)
+	return sdk, ok
+}
+
+// Registry binds Bucketeer providers to OpenFeature domains and finds them by domain,
+// e.g. to unwrap the SDK of a domain in code that only knows the domain.
+// The OpenFeature API doesn't expose its providers, so the registry only knows the providers set through it.
+// It's safe for concurrent use.
+type Registry struct {
+	mu        sync.RWMutex
+	providers map[string]*Provider
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{providers: make(map[string]*Provider)}
+}
+
+// SetProvider binds the provider to the domain in the OpenFeature API and waits until it's ready.
+// The empty domain sets the default provider, like openfeature.SetProviderWithContextAndWait.
+func (r *Registry) SetProvider(ctx context.Context, domain string, p *Provider) error {
+	var err error
+	if domain == "" {
+		err = openfeature.SetProviderWithContextAndWait(ctx, p)
+	} else {
+		err = openfeature.SetNamedProviderWithContextAndWait(ctx, domain, p)
+	}
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[domain] = p
+	return nil
+}
+
+// FromDomain returns the provider bound to the domain with SetProvider.
+// Like OpenFeature clients, it falls back to the default provider if no provider is bound to the domain,
+// so FromDomain("") returns the default provider.
+// It returns false if no provider has been set for the domain nor as default.
+func (r *Registry) FromDomain(domain string) (*Provider, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if p, ok := r.providers[domain]; ok {
+		return p, true
+	}
+	p, ok := r.providers[""]
+	return p, ok
+}
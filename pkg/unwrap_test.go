@@ -0,0 +1,109 @@
+package provider
+
+import (
+	"context"
+	"maps"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	mockProvider "github.com/bucketeer-io/openfeature-go-server-sdk/test/mock/provider"
+)
+
+func TestUnwrap(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	extendedSDK := mockProvider.NewMockExtendedSDK(ctrl)
+	sdk, ok := newTestProvider(extendedSDK).Unwrap()
+	assert.True(t, ok)
+	assert.Same(t, extendedSDK, sdk)
+
+	sdk, ok = newTestProvider(mockProvider.NewMockBucketeerSDK(ctrl)).Unwrap()
+	assert.False(t, ok)
+	assert.Nil(t, sdk)
+}
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	defaultProvider := newTestProvider(mockProvider.NewMockBucketeerSDK(ctrl))
+	namedProvider := newTestProvider(mockProvider.NewMockBucketeerSDK(ctrl))
+	tests := []struct {
+		desc       string
+		providers  map[string]*Provider
+		domain     string
+		expected   *Provider
+		expectedOK bool
+	}{
+		{
+			desc:       "named provider",
+			providers:  map[string]*Provider{"": defaultProvider, "billing": namedProvider},
+			domain:     "billing",
+			expected:   namedProvider,
+			expectedOK: true,
+		},
+		{
+			desc:       "default provider for unknown domain",
+			providers:  map[string]*Provider{"": defaultProvider, "billing": namedProvider},
+			domain:     "search",
+			expected:   defaultProvider,
+			expectedOK: true,
+		},
+		{
+			desc:       "default provider for empty domain",
+			providers:  map[string]*Provider{"": defaultProvider},
+			domain:     "",
+			expected:   defaultProvider,
+			expectedOK: true,
+		},
+		{
+			desc:       "no provider",
+			providers:  map[string]*Provider{"billing": namedProvider},
+			domain:     "search",
+			expectedOK: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			registry := NewRegistry()
+			maps.Copy(registry.providers, test.providers)
+			p, ok := registry.FromDomain(test.domain)
+			assert.Equal(t, test.expectedOK, ok)
+			assert.Same(t, test.expected, p)
+		})
+	}
+}
+
+func TestRegistrySetProvider(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mockSDK := mockProvider.NewMockBucketeerSDK(ctrl)
+	mockSDK.EXPECT().Close(gomock.Any()).Return(nil).Times(1)
+	provider := newTestProvider(mockSDK)
+
+	// The domain is bound in the global OpenFeature API, so it's unique to this test and unbound afterwards.
+	// The cleanup runs before the controller checks the calls.
+	domain := "registry-" + t.Name()
+	t.Cleanup(func() {
+		assert.NoError(t, openfeature.SetNamedProviderAndWait(domain, openfeature.NoopProvider{}))
+		// Waits for the shutdown of the replaced provider
+		assert.NoError(t, provider.ShutdownWithContext(context.Background()))
+	})
+
+	registry := NewRegistry()
+	require.NoError(t, registry.SetProvider(context.Background(), domain, provider))
+
+	p, ok := registry.FromDomain(domain)
+	assert.True(t, ok)
+	assert.Same(t, provider, p)
+	assert.Equal(t, provider.Metadata(), openfeature.NamedProviderMetadata(domain))
+}
@@ -0,0 +1,210 @@
+package provider
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/model"
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+// UsageTracker counts the evaluations of each flag, to find the stale flags:
+// the flags missing in Bucketeer, the flags always served their default strategy, and the unused flags.
+// See WithUsageTracking.
+type UsageTracker struct {
+	now     func() time.Time
+	started time.Time
+	// featureID maps the flag keys to the Bucketeer feature IDs of the tag, see WithFlagAliases
+	featureID func(flag string) string
+
+	mu    sync.Mutex
+	flags map[string]*flagUsage
+}
+
+// flagUsage is the usage of a flag since the tracking started
+type flagUsage struct {
+	evaluations   uint64
+	reasons       map[string]uint64
+	errorCodes    map[string]uint64
+	lastReason    openfeature.Reason
+	lastErrorCode openfeature.ErrorCode
+	lastEvaluated time.Time
+	// lastFound is the last evaluation without a FLAG_NOT_FOUND error
+	lastFound time.Time
+	// lastTargeted is the last evaluation targeted by Bucketeer, see targeted
+	lastTargeted time.Time
+}
+
+// FlagUsage is the usage of a flag since the tracking started
+type FlagUsage struct {
+	Flag        string `json:"flag"`
+	Evaluations uint64 `json:"evaluations"`
+	// Reasons are the numbers of evaluations by reason
+	Reasons map[string]uint64 `json:"reasons"`
+	// ErrorCodes are the numbers of failed evaluations by error code
+	ErrorCodes    map[string]uint64 `json:"errorCodes,omitempty"`
+	LastReason    string            `json:"lastReason"`
+	LastErrorCode string            `json:"lastErrorCode,omitempty"`
+	LastEvaluated time.Time         `json:"lastEvaluated"`
+}
+
+// UsageReport lists the stale flags over a window.
+// The flags are the flag keys evaluated by the code, before WithFlagAliases is applied,
+// except the flags of the tag in Unused, which are Bucketeer feature IDs.
+type UsageReport struct {
+	// Since is the start of the window. It's never before TrackingSince.
+	Since time.Time `json:"since"`
+	// TrackingSince is when the tracking started
+	TrackingSince time.Time `json:"trackingSince"`
+	// Flags are the evaluated flags with their usage since the tracking started, sorted by flag
+	Flags []FlagUsage `json:"flags"`
+	// NotFound are the flags evaluated in the window, always with a FLAG_NOT_FOUND error:
+	// flags referenced in the code but missing in Bucketeer
+	NotFound []string `json:"notFound"`
+	// DefaultOnly are the flags evaluated in the window, never targeted by Bucketeer: always served
+	// with the Bucketeer DEFAULT reason, an error or an override, i.e. no user matched a target or a rule,
+	// and the flag is never disabled
+	DefaultOnly []string `json:"defaultOnly"`
+	// Unused are the flags not evaluated in the window: the flags evaluated before the window,
+	// and the flags of the tag never evaluated if the report is compared with the tag's flags
+	Unused []string `json:"unused"`
+	// ComparedWithTag is true if the flags of the tag are in Unused
+	ComparedWithTag bool `json:"comparedWithTag"`
+}
+
+// WithUsageTracking tracks the evaluations of each flag, see Provider.UsageReport.
+// Evaluations are counted by flag key, so the flag keys must not be generated from user input.
+func WithUsageTracking() Option {
+	return func(p *Provider) {
+		p.usage = newUsageTracker(time.Now)
+	}
+}
+
+func newUsageTracker(now func() time.Time) *UsageTracker {
+	return &UsageTracker{
+		now:       now,
+		started:   now(),
+		featureID: func(flag string) string { return flag },
+		flags:     make(map[string]*flagUsage),
+	}
+}
+
+// record counts an evaluation of the flag
+func (t *UsageTracker) record(flag string, detail openfeature.ProviderResolutionDetail) {
+	resolution := detail.ResolutionDetail()
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	usage, ok := t.flags[flag]
+	if !ok {
+		usage = &flagUsage{reasons: make(map[string]uint64), errorCodes: make(map[string]uint64)}
+		t.flags[flag] = usage
+	}
+	usage.evaluations++
+	usage.reasons[string(resolution.Reason)]++
+	if resolution.ErrorCode != "" {
+		usage.errorCodes[string(resolution.ErrorCode)]++
+	}
+	usage.lastReason = resolution.Reason
+	usage.lastErrorCode = resolution.ErrorCode
+	usage.lastEvaluated = now
+	if resolution.ErrorCode != openfeature.FlagNotFoundCode {
+		usage.lastFound = now
+		if targeted(resolution) {
+			usage.lastTargeted = now
+		}
+	}
+}
+
+// targeted returns true if Bucketeer served a target, a rule, a prerequisite or the off variation.
+// The Bucketeer reason of the flag metadata is used, since the OpenFeature reason can be changed
+// with WithReasonMapping. Errors are not targeted, even if WithErrorMapping doesn't fail them, nor overrides.
+func targeted(resolution openfeature.ResolutionDetail) bool {
+	if resolution.ErrorCode != "" {
+		return false
+	}
+	if _, ok := resolution.FlagMetadata[FlagMetadataOverride]; ok {
+		return false
+	}
+	reason, _ := resolution.FlagMetadata.GetString(FlagMetadataBucketeerReason)
+	return reason != "" &&
+		reason != string(model.EvaluationReasonDefault) &&
+		!strings.HasPrefix(reason, "ERROR")
+}
+
+// Report returns the stale flags over the window, i.e. the last window duration,
+// or since the tracking started if window isn't positive or the tracking started during the window.
+// The flags of the tag, e.g. listed with inspect.Inspector.Flags, are reported unused if they were not evaluated.
+// They are Bucketeer feature IDs, compared with the feature IDs the flag keys are mapped to.
+func (t *UsageTracker) Report(window time.Duration, tagFlags ...string) UsageReport {
+	now := t.now()
+	since := t.started
+	if window > 0 && now.Add(-window).After(since) {
+		since = now.Add(-window)
+	}
+	report := UsageReport{
+		Since:           since,
+		TrackingSince:   t.started,
+		Flags:           []FlagUsage{},
+		NotFound:        []string{},
+		DefaultOnly:     []string{},
+		Unused:          []string{},
+		ComparedWithTag: tagFlags != nil,
+	}
+
+	t.mu.Lock()
+	evaluated := make(map[string]bool, len(t.flags))
+	for flag, usage := range t.flags {
+		evaluated[t.featureID(flag)] = true
+		report.Flags = append(report.Flags, FlagUsage{
+			Flag:          flag,
+			Evaluations:   usage.evaluations,
+			Reasons:       maps.Clone(usage.reasons),
+			ErrorCodes:    maps.Clone(usage.errorCodes),
+			LastReason:    string(usage.lastReason),
+			LastErrorCode: string(usage.lastErrorCode),
+			LastEvaluated: usage.lastEvaluated,
+		})
+		switch {
+		case usage.lastEvaluated.Before(since):
+			report.Unused = append(report.Unused, flag)
+		case usage.lastFound.Before(since):
+			report.NotFound = append(report.NotFound, flag)
+		case usage.lastTargeted.Before(since):
+			report.DefaultOnly = append(report.DefaultOnly, flag)
+		}
+	}
+	for _, featureID := range tagFlags {
+		if !evaluated[featureID] {
+			report.Unused = append(report.Unused, featureID)
+		}
+	}
+	t.mu.Unlock()
+
+	slices.SortFunc(report.Flags, func(a, b FlagUsage) int {
+		return strings.Compare(a.Flag, b.Flag)
+	})
+	slices.Sort(report.NotFound)
+	slices.Sort(report.DefaultOnly)
+	slices.Sort(report.Unused)
+	report.Unused = slices.Compact(report.Unused)
+	return report
+}
+
+// UsageTracker returns the usage tracker of the provider, and false if WithUsageTracking is not set
+func (p *Provider) UsageTracker() (*UsageTracker, bool) {
+	return p.usage, p.usage != nil
+}
+
+// UsageReport returns the stale flags over the window, compared with the flags of the tag if any,
+// see UsageTracker.Report. It returns false if WithUsageTracking is not set.
+func (p *Provider) UsageReport(window time.Duration, tagFlags ...string) (UsageReport, bool) {
+	if p.usage == nil {
+		return UsageReport{}, false
+	}
+	return p.usage.Report(window, tagFlags...), true
+}
@@ -0,0 +1,186 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/model"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	mockProvider "github.com/bucketeer-io/openfeature-go-server-sdk/test/mock/provider"
+)
+
+func resolution(
+	reason openfeature.Reason,
+	bucketeerReason model.EvaluationReason,
+	err openfeature.ResolutionError,
+) openfeature.ProviderResolutionDetail {
+	return openfeature.ProviderResolutionDetail{
+		Reason:          reason,
+		ResolutionError: err,
+		FlagMetadata:    openfeature.FlagMetadata{FlagMetadataBucketeerReason: string(bucketeerReason)},
+	}
+}
+
+func TestUsageReport(t *testing.T) {
+	t.Parallel()
+	clock := &fakeClock{}
+	tracker := newUsageTracker(clock.Now)
+	notFound := openfeature.NewFlagNotFoundResolutionError("ERROR_FLAG_NOT_FOUND")
+
+	tracker.record("old", resolution(openfeature.TargetingMatchReason, model.EvaluationReasonRule, openfeature.ResolutionError{}))
+	tracker.record("renamed", resolution(openfeature.TargetingMatchReason, model.EvaluationReasonRule, openfeature.ResolutionError{}))
+	tracker.record("rolled-out", resolution(openfeature.TargetingMatchReason, model.EvaluationReasonRule, openfeature.ResolutionError{}))
+	clock.Advance(2 * time.Hour)
+	tracker.record("renamed", resolution(openfeature.ErrorReason, model.EvaluationReasonErrorFlagNotFound, notFound))
+	tracker.record("renamed", resolution(openfeature.ErrorReason, model.EvaluationReasonErrorFlagNotFound, notFound))
+	tracker.record("rolled-out", resolution(openfeature.DefaultReason, model.EvaluationReasonDefault, openfeature.ResolutionError{}))
+	tracker.record("targeted", resolution(openfeature.DefaultReason, model.EvaluationReasonDefault, openfeature.ResolutionError{}))
+	tracker.record("targeted", resolution(openfeature.TargetingMatchReason, model.EvaluationReasonRule, openfeature.ResolutionError{}))
+	tracker.record("disabled", resolution(openfeature.DisabledReason, model.EvaluationReasonOffVariation, openfeature.ResolutionError{}))
+	tracker.record("missing", resolution(openfeature.ErrorReason, model.EvaluationReasonErrorFlagNotFound, notFound))
+
+	report := tracker.Report(time.Hour)
+	assert.Equal(t, time.Unix(0, int64(time.Hour)), report.Since)
+	assert.Equal(t, time.Unix(0, 0), report.TrackingSince)
+	assert.Equal(t, []string{"missing", "renamed"}, report.NotFound)
+	assert.Equal(t, []string{"rolled-out"}, report.DefaultOnly)
+	assert.Equal(t, []string{"old"}, report.Unused)
+	assert.False(t, report.ComparedWithTag)
+	require.Len(t, report.Flags, 6)
+	assert.Equal(t, FlagUsage{
+		Flag:          "renamed",
+		Evaluations:   3,
+		Reasons:       map[string]uint64{"TARGETING_MATCH": 1, "ERROR": 2},
+		ErrorCodes:    map[string]uint64{"FLAG_NOT_FOUND": 2},
+		LastReason:    "ERROR",
+		LastErrorCode: "FLAG_NOT_FOUND",
+		LastEvaluated: time.Unix(0, int64(2*time.Hour)),
+	}, report.Flags[3])
+
+	// Without a window, the flags are reported since the tracking started
+	report = tracker.Report(0)
+	assert.Equal(t, report.TrackingSince, report.Since)
+	assert.Equal(t, []string{"missing"}, report.NotFound)
+	assert.Empty(t, report.DefaultOnly)
+	assert.Empty(t, report.Unused)
+
+	// The window can't start before the tracking
+	assert.Equal(t, report.TrackingSince, tracker.Report(24*time.Hour).Since)
+}
+
+func TestUsageReportDefaultOnly(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		desc        string
+		detail      openfeature.ProviderResolutionDetail
+		defaultOnly bool
+	}{
+		{
+			desc:   "rule",
+			detail: resolution(openfeature.TargetingMatchReason, model.EvaluationReasonRule, openfeature.ResolutionError{}),
+		},
+		{
+			desc:   "off variation",
+			detail: resolution(openfeature.DisabledReason, model.EvaluationReasonOffVariation, openfeature.ResolutionError{}),
+		},
+		{
+			desc:        "default strategy",
+			detail:      resolution(openfeature.DefaultReason, model.EvaluationReasonDefault, openfeature.ResolutionError{}),
+			defaultOnly: true,
+		},
+		{
+			desc:        "default strategy with a mapped reason",
+			detail:      resolution("FALLTHROUGH", model.EvaluationReasonDefault, openfeature.ResolutionError{}),
+			defaultOnly: true,
+		},
+		{
+			desc:   "rule with the reason mapped to DEFAULT",
+			detail: resolution(openfeature.DefaultReason, model.EvaluationReasonRule, openfeature.ResolutionError{}),
+		},
+		{
+			desc: "error",
+			detail: resolution(
+				openfeature.ErrorReason,
+				model.EvaluationReasonErrorException,
+				openfeature.NewGeneralResolutionError("ERROR_EXCEPTION"),
+			),
+			defaultOnly: true,
+		},
+		{
+			desc:        "error mapped to no error code",
+			detail:      resolution(openfeature.ErrorReason, model.EvaluationReasonErrorException, openfeature.ResolutionError{}),
+			defaultOnly: true,
+		},
+		{
+			desc: "override",
+			detail: openfeature.ProviderResolutionDetail{
+				Reason:       openfeature.StaticReason,
+				FlagMetadata: openfeature.FlagMetadata{FlagMetadataOverride: OverrideKillSwitch},
+			},
+			defaultOnly: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			tracker := newUsageTracker(time.Now)
+			tracker.record("flag", test.detail)
+
+			report := tracker.Report(time.Hour)
+			assert.Empty(t, report.NotFound)
+			if test.defaultOnly {
+				assert.Equal(t, []string{"flag"}, report.DefaultOnly)
+			} else {
+				assert.Empty(t, report.DefaultOnly)
+			}
+		})
+	}
+}
+
+func TestUsageReportWithTagFlags(t *testing.T) {
+	t.Parallel()
+	tracker := newUsageTracker(time.Now)
+	tracker.featureID = func(flag string) string { return "app-" + flag }
+	tracker.record("checkout", resolution(openfeature.TargetingMatchReason, model.EvaluationReasonRule, openfeature.ResolutionError{}))
+
+	report := tracker.Report(time.Hour, "app-checkout", "app-search", "checkout")
+	assert.True(t, report.ComparedWithTag)
+	assert.Equal(t, []string{"app-search", "checkout"}, report.Unused)
+}
+
+func TestProviderUsageReport(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	_, ok := newTestProvider(nil).UsageReport(time.Hour)
+	assert.False(t, ok)
+	_, ok = newTestProvider(nil).UsageTracker()
+	assert.False(t, ok)
+
+	mockSDK := mockProvider.NewMockBucketeerSDK(ctrl)
+	mockSDK.EXPECT().
+		StringVariationDetails(gomock.Any(), gomock.Any(), "app-checkout", "default").
+		Return(newStringEvaluation("default", model.EvaluationReasonErrorFlagNotFound)).
+		Times(1)
+	provider := newTestProvider(mockSDK, WithUsageTracking(), WithFlagPrefix("app-"))
+	provider.Overrides().Set("banner", "off")
+	evalCtx := openfeature.FlattenedContext{openfeature.TargetingKey: "test-user"}
+	provider.StringEvaluation(context.Background(), "checkout", "default", evalCtx)
+	provider.StringEvaluation(context.Background(), "banner", "default", evalCtx)
+
+	report, ok := provider.UsageReport(time.Hour, "app-checkout", "app-search")
+	require.True(t, ok)
+	assert.True(t, report.ComparedWithTag)
+	assert.Equal(t, []string{"checkout"}, report.NotFound)
+	assert.Equal(t, []string{"app-search"}, report.Unused)
+	require.Len(t, report.Flags, 2)
+	assert.Equal(t, "banner", report.Flags[0].Flag)
+	assert.Equal(t, map[string]uint64{"STATIC": 1}, report.Flags[0].Reasons)
+}
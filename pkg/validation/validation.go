@@ -0,0 +1,266 @@
+// Package validation provides an OpenFeature hook validating evaluation contexts against a schema,
+// to catch context bugs, e.g. a "Plan" attribute instead of "plan", before they mis-target users.
+//
+// The provider sends any attribute to Bucketeer, so a misspelled or mistyped attribute
+// silently makes the targeting rules fail. Run the hook in CI and staging with ActionFail,
+// and in production with ActionReport to log and count the invalid contexts.
+package validation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+var _ openfeature.Hook = (*Hook)(nil)
+
+// Type is the type of an attribute value
+type Type string
+
+// Attribute types
+const (
+	TypeString Type = "string"
+	// TypeNumber is any integer or floating-point value
+	TypeNumber Type = "number"
+	TypeBool   Type = "bool"
+	// TypeObject is a nested object, i.e. a map[string]any value
+	TypeObject Type = "object"
+)
+
+// Attribute is the schema of an attribute
+type Attribute struct {
+	Required bool
+	// Type is the type of the value. Any type is valid if it's empty.
+	Type Type
+	// Enum is the list of valid values, compared with the value formatted with fmt.Sprint
+	Enum []string
+	// Pattern is a regular expression matching the value formatted with fmt.Sprint
+	Pattern string
+}
+
+// Schema is the schema of the evaluation contexts.
+// The targeting key is the "targetingKey" attribute, which is always known.
+type Schema struct {
+	Attributes map[string]Attribute
+	// AllowUnknown accepts the attributes missing from Attributes.
+	// Unknown attributes are violations by default, to catch misspelled attributes.
+	AllowUnknown bool
+}
+
+// Violation is an attribute not matching the schema
+type Violation struct {
+	Attribute string
+	Message   string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s", v.Attribute, v.Message)
+}
+
+// Action is what the hook does when the evaluation context doesn't match the schema
+type Action int
+
+const (
+	// ActionReport passes the violations to the handler and counts them, but evaluates the flag.
+	// This is the default.
+	ActionReport Action = iota
+	// ActionFail also fails the evaluation with an INVALID_CONTEXT error, so the default value is returned.
+	ActionFail
+)
+
+// Option configures a Hook.
+type Option func(*Hook)
+
+// WithAction sets what the hook does when the evaluation context doesn't match the schema.
+func WithAction(action Action) Option {
+	return func(h *Hook) {
+		h.action = action
+	}
+}
+
+// WithViolationHandler sets a function called with the violations of each invalid evaluation context,
+// e.g. LogViolations.
+func WithViolationHandler(handler func(flag string, violations []Violation)) Option {
+	return func(h *Hook) {
+		h.handler = handler
+	}
+}
+
+// LogViolations returns a violation handler logging the violations as warnings
+func LogViolations(logger *slog.Logger) func(flag string, violations []Violation) {
+	return func(flag string, violations []Violation) {
+		messages := make([]string, 0, len(violations))
+		for _, v := range violations {
+			messages = append(messages, v.String())
+		}
+		logger.Warn("invalid evaluation context",
+			slog.String("flag", flag),
+			slog.Any("violations", messages),
+		)
+	}
+}
+
+// Hook is an OpenFeature hook validating the evaluation contexts before the evaluations.
+type Hook struct {
+	openfeature.UnimplementedHook
+	attributes   map[string]attribute
+	allowUnknown bool
+	action       Action
+	handler      func(flag string, violations []Violation)
+	invalid      atomic.Uint64
+}
+
+// attribute is an Attribute with its compiled pattern
+type attribute struct {
+	Attribute
+	pattern *regexp.Regexp
+}
+
+// NewHook creates a Hook validating the evaluation contexts against the schema.
+// It returns an error if a type or a pattern of the schema is invalid.
+func NewHook(schema Schema, opts ...Option) (*Hook, error) {
+	h := &Hook{
+		attributes:   make(map[string]attribute, len(schema.Attributes)),
+		allowUnknown: schema.AllowUnknown,
+	}
+	var errs []error
+	for name, attr := range schema.Attributes {
+		switch attr.Type {
+		case "", TypeString, TypeNumber, TypeBool, TypeObject:
+		default:
+			errs = append(errs, fmt.Errorf("attribute %q: unknown type %q", name, attr.Type))
+		}
+		compiled := attribute{Attribute: attr}
+		if attr.Pattern != "" {
+			var err error
+			if compiled.pattern, err = regexp.Compile(attr.Pattern); err != nil {
+				errs = append(errs, fmt.Errorf("attribute %q: %w", name, err))
+			}
+		}
+		h.attributes[name] = compiled
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h, nil
+}
+
+// Before validates the evaluation context.
+// With ActionFail, it returns an INVALID_CONTEXT resolution error listing the violations.
+func (h *Hook) Before(
+	ctx context.Context,
+	hookCtx openfeature.HookContext,
+	hints openfeature.HookHints,
+) (*openfeature.EvaluationContext, error) {
+	violations := h.Validate(hookCtx.EvaluationContext())
+	if len(violations) == 0 {
+		return nil, nil
+	}
+	h.invalid.Add(1)
+	if h.handler != nil {
+		h.handler(hookCtx.FlagKey(), violations)
+	}
+	if h.action != ActionFail {
+		return nil, nil
+	}
+	messages := make([]string, 0, len(violations))
+	for _, v := range violations {
+		messages = append(messages, v.String())
+	}
+	return nil, openfeature.NewInvalidContextResolutionError(strings.Join(messages, "; "))
+}
+
+// Invalid returns the number of evaluations with an invalid evaluation context
+func (h *Hook) Invalid() uint64 {
+	return h.invalid.Load()
+}
+
+// Validate returns the violations of the evaluation context, sorted by attribute
+func (h *Hook) Validate(evalCtx openfeature.EvaluationContext) []Violation {
+	attrs := evalCtx.Attributes()
+	if evalCtx.TargetingKey() != "" {
+		attrs[openfeature.TargetingKey] = evalCtx.TargetingKey()
+	}
+
+	var violations []Violation
+	for name, attr := range h.attributes {
+		value, ok := attrs[name]
+		if !ok || value == nil {
+			if attr.Required {
+				violations = append(violations, Violation{Attribute: name, Message: "required attribute is missing"})
+			}
+			continue
+		}
+		if msg := attr.validate(value); msg != "" {
+			violations = append(violations, Violation{Attribute: name, Message: msg})
+		}
+	}
+	if !h.allowUnknown {
+		for name := range attrs {
+			if _, ok := h.attributes[name]; ok || name == openfeature.TargetingKey {
+				continue
+			}
+			violations = append(violations, Violation{Attribute: name, Message: h.unknownMessage(name)})
+		}
+	}
+	sort.Slice(violations, func(i, j int) bool {
+		return violations[i].Attribute < violations[j].Attribute
+	})
+	return violations
+}
+
+// unknownMessage returns the violation message of an unknown attribute,
+// suggesting the attribute of the schema with the same name in another case
+func (h *Hook) unknownMessage(name string) string {
+	for known := range h.attributes {
+		if strings.EqualFold(known, name) {
+			return fmt.Sprintf("unknown attribute, did you mean %q?", known)
+		}
+	}
+	return "unknown attribute"
+}
+
+// validate returns the violation message of the value, or an empty string if it's valid
+func (a attribute) validate(value any) string {
+	if a.Type != "" && typeOf(value) != a.Type {
+		return fmt.Sprintf("expected type %s, got %T", a.Type, value)
+	}
+	s := fmt.Sprint(value)
+	if len(a.Enum) > 0 && !slices.Contains(a.Enum, s) {
+		return fmt.Sprintf("%q is not one of %s", s, strings.Join(a.Enum, ", "))
+	}
+	if a.pattern != nil && !a.pattern.MatchString(s) {
+		return fmt.Sprintf("%q doesn't match %s", s, a.pattern)
+	}
+	return ""
+}
+
+func typeOf(value any) Type {
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.String:
+		return TypeString
+	case reflect.Bool:
+		return TypeBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return TypeNumber
+	case reflect.Map:
+		if _, ok := value.(map[string]any); ok {
+			return TypeObject
+		}
+	}
+	return ""
+}
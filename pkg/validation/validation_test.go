@@ -0,0 +1,187 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSchema = Schema{
+	Attributes: map[string]Attribute{
+		openfeature.TargetingKey: {Required: true, Pattern: `^user-\d+$`},
+		"plan":                   {Required: true, Type: TypeString, Enum: []string{"free", "pro"}},
+		"age":                    {Type: TypeNumber},
+		"beta":                   {Type: TypeBool},
+		"org":                    {Type: TypeObject},
+	},
+}
+
+func newHookContext(flag string, evalCtx openfeature.EvaluationContext) openfeature.HookContext {
+	return openfeature.NewHookContext(
+		flag,
+		openfeature.Boolean,
+		false,
+		openfeature.NewClientMetadata(""),
+		openfeature.Metadata{Name: "Bucketeer"},
+		evalCtx,
+	)
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		desc     string
+		schema   Schema
+		evalCtx  openfeature.EvaluationContext
+		expected []Violation
+	}{
+		{
+			desc: "valid",
+			evalCtx: openfeature.NewEvaluationContext("user-1", map[string]any{
+				"plan": "pro",
+				"age":  30,
+				"beta": true,
+				"org":  map[string]any{"id": "org-1"},
+			}),
+		},
+		{
+			desc:    "missing required attributes",
+			evalCtx: openfeature.NewTargetlessEvaluationContext(nil),
+			expected: []Violation{
+				{Attribute: "plan", Message: "required attribute is missing"},
+				{Attribute: "targetingKey", Message: "required attribute is missing"},
+			},
+		},
+		{
+			desc: "misspelled attribute",
+			evalCtx: openfeature.NewEvaluationContext("user-1", map[string]any{
+				"Plan": "pro",
+				"team": "search",
+			}),
+			expected: []Violation{
+				{Attribute: "Plan", Message: `unknown attribute, did you mean "plan"?`},
+				{Attribute: "plan", Message: "required attribute is missing"},
+				{Attribute: "team", Message: "unknown attribute"},
+			},
+		},
+		{
+			desc: "unknown attribute allowed",
+			schema: Schema{
+				Attributes:   testSchema.Attributes,
+				AllowUnknown: true,
+			},
+			evalCtx: openfeature.NewEvaluationContext("user-1", map[string]any{
+				"plan": "pro",
+				"team": "search",
+			}),
+		},
+		{
+			desc: "invalid values",
+			evalCtx: openfeature.NewEvaluationContext("admin", map[string]any{
+				"plan": "enterprise",
+				"age":  "30",
+				"beta": 1,
+				"org":  "org-1",
+			}),
+			expected: []Violation{
+				{Attribute: "age", Message: "expected type number, got string"},
+				{Attribute: "beta", Message: "expected type bool, got int"},
+				{Attribute: "org", Message: "expected type object, got string"},
+				{Attribute: "plan", Message: `"enterprise" is not one of free, pro`},
+				{Attribute: "targetingKey", Message: `"admin" doesn't match ^user-\d+$`},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			schema := test.schema
+			if schema.Attributes == nil {
+				schema = testSchema
+			}
+			h, err := NewHook(schema)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, h.Validate(test.evalCtx))
+		})
+	}
+}
+
+func TestNewHookInvalidSchema(t *testing.T) {
+	t.Parallel()
+	_, err := NewHook(Schema{Attributes: map[string]Attribute{
+		"plan": {Type: "text"},
+		"id":   {Pattern: "("},
+	}})
+	assert.ErrorContains(t, err, `attribute "plan": unknown type "text"`)
+	assert.ErrorContains(t, err, `attribute "id": error parsing regexp`)
+}
+
+func TestHookBefore(t *testing.T) {
+	t.Parallel()
+	invalidCtx := openfeature.NewEvaluationContext("user-1", map[string]any{"Plan": "pro"})
+	validCtx := openfeature.NewEvaluationContext("user-1", map[string]any{"plan": "pro"})
+	tests := []struct {
+		desc          string
+		action        Action
+		evalCtx       openfeature.EvaluationContext
+		expectedError bool
+		expectedCalls int
+	}{
+		{desc: "valid", action: ActionFail, evalCtx: validCtx},
+		{desc: "reported", action: ActionReport, evalCtx: invalidCtx, expectedCalls: 1},
+		{desc: "failed", action: ActionFail, evalCtx: invalidCtx, expectedError: true, expectedCalls: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			var calls int
+			h, err := NewHook(testSchema,
+				WithAction(test.action),
+				WithViolationHandler(func(flag string, violations []Violation) {
+					calls++
+					assert.Equal(t, "flag", flag)
+					assert.NotEmpty(t, violations)
+				}),
+			)
+			require.NoError(t, err)
+
+			evalCtx, err := h.Before(context.Background(), newHookContext("flag", test.evalCtx), openfeature.HookHints{})
+			assert.Nil(t, evalCtx)
+			if test.expectedError {
+				var resolutionErr openfeature.ResolutionError
+				require.True(t, errors.As(err, &resolutionErr))
+				assert.EqualError(t, err,
+					`INVALID_CONTEXT: Plan: unknown attribute, did you mean "plan"?; plan: required attribute is missing`,
+				)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.expectedCalls, calls)
+			assert.Equal(t, uint64(test.expectedCalls), h.Invalid())
+		})
+	}
+}
+
+func TestHookWithClient(t *testing.T) {
+	t.Parallel()
+	h, err := NewHook(testSchema, WithAction(ActionFail))
+	require.NoError(t, err)
+	client := openfeature.NewClient(t.Name())
+	client.AddHooks(h)
+
+	value, err := client.BooleanValue(
+		context.Background(),
+		"flag",
+		true,
+		openfeature.NewEvaluationContext("user-1", map[string]any{"plan": "team"}),
+	)
+	assert.True(t, value)
+	assert.ErrorContains(t, err, "INVALID_CONTEXT")
+	assert.Equal(t, uint64(1), h.Invalid())
+}
@@ -0,0 +1,457 @@
+// Package conformance verifies the provider against the OpenFeature specification,
+// with scenarios modeled on the OpenFeature test harness features:
+// flag evaluation, context merging, error handling, metadata and lifecycle.
+//
+// The provider evaluates the flags with an in-memory SDK, so the suite doesn't need a Bucketeer backend.
+package conformance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bucketeer-io/go-server-sdk/pkg/bucketeer/model"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	provider "github.com/bucketeer-io/openfeature-go-server-sdk/pkg"
+	"github.com/bucketeer-io/openfeature-go-server-sdk/pkg/inmemory"
+)
+
+// flags are the flags of the test harness, with their default variation served unless a rule matches
+var flags = map[string]inmemory.Flag{
+	"boolean-flag": {
+		Version: 1,
+		Variations: []inmemory.Variation{
+			{ID: "boolean-on", Name: "on", Value: "true"},
+			{ID: "boolean-off", Name: "off", Value: "false"},
+		},
+		DefaultVariation: "boolean-on",
+		OffVariation:     "boolean-off",
+	},
+	"string-flag": {
+		Version: 1,
+		Variations: []inmemory.Variation{
+			{ID: "string-greeting", Name: "greeting", Value: "hi"},
+			{ID: "string-parting", Name: "parting", Value: "bye"},
+		},
+		DefaultVariation: "string-greeting",
+		OffVariation:     "string-parting",
+	},
+	"integer-flag": {
+		Version: 1,
+		Variations: []inmemory.Variation{
+			{ID: "integer-one", Name: "one", Value: "1"},
+			{ID: "integer-ten", Name: "ten", Value: "10"},
+		},
+		DefaultVariation: "integer-ten",
+		OffVariation:     "integer-one",
+	},
+	"float-flag": {
+		Version: 1,
+		Variations: []inmemory.Variation{
+			{ID: "float-tenth", Name: "tenth", Value: "0.1"},
+			{ID: "float-half", Name: "half", Value: "0.5"},
+		},
+		DefaultVariation: "float-half",
+		OffVariation:     "float-tenth",
+	},
+	"object-flag": {
+		Version: 1,
+		Variations: []inmemory.Variation{
+			{ID: "object-empty", Name: "empty", Value: "{}"},
+			{
+				ID:    "object-template",
+				Name:  "template",
+				Value: `{"showImages":true,"title":"Check out these pics!","imagesPerRow":100}`,
+			},
+		},
+		DefaultVariation: "object-template",
+		OffVariation:     "object-empty",
+	},
+	"disabled-flag": {
+		Version: 2,
+		Variations: []inmemory.Variation{
+			{ID: "disabled-on", Name: "on", Value: "true"},
+			{ID: "disabled-off", Name: "off", Value: "false"},
+		},
+		DefaultVariation: "disabled-on",
+		OffVariation:     "disabled-off",
+		Disabled:         true,
+	},
+	"context-aware": {
+		Version: 3,
+		Variations: []inmemory.Variation{
+			{ID: "context-internal", Name: "internal", Value: "INTERNAL"},
+			{ID: "context-external", Name: "external", Value: "EXTERNAL"},
+		},
+		DefaultVariation: "context-external",
+		OffVariation:     "context-external",
+		Targets:          map[string]string{"internal-user": "context-internal"},
+		Rules: []inmemory.Rule{
+			{Attribute: "customer", Values: []string{"false"}, Variation: "context-internal"},
+		},
+	},
+	"wrong-flag": {
+		Version: 1,
+		Variations: []inmemory.Variation{
+			{ID: "wrong-one", Name: "one", Value: "uno"},
+		},
+		DefaultVariation: "wrong-one",
+		OffVariation:     "wrong-one",
+	},
+}
+
+// Given a Bucketeer provider bound to a domain of its own
+func givenProvider(t *testing.T, opts ...provider.Option) (*openfeature.Client, *provider.Provider, *inmemory.SDK) {
+	t.Helper()
+	sdk := inmemory.New(flags)
+	p, err := provider.NewProviderFromSDK(sdk, opts...)
+	require.NoError(t, err)
+	require.NoError(t, openfeature.SetNamedProviderAndWait(t.Name(), p))
+	return openfeature.NewClient(t.Name()), p, sdk
+}
+
+// evaluate evaluates the flag with the client method of the default value type
+func evaluate(
+	ctx context.Context,
+	client *openfeature.Client,
+	flag string,
+	defaultValue any,
+	evalCtx openfeature.EvaluationContext,
+) openfeature.InterfaceEvaluationDetails {
+	switch v := defaultValue.(type) {
+	case bool:
+		d, _ := client.BooleanValueDetails(ctx, flag, v, evalCtx)
+		return openfeature.InterfaceEvaluationDetails{Value: d.Value, EvaluationDetails: d.EvaluationDetails}
+	case string:
+		d, _ := client.StringValueDetails(ctx, flag, v, evalCtx)
+		return openfeature.InterfaceEvaluationDetails{Value: d.Value, EvaluationDetails: d.EvaluationDetails}
+	case int64:
+		d, _ := client.IntValueDetails(ctx, flag, v, evalCtx)
+		return openfeature.InterfaceEvaluationDetails{Value: d.Value, EvaluationDetails: d.EvaluationDetails}
+	case float64:
+		d, _ := client.FloatValueDetails(ctx, flag, v, evalCtx)
+		return openfeature.InterfaceEvaluationDetails{Value: d.Value, EvaluationDetails: d.EvaluationDetails}
+	default:
+		d, _ := client.ObjectValueDetails(ctx, flag, v, evalCtx)
+		return d
+	}
+}
+
+// Feature: Flag evaluation
+func TestFlagEvaluation(t *testing.T) {
+	t.Parallel()
+	evalCtx := openfeature.NewEvaluationContext("user-1", nil)
+	tests := []struct {
+		desc            string
+		flag            string
+		defaultValue    any
+		expectedValue   any
+		expectedVariant string
+		expectedReason  openfeature.Reason
+	}{
+		{
+			desc:            "Resolves boolean value",
+			flag:            "boolean-flag",
+			defaultValue:    false,
+			expectedValue:   true,
+			expectedVariant: "on",
+			expectedReason:  openfeature.DefaultReason,
+		},
+		{
+			desc:            "Resolves string value",
+			flag:            "string-flag",
+			defaultValue:    "bye",
+			expectedValue:   "hi",
+			expectedVariant: "greeting",
+			expectedReason:  openfeature.DefaultReason,
+		},
+		{
+			desc:            "Resolves integer value",
+			flag:            "integer-flag",
+			defaultValue:    int64(1),
+			expectedValue:   int64(10),
+			expectedVariant: "ten",
+			expectedReason:  openfeature.DefaultReason,
+		},
+		{
+			desc:            "Resolves float value",
+			flag:            "float-flag",
+			defaultValue:    0.1,
+			expectedValue:   0.5,
+			expectedVariant: "half",
+			expectedReason:  openfeature.DefaultReason,
+		},
+		{
+			desc:         "Resolves object value",
+			flag:         "object-flag",
+			defaultValue: map[string]any{},
+			expectedValue: map[string]any{
+				"showImages":   true,
+				"title":        "Check out these pics!",
+				"imagesPerRow": float64(100),
+			},
+			expectedVariant: "template",
+			expectedReason:  openfeature.DefaultReason,
+		},
+		{
+			desc:            "Resolves disabled flag",
+			flag:            "disabled-flag",
+			defaultValue:    true,
+			expectedValue:   false,
+			expectedVariant: "off",
+			expectedReason:  openfeature.DisabledReason,
+		},
+	}
+
+	client, _, _ := givenProvider(t)
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			details := evaluate(context.Background(), client, test.flag, test.defaultValue, evalCtx)
+
+			assert.Equal(t, test.expectedValue, details.Value)
+			assert.Equal(t, test.expectedVariant, details.Variant)
+			assert.Equal(t, test.expectedReason, details.Reason)
+			assert.Empty(t, details.ErrorCode)
+			assert.Empty(t, details.ErrorMessage)
+		})
+	}
+}
+
+// Feature: Context-aware evaluation
+func TestContextAwareEvaluation(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		desc            string
+		evalCtx         openfeature.EvaluationContext
+		expectedValue   string
+		expectedVariant string
+		expectedReason  openfeature.Reason
+	}{
+		{
+			desc: "Resolves value matching a rule on an attribute",
+			evalCtx: openfeature.NewEvaluationContext("user-1", map[string]any{
+				"fn":       "Sulisław",
+				"ln":       "Świętopełk",
+				"age":      29,
+				"customer": false,
+			}),
+			expectedValue:   "INTERNAL",
+			expectedVariant: "internal",
+			expectedReason:  openfeature.TargetingMatchReason,
+		},
+		{
+			desc:            "Resolves value targeting the targeting key",
+			evalCtx:         openfeature.NewEvaluationContext("internal-user", nil),
+			expectedValue:   "INTERNAL",
+			expectedVariant: "internal",
+			expectedReason:  openfeature.TargetingMatchReason,
+		},
+		{
+			desc:            "Resolves default value when nothing matches",
+			evalCtx:         openfeature.NewEvaluationContext("user-1", map[string]any{"customer": true}),
+			expectedValue:   "EXTERNAL",
+			expectedVariant: "external",
+			expectedReason:  openfeature.DefaultReason,
+		},
+	}
+
+	client, _, _ := givenProvider(t)
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			details, err := client.StringValueDetails(context.Background(), "context-aware", "EXTERNAL", test.evalCtx)
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedValue, details.Value)
+			assert.Equal(t, test.expectedVariant, details.Variant)
+			assert.Equal(t, test.expectedReason, details.Reason)
+		})
+	}
+}
+
+// Feature: Evaluation context merging
+//
+// The API, transaction, client and invocation contexts are merged in this order of precedence,
+// so the merged attributes are used by the provider. Not parallel, since the API context is global.
+func TestContextMerging(t *testing.T) {
+	client, _, _ := givenProvider(t)
+	openfeature.SetEvaluationContext(openfeature.NewEvaluationContext("internal-user", map[string]any{
+		"customer": true,
+	}))
+	defer openfeature.SetEvaluationContext(openfeature.EvaluationContext{})
+
+	tests := []struct {
+		desc          string
+		transaction   *openfeature.EvaluationContext
+		client        *openfeature.EvaluationContext
+		invocation    openfeature.EvaluationContext
+		expectedValue string
+	}{
+		{
+			desc:          "API context is used",
+			expectedValue: "INTERNAL",
+		},
+		{
+			desc:          "Transaction context overrides API context",
+			transaction:   ptr(openfeature.NewEvaluationContext("user-1", nil)),
+			expectedValue: "EXTERNAL",
+		},
+		{
+			desc:          "Client context overrides transaction context",
+			transaction:   ptr(openfeature.NewEvaluationContext("user-1", nil)),
+			client:        ptr(openfeature.NewTargetlessEvaluationContext(map[string]any{"customer": false})),
+			expectedValue: "INTERNAL",
+		},
+		{
+			desc:          "Invocation context overrides client context",
+			client:        ptr(openfeature.NewEvaluationContext("user-1", map[string]any{"customer": false})),
+			invocation:    openfeature.NewTargetlessEvaluationContext(map[string]any{"customer": true}),
+			expectedValue: "EXTERNAL",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			ctx := context.Background()
+			if test.transaction != nil {
+				ctx = openfeature.WithTransactionContext(ctx, *test.transaction)
+			}
+			if test.client != nil {
+				client.SetEvaluationContext(*test.client)
+				defer client.SetEvaluationContext(openfeature.EvaluationContext{})
+			}
+
+			value, err := client.StringValue(ctx, "context-aware", "DEFAULT", test.invocation)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedValue, value)
+		})
+	}
+}
+
+// Feature: Error handling
+//
+// On error, the default value is returned with the ERROR reason and an error code.
+func TestErrorHandling(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		desc              string
+		flag              string
+		defaultValue      any
+		evalCtx           openfeature.EvaluationContext
+		failureReason     model.EvaluationReason
+		expectedErrorCode openfeature.ErrorCode
+	}{
+		{
+			desc:              "Flag not found",
+			flag:              "missing-flag",
+			defaultValue:      "uh-oh",
+			evalCtx:           openfeature.NewEvaluationContext("user-1", nil),
+			expectedErrorCode: openfeature.FlagNotFoundCode,
+		},
+		{
+			desc:              "Type error",
+			flag:              "wrong-flag",
+			defaultValue:      int64(13),
+			evalCtx:           openfeature.NewEvaluationContext("user-1", nil),
+			expectedErrorCode: openfeature.TypeMismatchCode,
+		},
+		{
+			desc:              "Targeting key missing",
+			flag:              "boolean-flag",
+			defaultValue:      false,
+			evalCtx:           openfeature.NewTargetlessEvaluationContext(map[string]any{"customer": true}),
+			expectedErrorCode: openfeature.TargetingKeyMissingCode,
+		},
+		{
+			desc:              "Empty evaluation context",
+			flag:              "float-flag",
+			defaultValue:      0.25,
+			evalCtx:           openfeature.EvaluationContext{},
+			expectedErrorCode: openfeature.TargetingKeyMissingCode,
+		},
+		{
+			desc:              "Bucketeer unavailable",
+			flag:              "object-flag",
+			defaultValue:      map[string]any{"fallback": true},
+			evalCtx:           openfeature.NewEvaluationContext("user-1", nil),
+			failureReason:     model.EvaluationReasonErrorException,
+			expectedErrorCode: openfeature.GeneralCode,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+			client, _, sdk := givenProvider(t)
+			sdk.SetFailureReason(test.failureReason)
+
+			details := evaluate(context.Background(), client, test.flag, test.defaultValue, test.evalCtx)
+
+			assert.Equal(t, test.defaultValue, details.Value)
+			assert.Equal(t, openfeature.ErrorReason, details.Reason)
+			assert.Equal(t, test.expectedErrorCode, details.ErrorCode)
+			assert.NotEmpty(t, details.ErrorMessage)
+			assert.Empty(t, details.Variant)
+		})
+	}
+}
+
+// Feature: Metadata
+func TestMetadata(t *testing.T) {
+	t.Parallel()
+	client, p, _ := givenProvider(t)
+
+	assert.Equal(t, "Bucketeer", p.Metadata().Name)
+
+	details, err := client.BooleanValueDetails(
+		context.Background(),
+		"disabled-flag",
+		true,
+		openfeature.NewEvaluationContext("user-1", nil),
+	)
+	assert.NoError(t, err)
+	version, err := details.FlagMetadata.GetInt(provider.FlagMetadataFeatureVersion)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), version)
+	variationID, err := details.FlagMetadata.GetString(provider.FlagMetadataVariationID)
+	assert.NoError(t, err)
+	assert.Equal(t, "disabled-off", variationID)
+	reason, err := details.FlagMetadata.GetString(provider.FlagMetadataBucketeerReason)
+	assert.NoError(t, err)
+	assert.Equal(t, string(model.EvaluationReasonOffVariation), reason)
+}
+
+// Feature: Provider lifecycle
+func TestLifecycle(t *testing.T) {
+	t.Parallel()
+	client, p, sdk := givenProvider(t)
+	evalCtx := openfeature.NewEvaluationContext("user-1", nil)
+
+	// Given a ready provider
+	assert.Equal(t, openfeature.ReadyState, client.State())
+	assert.Equal(t, openfeature.ReadyState, p.Status())
+	value, err := client.BooleanValue(context.Background(), "boolean-flag", false, evalCtx)
+	assert.NoError(t, err)
+	assert.True(t, value)
+
+	// When it's shut down
+	require.NoError(t, p.ShutdownWithContext(context.Background()))
+	assert.True(t, sdk.Closed())
+	assert.Equal(t, openfeature.NotReadyState, p.Status())
+
+	// Then evaluations return the default value with PROVIDER_NOT_READY
+	details := evaluate(context.Background(), client, "boolean-flag", false, evalCtx)
+	assert.Equal(t, false, details.Value)
+	assert.Equal(t, openfeature.ErrorReason, details.Reason)
+	assert.Equal(t, openfeature.ProviderNotReadyCode, details.ErrorCode)
+
+	// And it can't be set again
+	assert.Error(t, openfeature.SetNamedProviderAndWait(t.Name()+"/again", p))
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -125,3 +125,135 @@ func (mr *MockBucketeerSDKMockRecorder) StringVariationDetails(ctx, arg1, featur
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StringVariationDetails", reflect.TypeOf((*MockBucketeerSDK)(nil).StringVariationDetails), ctx, arg1, featureID, defaultValue)
 }
+
+// MockExtendedSDK is a mock of ExtendedSDK interface.
+type MockExtendedSDK struct {
+	ctrl     *gomock.Controller
+	recorder *MockExtendedSDKMockRecorder
+	isgomock struct{}
+}
+
+// MockExtendedSDKMockRecorder is the mock recorder for MockExtendedSDK.
+type MockExtendedSDKMockRecorder struct {
+	mock *MockExtendedSDK
+}
+
+// NewMockExtendedSDK creates a new mock instance.
+func NewMockExtendedSDK(ctrl *gomock.Controller) *MockExtendedSDK {
+	mock := &MockExtendedSDK{ctrl: ctrl}
+	mock.recorder = &MockExtendedSDKMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExtendedSDK) EXPECT() *MockExtendedSDKMockRecorder {
+	return m.recorder
+}
+
+// BoolVariationDetails mocks base method.
+func (m *MockExtendedSDK) BoolVariationDetails(ctx context.Context, arg1 *user.User, featureID string, defaultValue bool) model.BKTEvaluationDetails[bool] {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BoolVariationDetails", ctx, arg1, featureID, defaultValue)
+	ret0, _ := ret[0].(model.BKTEvaluationDetails[bool])
+	return ret0
+}
+
+// BoolVariationDetails indicates an expected call of BoolVariationDetails.
+func (mr *MockExtendedSDKMockRecorder) BoolVariationDetails(ctx, arg1, featureID, defaultValue any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BoolVariationDetails", reflect.TypeOf((*MockExtendedSDK)(nil).BoolVariationDetails), ctx, arg1, featureID, defaultValue)
+}
+
+// Close mocks base method.
+func (m *MockExtendedSDK) Close(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockExtendedSDKMockRecorder) Close(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockExtendedSDK)(nil).Close), ctx)
+}
+
+// Float64VariationDetails mocks base method.
+func (m *MockExtendedSDK) Float64VariationDetails(ctx context.Context, arg1 *user.User, featureID string, defaultValue float64) model.BKTEvaluationDetails[float64] {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Float64VariationDetails", ctx, arg1, featureID, defaultValue)
+	ret0, _ := ret[0].(model.BKTEvaluationDetails[float64])
+	return ret0
+}
+
+// Float64VariationDetails indicates an expected call of Float64VariationDetails.
+func (mr *MockExtendedSDKMockRecorder) Float64VariationDetails(ctx, arg1, featureID, defaultValue any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Float64VariationDetails", reflect.TypeOf((*MockExtendedSDK)(nil).Float64VariationDetails), ctx, arg1, featureID, defaultValue)
+}
+
+// Int64VariationDetails mocks base method.
+func (m *MockExtendedSDK) Int64VariationDetails(ctx context.Context, arg1 *user.User, featureID string, defaultValue int64) model.BKTEvaluationDetails[int64] {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Int64VariationDetails", ctx, arg1, featureID, defaultValue)
+	ret0, _ := ret[0].(model.BKTEvaluationDetails[int64])
+	return ret0
+}
+
+// Int64VariationDetails indicates an expected call of Int64VariationDetails.
+func (mr *MockExtendedSDKMockRecorder) Int64VariationDetails(ctx, arg1, featureID, defaultValue any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Int64VariationDetails", reflect.TypeOf((*MockExtendedSDK)(nil).Int64VariationDetails), ctx, arg1, featureID, defaultValue)
+}
+
+// ObjectVariationDetails mocks base method.
+func (m *MockExtendedSDK) ObjectVariationDetails(ctx context.Context, arg1 *user.User, featureID string, defaultValue any) model.BKTEvaluationDetails[any] {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ObjectVariationDetails", ctx, arg1, featureID, defaultValue)
+	ret0, _ := ret[0].(model.BKTEvaluationDetails[any])
+	return ret0
+}
+
+// ObjectVariationDetails indicates an expected call of ObjectVariationDetails.
+func (mr *MockExtendedSDKMockRecorder) ObjectVariationDetails(ctx, arg1, featureID, defaultValue any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ObjectVariationDetails", reflect.TypeOf((*MockExtendedSDK)(nil).ObjectVariationDetails), ctx, arg1, featureID, defaultValue)
+}
+
+// StringVariationDetails mocks base method.
+func (m *MockExtendedSDK) StringVariationDetails(ctx context.Context, arg1 *user.User, featureID, defaultValue string) model.BKTEvaluationDetails[string] {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StringVariationDetails", ctx, arg1, featureID, defaultValue)
+	ret0, _ := ret[0].(model.BKTEvaluationDetails[string])
+	return ret0
+}
+
+// StringVariationDetails indicates an expected call of StringVariationDetails.
+func (mr *MockExtendedSDKMockRecorder) StringVariationDetails(ctx, arg1, featureID, defaultValue any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StringVariationDetails", reflect.TypeOf((*MockExtendedSDK)(nil).StringVariationDetails), ctx, arg1, featureID, defaultValue)
+}
+
+// Track mocks base method.
+func (m *MockExtendedSDK) Track(ctx context.Context, arg1 *user.User, GoalID string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Track", ctx, arg1, GoalID)
+}
+
+// Track indicates an expected call of Track.
+func (mr *MockExtendedSDKMockRecorder) Track(ctx, arg1, GoalID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Track", reflect.TypeOf((*MockExtendedSDK)(nil).Track), ctx, arg1, GoalID)
+}
+
+// TrackValue mocks base method.
+func (m *MockExtendedSDK) TrackValue(ctx context.Context, arg1 *user.User, GoalID string, value float64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "TrackValue", ctx, arg1, GoalID, value)
+}
+
+// TrackValue indicates an expected call of TrackValue.
+func (mr *MockExtendedSDKMockRecorder) TrackValue(ctx, arg1, GoalID, value any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrackValue", reflect.TypeOf((*MockExtendedSDK)(nil).TrackValue), ctx, arg1, GoalID, value)
+}